@@ -0,0 +1,26 @@
+package app
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"time"
+)
+
+const defaultAccountDeletionGraceDays = 14
+
+// AccountDeletionGrace reads how long a deleted account can still be
+// restored from ACCOUNT_DELETION_GRACE_DAYS, defaulting to 14 days.
+func AccountDeletionGrace() time.Duration {
+	days := defaultAccountDeletionGraceDays
+	if v := os.Getenv("ACCOUNT_DELETION_GRACE_DAYS"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 0 {
+			log.Printf("Warning: invalid ACCOUNT_DELETION_GRACE_DAYS %q, using %d", v, defaultAccountDeletionGraceDays)
+		} else {
+			days = parsed
+		}
+	}
+
+	return time.Duration(days) * 24 * time.Hour
+}
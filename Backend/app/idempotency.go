@@ -0,0 +1,27 @@
+package app
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"time"
+)
+
+const defaultIdempotencyTTLHours = 24
+
+// IdempotencyTTL reads how long responses to requests sent with an
+// Idempotency-Key are kept for replay from IDEMPOTENCY_TTL_HOURS,
+// defaulting to 24 hours.
+func IdempotencyTTL() time.Duration {
+	hours := defaultIdempotencyTTLHours
+	if v := os.Getenv("IDEMPOTENCY_TTL_HOURS"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 1 {
+			log.Printf("Warning: invalid IDEMPOTENCY_TTL_HOURS %q, using %d", v, defaultIdempotencyTTLHours)
+		} else {
+			hours = parsed
+		}
+	}
+
+	return time.Duration(hours) * time.Hour
+}
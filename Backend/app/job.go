@@ -0,0 +1,29 @@
+package app
+
+import (
+	"log"
+	"time"
+)
+
+// StartJob runs job every interval in the background until the process
+// exits. A panicking run is logged and the job keeps its schedule.
+func StartJob(name string, interval time.Duration, job func()) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			runJob(name, job)
+		}
+	}()
+}
+
+func runJob(name string, job func()) {
+	defer func() {
+		if err := recover(); err != nil {
+			log.Printf("job %s failed: %v", name, err)
+		}
+	}()
+
+	job()
+}
@@ -0,0 +1,36 @@
+package app
+
+import (
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ryhnfhrza/Expense-Tracker/oidc"
+)
+
+// NewOIDCClient builds the single sign-on client from OIDC_ISSUER,
+// OIDC_CLIENT_ID, OIDC_CLIENT_SECRET, OIDC_REDIRECT_URL and the optional
+// space separated OIDC_SCOPES. It returns nil, leaving single sign-on off,
+// when OIDC_ISSUER is not set.
+func NewOIDCClient() *oidc.Client {
+	issuer := os.Getenv("OIDC_ISSUER")
+	if issuer == "" {
+		return nil
+	}
+
+	config := oidc.Config{
+		Issuer:       issuer,
+		ClientId:     os.Getenv("OIDC_CLIENT_ID"),
+		ClientSecret: os.Getenv("OIDC_CLIENT_SECRET"),
+		RedirectURL:  os.Getenv("OIDC_REDIRECT_URL"),
+		Scopes:       strings.Fields(os.Getenv("OIDC_SCOPES")),
+	}
+	if config.ClientId == "" || config.RedirectURL == "" {
+		log.Printf("Warning: OIDC_ISSUER is set without OIDC_CLIENT_ID or OIDC_REDIRECT_URL, single sign-on is off")
+		return nil
+	}
+
+	return oidc.NewClient(config, &http.Client{Timeout: 10 * time.Second})
+}
@@ -4,27 +4,119 @@ import (
 	"github.com/julienschmidt/httprouter"
 	"github.com/ryhnfhrza/Expense-Tracker/controller"
 	"github.com/ryhnfhrza/Expense-Tracker/middleware"
+	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
+	"github.com/ryhnfhrza/Expense-Tracker/repository"
+	"github.com/ryhnfhrza/Expense-Tracker/service"
 )
 
-func NewRouter(userController controller.UserController, categoryController controller.CategoryController, expenseController controller.ExpenseController) *httprouter.Router {
+func NewRouter(userController controller.UserController, ssoController controller.SsoController, categoryController controller.CategoryController, expenseController controller.ExpenseController, savedViewController controller.SavedViewController, categoryRuleController controller.CategoryRuleController, expenseImportController controller.ExpenseImportController, insightController controller.InsightController, recurringExpenseController controller.RecurringExpenseController, auditController controller.AuditController, trashController controller.TrashController, undoController controller.UndoController, syncController controller.SyncController, webhookController controller.WebhookController, accessTokenController controller.AccessTokenController, twoFactorController controller.TwoFactorController, dataExportController controller.DataExportController, restoreController controller.RestoreController, accessTokenService service.AccessTokenService, idempotencyRepository repository.IdempotencyRedisRepository) *httprouter.Router {
 	router := httprouter.New()
+	// auth takes a route's handler and the scopes a personal access token
+	// needs for it. Routes without scopes accept Login JWTs only.
+	auth := middleware.AuthMiddleware(accessTokenService)
+	// idempotent lets clients retry writes safely with an Idempotency-Key.
+	idempotent := middleware.IdempotencyMiddleware(idempotencyRepository, IdempotencyTTL())
 
 	router.POST("/api/register", userController.Register)
 	router.POST("/api/login", userController.Login)
+	router.POST("/api/login/2fa", userController.LoginTwoFactor)
 	router.POST("/api/verify-email", userController.VerifyEmail)
+	router.GET("/api/sso/authorize", ssoController.Authorize)
+	router.POST("/api/sso/callback", ssoController.Callback)
 
-	router.POST("/api/category", middleware.AuthMiddleware(categoryController.CreateCategory))
-	router.DELETE("/api/category/:categoryId", middleware.AuthMiddleware(categoryController.DeleteCategory))
-	router.GET("/api/category/:categoryId", middleware.AuthMiddleware(categoryController.FindCategoryById))
-	router.GET("/api/category", middleware.AuthMiddleware(categoryController.FindAllCategory))
+	router.POST("/api/category", auth(idempotent(categoryController.CreateCategory), domain.ScopeWriteCategories))
+	router.DELETE("/api/category/:categoryId", auth(idempotent(categoryController.DeleteCategory), domain.ScopeWriteCategories))
+	router.GET("/api/category/:categoryId", auth(categoryController.FindCategoryById, domain.ScopeReadCategories))
+	router.GET("/api/category", auth(categoryController.FindAllCategory, domain.ScopeReadCategories))
 
-	router.POST("/api/expense", middleware.AuthMiddleware(expenseController.CreateExpense))
-	router.PUT("/api/expense/:expenseId", middleware.AuthMiddleware(expenseController.UpdateExpense))
-	router.DELETE("/api/expense/:expenseId", middleware.AuthMiddleware(expenseController.DeleteExpense))
-	router.GET("/api/expense/:expenseId", middleware.AuthMiddleware(expenseController.FindExpenseById))
-	router.GET("/api/expense", middleware.AuthMiddleware(expenseController.FindAllExpense))
+	router.POST("/api/expense", auth(idempotent(expenseController.CreateExpense), domain.ScopeWriteExpenses))
+	router.POST("/api/expense/quick", auth(idempotent(expenseController.QuickAddExpense), domain.ScopeWriteExpenses))
+	router.POST("/api/expense/suggest-category", auth(expenseController.SuggestCategory, domain.ScopeReadExpenses))
+	router.PUT("/api/expense/:expenseId", auth(idempotent(expenseController.UpdateExpense), domain.ScopeWriteExpenses))
+	router.DELETE("/api/expense/:expenseId", auth(idempotent(expenseController.DeleteExpense), domain.ScopeWriteExpenses))
+	router.GET("/api/expense/:expenseId", auth(expenseController.FindExpenseById, domain.ScopeReadExpenses))
+	router.GET("/api/expense/:expenseId/history", auth(auditController.FindExpenseHistory, domain.ScopeReadExpenses))
+	router.GET("/api/expense", auth(expenseController.FindAllExpense, domain.ScopeReadExpenses))
 
-	router.GET("/api/expenses/summary/details", middleware.AuthMiddleware(expenseController.GetSummeryDetails))
+	router.GET("/api/expenses/summary/details", auth(expenseController.GetSummeryDetails, domain.ScopeReadExpenses))
+	router.GET("/api/expenses/search", auth(expenseController.SearchExpense, domain.ScopeReadExpenses))
+	router.POST("/api/expenses/import", auth(idempotent(expenseImportController.ImportStatement), domain.ScopeWriteExpenses))
+	router.POST("/api/expenses/bulk", auth(idempotent(expenseController.BulkExpense), domain.ScopeWriteExpenses))
+
+	router.POST("/api/views", auth(idempotent(savedViewController.CreateView), domain.ScopeWriteViews))
+	router.PUT("/api/views/:viewId", auth(idempotent(savedViewController.UpdateView), domain.ScopeWriteViews))
+	router.DELETE("/api/views/:viewId", auth(idempotent(savedViewController.DeleteView), domain.ScopeWriteViews))
+	router.GET("/api/views/:viewId", auth(savedViewController.FindViewById, domain.ScopeReadViews))
+	router.GET("/api/views", auth(savedViewController.FindAllView, domain.ScopeReadViews))
+
+	router.POST("/api/rules", auth(idempotent(categoryRuleController.CreateRule), domain.ScopeWriteRules))
+	router.POST("/api/rules/apply", auth(idempotent(categoryRuleController.ApplyRules), domain.ScopeWriteRules, domain.ScopeWriteExpenses))
+	router.PUT("/api/rules/:ruleId", auth(idempotent(categoryRuleController.UpdateRule), domain.ScopeWriteRules))
+	router.DELETE("/api/rules/:ruleId", auth(idempotent(categoryRuleController.DeleteRule), domain.ScopeWriteRules))
+	router.GET("/api/rules/:ruleId", auth(categoryRuleController.FindRuleById, domain.ScopeReadRules))
+	router.GET("/api/rules", auth(categoryRuleController.FindAllRule, domain.ScopeReadRules))
+
+	router.GET("/api/insights/anomalies", auth(insightController.FindAnomalies, domain.ScopeReadInsights))
+	router.GET("/api/insights/forecast", auth(insightController.Forecast, domain.ScopeReadInsights))
+	router.GET("/api/insights/subscriptions", auth(insightController.FindSubscriptions, domain.ScopeReadInsights))
+
+	router.POST("/api/recurring", auth(idempotent(recurringExpenseController.ConvertSubscription), domain.ScopeWriteRecurring))
+	router.DELETE("/api/recurring/:recurringId", auth(idempotent(recurringExpenseController.DeleteRecurring), domain.ScopeWriteRecurring))
+	router.GET("/api/recurring", auth(recurringExpenseController.FindAllRecurring, domain.ScopeReadRecurring))
+
+	router.GET("/api/activity", auth(auditController.FindActivity, domain.ScopeReadActivity))
+
+	router.GET("/api/trash", auth(trashController.FindTrash, domain.ScopeReadTrash))
+	router.DELETE("/api/trash", auth(idempotent(trashController.EmptyTrash), domain.ScopeWriteTrash))
+	router.POST("/api/trash/expenses/:expenseId/restore", auth(idempotent(trashController.RestoreExpense), domain.ScopeWriteTrash))
+	router.DELETE("/api/trash/expenses/:expenseId", auth(idempotent(trashController.PurgeExpense), domain.ScopeWriteTrash))
+	router.POST("/api/trash/categories/:categoryId/restore", auth(idempotent(trashController.RestoreCategory), domain.ScopeWriteTrash))
+	router.DELETE("/api/trash/categories/:categoryId", auth(idempotent(trashController.PurgeCategory), domain.ScopeWriteTrash))
+
+	router.POST("/api/undo/:token", auth(idempotent(undoController.Undo), domain.ScopeWriteExpenses, domain.ScopeWriteCategories))
+
+	router.GET("/api/sync", auth(syncController.FindChanges, domain.ScopeReadExpenses, domain.ScopeReadCategories))
+	router.POST("/api/sync", auth(idempotent(syncController.Push), domain.ScopeWriteExpenses, domain.ScopeWriteCategories))
+
+	// Not idempotent: a replayed response would keep the signing secret in
+	// Redis.
+	router.POST("/api/webhooks", auth(webhookController.CreateWebhook, domain.ScopeWriteWebhooks))
+	router.DELETE("/api/webhooks/:webhookId", auth(idempotent(webhookController.DeleteWebhook), domain.ScopeWriteWebhooks))
+	router.GET("/api/webhooks", auth(webhookController.FindAllWebhook, domain.ScopeReadWebhooks))
+	router.GET("/api/webhook-deliveries", auth(webhookController.FindAllDeliveries, domain.ScopeReadWebhooks))
+	router.POST("/api/webhook-deliveries/:deliveryId/retry", auth(idempotent(webhookController.RetryDelivery), domain.ScopeWriteWebhooks))
+
+	// Not idempotent: a replayed response would keep the plaintext token in
+	// Redis.
+	router.POST("/api/tokens", auth(accessTokenController.CreateAccessToken))
+	router.DELETE("/api/tokens/:tokenId", auth(idempotent(accessTokenController.DeleteAccessToken)))
+	router.GET("/api/tokens", auth(accessTokenController.FindAllAccessToken))
+
+	// Not idempotent: a replayed response would keep the secret and the
+	// recovery codes in Redis.
+	router.POST("/api/2fa/enroll", auth(twoFactorController.Enroll))
+	router.POST("/api/2fa/confirm", auth(twoFactorController.Confirm))
+	router.POST("/api/2fa/disable", auth(twoFactorController.Disable))
+
+	router.GET("/api/me", auth(userController.FindProfile))
+	router.PUT("/api/me", auth(idempotent(userController.UpdateProfile)))
+	router.DELETE("/api/me", auth(idempotent(userController.ScheduleDeletion)))
+	router.PUT("/api/me/password", auth(idempotent(userController.ChangePassword)))
+	router.POST("/api/me/email", auth(idempotent(userController.RequestEmailChange)))
+	router.POST("/api/me/email/verify", auth(idempotent(userController.VerifyEmailChange)))
+	router.POST("/api/me/deletion/cancel", auth(idempotent(userController.CancelDeletion)))
+	router.GET("/api/me/preferences", auth(userController.FindPreferences))
+	router.PUT("/api/me/preferences", auth(idempotent(userController.UpdatePreferences)))
+
+	router.POST("/api/exports", auth(idempotent(dataExportController.RequestExport)))
+	router.GET("/api/exports", auth(dataExportController.FindAllExport))
+	router.GET("/api/exports/:exportId", auth(dataExportController.FindExport))
+	// Signed links, opened without a token.
+	router.GET("/api/exports/:exportId/download", dataExportController.DownloadExport)
+
+	// Not idempotent: archives can be larger than the middleware buffers,
+	// and running a restore again leaves the account as the first run did.
+	router.POST("/api/restore", auth(restoreController.RestoreTakeout, domain.ScopeWriteExpenses, domain.ScopeWriteCategories))
 
 	return router
 }
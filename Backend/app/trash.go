@@ -0,0 +1,26 @@
+package app
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"time"
+)
+
+const defaultTrashRetentionDays = 30
+
+// TrashRetention reads how long deleted items stay in the trash from
+// TRASH_RETENTION_DAYS, defaulting to 30 days.
+func TrashRetention() time.Duration {
+	days := defaultTrashRetentionDays
+	if v := os.Getenv("TRASH_RETENTION_DAYS"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 1 {
+			log.Printf("Warning: invalid TRASH_RETENTION_DAYS %q, using %d", v, defaultTrashRetentionDays)
+		} else {
+			days = parsed
+		}
+	}
+
+	return time.Duration(days) * 24 * time.Hour
+}
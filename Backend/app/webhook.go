@@ -0,0 +1,48 @@
+package app
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+
+	"github.com/ryhnfhrza/Expense-Tracker/util"
+)
+
+// NewWebhookClient builds the client webhook deliveries are sent with.
+// Users choose the URLs, so it only connects to public addresses and does
+// not follow redirects. The address is checked when the connection is
+// made, after DNS resolution, so a host that later resolves to a private
+// address is refused too.
+func NewWebhookClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout: 5 * time.Second,
+		Control: func(network, address string, conn syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return err
+			}
+			if !util.IsPublicAddress(addrPort.Addr()) {
+				return fmt.Errorf("webhook address %s is not public", addrPort.Addr())
+			}
+			return nil
+		},
+	}
+
+	return &http.Client{
+		Timeout: 10 * time.Second,
+		// Proxy is left unset: a proxy would make the connection instead,
+		// skipping the address check.
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: 5 * time.Second,
+			MaxIdleConns:        10,
+			IdleConnTimeout:     90 * time.Second,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
@@ -0,0 +1,133 @@
+package classifier
+
+import (
+	"math"
+	"sort"
+)
+
+type classStats struct {
+	docs   int
+	total  int
+	tokens map[string]int
+}
+
+// NaiveBayes is a multinomial naive Bayes model with Laplace smoothing. It is
+// updated incrementally with Add and Remove, so an edited expense can be
+// moved from its old category to the new one without retraining.
+type NaiveBayes struct {
+	docs    int
+	classes map[int]*classStats
+	vocab   map[string]int
+}
+
+type Prediction struct {
+	CategoryId int
+	Confidence float64
+}
+
+func NewNaiveBayes() *NaiveBayes {
+	return &NaiveBayes{
+		classes: map[int]*classStats{},
+		vocab:   map[string]int{},
+	}
+}
+
+func (model *NaiveBayes) Docs() int {
+	return model.docs
+}
+
+func (model *NaiveBayes) Add(categoryId int, features []string) {
+	class, ok := model.classes[categoryId]
+	if !ok {
+		class = &classStats{tokens: map[string]int{}}
+		model.classes[categoryId] = class
+	}
+
+	model.docs++
+	class.docs++
+	for _, f := range features {
+		class.tokens[f]++
+		class.total++
+		model.vocab[f]++
+	}
+}
+
+// Remove undoes an earlier Add with the same arguments.
+func (model *NaiveBayes) Remove(categoryId int, features []string) {
+	class, ok := model.classes[categoryId]
+	if !ok || class.docs == 0 {
+		return
+	}
+
+	model.docs--
+	class.docs--
+	for _, f := range features {
+		if class.tokens[f] == 0 {
+			continue
+		}
+		class.tokens[f]--
+		class.total--
+		if class.tokens[f] == 0 {
+			delete(class.tokens, f)
+		}
+
+		model.vocab[f]--
+		if model.vocab[f] <= 0 {
+			delete(model.vocab, f)
+		}
+	}
+
+	if class.docs == 0 {
+		delete(model.classes, categoryId)
+	}
+}
+
+// Predict ranks every known category for the features. Confidences are the
+// posterior probabilities and sum to 1 over all categories; only the best
+// limit are returned. Features never seen in training are ignored.
+func (model *NaiveBayes) Predict(features []string, limit int) []Prediction {
+	if model.docs == 0 {
+		return []Prediction{}
+	}
+
+	vocabSize := float64(len(model.vocab))
+	scores := make([]Prediction, 0, len(model.classes))
+	maxScore := math.Inf(-1)
+	for categoryId, class := range model.classes {
+		score := math.Log(float64(class.docs) / float64(model.docs))
+		for _, f := range features {
+			if _, known := model.vocab[f]; !known {
+				continue
+			}
+			score += math.Log((float64(class.tokens[f]) + 1) / (float64(class.total) + vocabSize))
+		}
+
+		scores = append(scores, Prediction{CategoryId: categoryId, Confidence: score})
+		if score > maxScore {
+			maxScore = score
+		}
+	}
+
+	// Normalise the log scores with the log-sum-exp trick.
+	var sum float64
+	for i := range scores {
+		scores[i].Confidence = math.Exp(scores[i].Confidence - maxScore)
+		sum += scores[i].Confidence
+	}
+	for i := range scores {
+		scores[i].Confidence /= sum
+	}
+
+	sort.Slice(scores, func(i, j int) bool {
+		if scores[i].Confidence != scores[j].Confidence {
+			return scores[i].Confidence > scores[j].Confidence
+		}
+		return scores[i].CategoryId < scores[j].CategoryId
+	})
+
+	if limit > 0 && len(scores) > limit {
+		scores = scores[:limit]
+	}
+
+	return scores
+}
@@ -0,0 +1,138 @@
+package classifier
+
+import (
+	"sync"
+	"time"
+)
+
+// storeCapacity is how many users' models are kept in memory. Past it the
+// least recently used model is dropped and retrained when next needed.
+const storeCapacity = 1000
+
+// Store keeps one model per user in memory. A user's model is built lazily
+// from their history on first use (see Load); until then Learn and Forget
+// are no-ops because the full training run will pick the change up anyway.
+type Store struct {
+	mu       sync.Mutex
+	models   map[int]*storedModel
+	training map[int]*trainingRun
+}
+
+type storedModel struct {
+	model    *NaiveBayes
+	lastUsed time.Time
+}
+
+// trainingRun tracks the loads training a user's model. generation counts
+// the changes made meanwhile; a model trained before one of them is stale.
+type trainingRun struct {
+	loads      int
+	generation uint64
+}
+
+func NewStore() *Store {
+	return &Store{models: map[int]*storedModel{}, training: map[int]*trainingRun{}}
+}
+
+// Load returns the user's model, training it with train when it is not in
+// memory yet. train runs without the lock held. If the user's data changed
+// while it ran, the model is used for this call but not kept, as it may
+// have missed the change.
+func (store *Store) Load(userId int, train func() *NaiveBayes) *NaiveBayes {
+	store.mu.Lock()
+	if stored, ok := store.models[userId]; ok {
+		stored.lastUsed = time.Now()
+		store.mu.Unlock()
+		return stored.model
+	}
+	run, ok := store.training[userId]
+	if !ok {
+		run = &trainingRun{}
+		store.training[userId] = run
+	}
+	run.loads++
+	generation := run.generation
+	store.mu.Unlock()
+	// train may panic; the run is closed either way.
+	defer store.endTraining(userId, run)
+
+	model := train()
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	if stored, ok := store.models[userId]; ok {
+		stored.lastUsed = time.Now()
+		return stored.model
+	}
+	if run.generation != generation {
+		return model
+	}
+
+	store.models[userId] = &storedModel{model: model, lastUsed: time.Now()}
+	store.evict()
+	return model
+}
+
+func (store *Store) endTraining(userId int, run *trainingRun) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	run.loads--
+	if run.loads == 0 {
+		delete(store.training, userId)
+	}
+}
+
+// evict drops the least recently used models past storeCapacity.
+func (store *Store) evict() {
+	for len(store.models) > storeCapacity {
+		oldestId, oldest := 0, time.Time{}
+		for userId, stored := range store.models {
+			if oldest.IsZero() || stored.lastUsed.Before(oldest) {
+				oldestId, oldest = userId, stored.lastUsed
+			}
+		}
+		delete(store.models, oldestId)
+	}
+}
+
+// Predict runs model.Predict under the store lock so it does not race with
+// incremental updates.
+func (store *Store) Predict(model *NaiveBayes, features []string, limit int) []Prediction {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	return model.Predict(features, limit)
+}
+
+func (store *Store) Learn(userId, categoryId int, description string, amount float64) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	if stored, ok := store.models[userId]; ok {
+		stored.model.Add(categoryId, Features(description, amount))
+	}
+	store.changed(userId)
+}
+
+func (store *Store) Forget(userId, categoryId int, description string, amount float64) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	if stored, ok := store.models[userId]; ok {
+		stored.model.Remove(categoryId, Features(description, amount))
+	}
+	store.changed(userId)
+}
+
+// Reset drops the user's model so it is retrained from the database on the
+// next suggestion, for changes too broad to apply one by one.
+func (store *Store) Reset(userId int) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	delete(store.models, userId)
+	store.changed(userId)
+}
+
+// changed marks models being trained for the user as stale.
+func (store *Store) changed(userId int) {
+	if run, ok := store.training[userId]; ok {
+		run.generation++
+	}
+}
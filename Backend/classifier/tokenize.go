@@ -0,0 +1,44 @@
+// Package classifier learns which category a user files an expense under
+// from the description and amount of their past expenses.
+package classifier
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Features turns an expense into the tokens the model is trained on: the
+// lowercased words of the description plus one coarse amount bucket, so that
+// "kopi" for 25k and "kopi" beans for 250k can still lean different ways.
+func Features(description string, amount float64) []string {
+	words := strings.FieldsFunc(strings.ToLower(description), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	features := make([]string, 0, len(words)+1)
+	for _, w := range words {
+		if len([]rune(w)) < 2 || isNumber(w) {
+			continue
+		}
+		features = append(features, w)
+	}
+
+	if amount > 0 {
+		// Half-decade buckets: 10k-31k, 31k-100k, 100k-316k, ...
+		bucket := int(math.Floor(math.Log10(amount) * 2))
+		features = append(features, "#amount:"+strconv.Itoa(bucket))
+	}
+
+	return features
+}
+
+func isNumber(word string) bool {
+	for _, r := range word {
+		if !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
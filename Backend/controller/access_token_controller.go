@@ -0,0 +1,13 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+type AccessTokenController interface {
+	CreateAccessToken(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	DeleteAccessToken(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	FindAllAccessToken(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+}
@@ -0,0 +1,81 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/ryhnfhrza/Expense-Tracker/helper"
+	"github.com/ryhnfhrza/Expense-Tracker/model/web"
+	"github.com/ryhnfhrza/Expense-Tracker/service"
+)
+
+type accessTokenControllerImpl struct {
+	AccessTokenService service.AccessTokenService
+}
+
+func NewAccessTokenController(accessTokenService service.AccessTokenService) AccessTokenController {
+	return &accessTokenControllerImpl{
+		AccessTokenService: accessTokenService,
+	}
+}
+
+func (accessTokenController *accessTokenControllerImpl) CreateAccessToken(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	tokenRequest := web.AccessTokenCreateRequest{}
+	helper.ReadFromRequestBody(request, &tokenRequest)
+
+	userId, ok := helper.GetUserIDFromContext(request.Context())
+	if !ok {
+		http.Error(writer, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	tokenRequest.UserId = userId
+
+	tokenResponse := accessTokenController.AccessTokenService.CreateAccessToken(request.Context(), tokenRequest)
+
+	webResponse := web.WebResponse{
+		Code:   http.StatusCreated,
+		Status: "Created",
+		Data:   tokenResponse,
+	}
+
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (accessTokenController *accessTokenControllerImpl) DeleteAccessToken(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	tokenId, err := strconv.Atoi(params.ByName("tokenId"))
+	helper.PanicIfError(err)
+
+	userId, ok := helper.GetUserIDFromContext(request.Context())
+	if !ok {
+		http.Error(writer, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	accessTokenController.AccessTokenService.DeleteAccessToken(request.Context(), tokenId, userId)
+
+	webResponse := web.WebResponse{
+		Code:   http.StatusNoContent,
+		Status: "NO CONTENT",
+	}
+
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (accessTokenController *accessTokenControllerImpl) FindAllAccessToken(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	userId, ok := helper.GetUserIDFromContext(request.Context())
+	if !ok {
+		http.Error(writer, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	tokenResponses := accessTokenController.AccessTokenService.FindAllAccessToken(request.Context(), userId)
+
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   tokenResponses,
+	}
+
+	helper.WriteToResponseBody(writer, webResponse)
+}
@@ -0,0 +1,12 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+type AuditController interface {
+	FindExpenseHistory(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	FindActivity(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+}
@@ -0,0 +1,87 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/ryhnfhrza/Expense-Tracker/exception"
+	"github.com/ryhnfhrza/Expense-Tracker/helper"
+	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
+	"github.com/ryhnfhrza/Expense-Tracker/model/web"
+	"github.com/ryhnfhrza/Expense-Tracker/service"
+)
+
+type auditControllerImpl struct {
+	AuditService service.AuditService
+}
+
+func NewAuditController(auditService service.AuditService) AuditController {
+	return &auditControllerImpl{
+		AuditService: auditService,
+	}
+}
+
+func (auditController *auditControllerImpl) FindExpenseHistory(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	expenseIdString := params.ByName("expenseId")
+	expenseId, err := strconv.Atoi(expenseIdString)
+	helper.PanicIfError(err)
+
+	userId, ok := helper.GetUserIDFromContext(request.Context())
+	if !ok {
+		http.Error(writer, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	historyResponse := auditController.AuditService.FindExpenseHistory(request.Context(), expenseId, userId)
+
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   historyResponse,
+	}
+
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (auditController *auditControllerImpl) FindActivity(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	userId, ok := helper.GetUserIDFromContext(request.Context())
+	if !ok {
+		http.Error(writer, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	query := request.URL.Query()
+
+	var filter domain.AuditFilter
+	if v := query.Get("limit"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 1 {
+			panic(exception.NewBadRequest("invalid limit"))
+		}
+		filter.Limit = parsed
+	}
+	if v := query.Get("before_id"); v != "" {
+		parsed, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || parsed < 1 {
+			panic(exception.NewBadRequest("invalid before_id"))
+		}
+		filter.BeforeId = parsed
+	}
+	switch v := query.Get("entity_type"); v {
+	case "", domain.AuditEntityExpense, domain.AuditEntityCategory:
+		filter.EntityType = v
+	default:
+		panic(exception.NewBadRequest("invalid entity_type, expected expense or category"))
+	}
+
+	activityResponse := auditController.AuditService.FindActivity(request.Context(), userId, filter)
+
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   activityResponse,
+	}
+
+	helper.WriteToResponseBody(writer, webResponse)
+}
@@ -54,11 +54,12 @@ func (categoryController *categoryControllerImpl) DeleteCategory(writer http.Res
 		return
 	}
 
-	categoryController.CategoryService.DeleteCategory(request.Context(), categoryId, int(userId))
+	undoResponse := categoryController.CategoryService.DeleteCategory(request.Context(), categoryId, int(userId))
 
 	webResponse := web.WebResponse{
 		Code:   http.StatusNoContent,
 		Status: "NO CONTENT",
+		Data:   undoResponse,
 	}
 
 	helper.WriteToResponseBody(writer, webResponse)
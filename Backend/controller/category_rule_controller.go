@@ -0,0 +1,16 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+type CategoryRuleController interface {
+	CreateRule(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	UpdateRule(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	DeleteRule(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	FindRuleById(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	FindAllRule(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	ApplyRules(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+}
@@ -0,0 +1,152 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/ryhnfhrza/Expense-Tracker/helper"
+	"github.com/ryhnfhrza/Expense-Tracker/model/web"
+	"github.com/ryhnfhrza/Expense-Tracker/service"
+)
+
+type categoryRuleControllerImpl struct {
+	CategoryRuleService service.CategoryRuleService
+}
+
+func NewCategoryRuleController(categoryRuleService service.CategoryRuleService) CategoryRuleController {
+	return &categoryRuleControllerImpl{
+		CategoryRuleService: categoryRuleService,
+	}
+}
+
+func (ruleController *categoryRuleControllerImpl) CreateRule(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	ruleRequest := web.CategoryRuleRequest{}
+	helper.ReadFromRequestBody(request, &ruleRequest)
+
+	userId, ok := helper.GetUserIDFromContext(request.Context())
+	if !ok {
+		http.Error(writer, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	ruleRequest.UserId = userId
+
+	ruleResponse := ruleController.CategoryRuleService.CreateRule(request.Context(), ruleRequest)
+
+	webResponse := web.WebResponse{
+		Code:   http.StatusCreated,
+		Status: "Created",
+		Data:   ruleResponse,
+	}
+
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (ruleController *categoryRuleControllerImpl) UpdateRule(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	ruleRequest := web.CategoryRuleRequest{}
+	helper.ReadFromRequestBody(request, &ruleRequest)
+
+	ruleId, err := strconv.Atoi(params.ByName("ruleId"))
+	helper.PanicIfError(err)
+	ruleRequest.Id = ruleId
+
+	userId, ok := helper.GetUserIDFromContext(request.Context())
+	if !ok {
+		http.Error(writer, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	ruleRequest.UserId = userId
+
+	ruleResponse := ruleController.CategoryRuleService.UpdateRule(request.Context(), ruleRequest)
+
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   ruleResponse,
+	}
+
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (ruleController *categoryRuleControllerImpl) DeleteRule(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	ruleId, err := strconv.Atoi(params.ByName("ruleId"))
+	helper.PanicIfError(err)
+
+	userId, ok := helper.GetUserIDFromContext(request.Context())
+	if !ok {
+		http.Error(writer, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	ruleController.CategoryRuleService.DeleteRule(request.Context(), ruleId, userId)
+
+	webResponse := web.WebResponse{
+		Code:   http.StatusNoContent,
+		Status: "NO CONTENT",
+	}
+
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (ruleController *categoryRuleControllerImpl) FindRuleById(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	ruleId, err := strconv.Atoi(params.ByName("ruleId"))
+	helper.PanicIfError(err)
+
+	userId, ok := helper.GetUserIDFromContext(request.Context())
+	if !ok {
+		http.Error(writer, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	ruleResponse := ruleController.CategoryRuleService.FindRuleById(request.Context(), ruleId, userId)
+
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   ruleResponse,
+	}
+
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (ruleController *categoryRuleControllerImpl) FindAllRule(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	userId, ok := helper.GetUserIDFromContext(request.Context())
+	if !ok {
+		http.Error(writer, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	ruleResponses := ruleController.CategoryRuleService.FindAllRule(request.Context(), userId)
+
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   ruleResponses,
+	}
+
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (ruleController *categoryRuleControllerImpl) ApplyRules(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	applyRequest := web.CategoryRuleApplyRequest{}
+	if request.ContentLength != 0 {
+		helper.ReadFromRequestBody(request, &applyRequest)
+	}
+
+	userId, ok := helper.GetUserIDFromContext(request.Context())
+	if !ok {
+		http.Error(writer, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	applyRequest.UserId = userId
+
+	applyResponse := ruleController.CategoryRuleService.ApplyRules(request.Context(), applyRequest)
+
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   applyResponse,
+	}
+
+	helper.WriteToResponseBody(writer, webResponse)
+}
@@ -0,0 +1,14 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+type DataExportController interface {
+	RequestExport(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	FindExport(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	FindAllExport(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	DownloadExport(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+}
@@ -0,0 +1,98 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/ryhnfhrza/Expense-Tracker/helper"
+	"github.com/ryhnfhrza/Expense-Tracker/model/web"
+	"github.com/ryhnfhrza/Expense-Tracker/service"
+)
+
+type dataExportControllerImpl struct {
+	DataExportService service.DataExportService
+}
+
+func NewDataExportController(dataExportService service.DataExportService) DataExportController {
+	return &dataExportControllerImpl{
+		DataExportService: dataExportService,
+	}
+}
+
+func (dataExportController *dataExportControllerImpl) RequestExport(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	userId, ok := helper.GetUserIDFromContext(request.Context())
+	if !ok {
+		http.Error(writer, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	exportResponse := dataExportController.DataExportService.RequestExport(request.Context(), userId)
+
+	webResponse := web.WebResponse{
+		Code:   http.StatusAccepted,
+		Status: "ACCEPTED",
+		Data:   exportResponse,
+	}
+
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (dataExportController *dataExportControllerImpl) FindExport(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	exportId, err := strconv.ParseInt(params.ByName("exportId"), 10, 64)
+	helper.PanicIfError(err)
+
+	userId, ok := helper.GetUserIDFromContext(request.Context())
+	if !ok {
+		http.Error(writer, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	exportResponse := dataExportController.DataExportService.FindExport(request.Context(), exportId, userId)
+
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   exportResponse,
+	}
+
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (dataExportController *dataExportControllerImpl) FindAllExport(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	userId, ok := helper.GetUserIDFromContext(request.Context())
+	if !ok {
+		http.Error(writer, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	exportResponses := dataExportController.DataExportService.FindAllExport(request.Context(), userId)
+
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   exportResponses,
+	}
+
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+// DownloadExport needs no token: the signature in the link stands in for
+// it, so the link can be opened straight from the browser.
+func (dataExportController *dataExportControllerImpl) DownloadExport(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	exportId, err := strconv.ParseInt(params.ByName("exportId"), 10, 64)
+	helper.PanicIfError(err)
+
+	query := request.URL.Query()
+	expires, _ := strconv.ParseInt(query.Get("expires"), 10, 64)
+
+	archive := dataExportController.DataExportService.DownloadExport(request.Context(), exportId, expires, query.Get("signature"))
+
+	writer.Header().Set("Content-Type", "application/zip")
+	writer.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="expense-tracker-export-%d.zip"`, exportId))
+	writer.Header().Set("Content-Length", strconv.Itoa(len(archive)))
+	writer.Header().Set("Cache-Control", "private, no-store")
+	_, err = writer.Write(archive)
+	helper.PanicIfError(err)
+}
@@ -13,4 +13,8 @@ type ExpenseController interface {
 	FindExpenseById(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
 	FindAllExpense(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
 	GetSummeryDetails(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	QuickAddExpense(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	SuggestCategory(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	SearchExpense(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	BulkExpense(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
 }
@@ -7,17 +7,22 @@ import (
 	"github.com/julienschmidt/httprouter"
 	"github.com/ryhnfhrza/Expense-Tracker/exception"
 	"github.com/ryhnfhrza/Expense-Tracker/helper"
+	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
 	"github.com/ryhnfhrza/Expense-Tracker/model/web"
 	"github.com/ryhnfhrza/Expense-Tracker/service"
 )
 
 type expenseControllerImpl struct {
-	ExpenseService service.ExpenseService
+	ExpenseService   service.ExpenseService
+	SavedViewService service.SavedViewService
+	UserService      service.UserService
 }
 
-func NewExpenseController(expenseService service.ExpenseService) ExpenseController {
+func NewExpenseController(expenseService service.ExpenseService, savedViewService service.SavedViewService, userService service.UserService) ExpenseController {
 	return &expenseControllerImpl{
-		ExpenseService: expenseService,
+		ExpenseService:   expenseService,
+		SavedViewService: savedViewService,
+		UserService:      userService,
 	}
 }
 
@@ -61,7 +66,17 @@ func (expenseController *expenseControllerImpl) UpdateExpense(writer http.Respon
 
 	expenseUpdateRequest.UserId = userId
 
+	ifMatch := request.Header.Get("If-Match")
+	if ifMatch == "" {
+		panic(exception.NewPreconditionRequiredError("If-Match header is required, use the ETag from GET /api/expense/:expenseId"))
+	}
+	expenseUpdateRequest.Version, err = helper.ParseIfMatch(ifMatch)
+	if err != nil {
+		panic(exception.NewBadRequest(err.Error()))
+	}
+
 	expenseResponse := expenseController.ExpenseService.UpdateExpense(request.Context(), expenseUpdateRequest)
+	writer.Header().Set("ETag", helper.FormatETag(expenseResponse.Version))
 
 	webResponse := web.WebResponse{
 		Code:   http.StatusOK,
@@ -83,11 +98,12 @@ func (expenseController *expenseControllerImpl) DeleteExpense(writer http.Respon
 		return
 	}
 
-	expenseController.ExpenseService.DeleteExpense(request.Context(), expenseId, userId)
+	undoResponse := expenseController.ExpenseService.DeleteExpense(request.Context(), expenseId, userId)
 
 	webResponse := web.WebResponse{
 		Code:   http.StatusNoContent,
 		Status: "NO CONTENT",
+		Data:   undoResponse,
 	}
 
 	helper.WriteToResponseBody(writer, webResponse)
@@ -105,6 +121,7 @@ func (expenseController *expenseControllerImpl) FindExpenseById(writer http.Resp
 	}
 
 	expenseResponse := expenseController.ExpenseService.FindExpenseById(request.Context(), expenseId, userId)
+	writer.Header().Set("ETag", helper.FormatETag(expenseResponse.Version))
 
 	webResponse := web.WebResponse{
 		Code:   http.StatusOK,
@@ -122,7 +139,13 @@ func (expenseController *expenseControllerImpl) FindAllExpense(writer http.Respo
 		return
 	}
 
-	filter, err := helper.ParseExpenseFilter(request.URL.Query())
+	query := request.URL.Query()
+	query = expenseController.SavedViewService.ApplyView(request.Context(), userId, domain.SavedViewKindExpense, query.Get("view"), query)
+	if query.Get("tz") == "" {
+		query.Set("tz", expenseController.UserService.FindPreferences(request.Context(), userId).Timezone)
+	}
+
+	filter, err := helper.ParseExpenseFilter(query)
 	if err != nil {
 		panic(exception.NewBadRequest(err.Error()))
 	}
@@ -145,7 +168,10 @@ func (expenseController *expenseControllerImpl) GetSummeryDetails(writer http.Re
 		return
 	}
 
-	filter, err := helper.ParseSummaryFilter(request.URL.Query())
+	query := request.URL.Query()
+	query = expenseController.SavedViewService.ApplyView(request.Context(), userId, domain.SavedViewKindSummary, query.Get("view"), query)
+
+	filter, err := helper.ParseSummaryFilter(query)
 	if err != nil {
 		panic(exception.NewBadRequest(err.Error()))
 	}
@@ -162,3 +188,98 @@ func (expenseController *expenseControllerImpl) GetSummeryDetails(writer http.Re
 
 	helper.WriteToResponseBody(writer, webResponse)
 }
+
+func (expenseController *expenseControllerImpl) SearchExpense(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	userId, ok := helper.GetUserIDFromContext(request.Context())
+	if !ok {
+		http.Error(writer, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	query, err := helper.ParseExpenseSearchQuery(request.URL.Query())
+	if err != nil {
+		panic(exception.NewBadRequest(err.Error()))
+	}
+
+	searchResponse := expenseController.
+		ExpenseService.
+		SearchExpense(request.Context(), userId, query)
+
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   searchResponse,
+	}
+
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (expenseController *expenseControllerImpl) QuickAddExpense(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	quickAddRequest := web.ExpenseQuickAddRequest{}
+	helper.ReadFromRequestBody(request, &quickAddRequest)
+
+	userId, ok := helper.GetUserIDFromContext(request.Context())
+	if !ok {
+		http.Error(writer, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	quickAddRequest.UserId = userId
+
+	quickAddResponse := expenseController.ExpenseService.QuickAddExpense(request.Context(), quickAddRequest)
+
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   quickAddResponse,
+	}
+	if quickAddResponse.Expense != nil {
+		webResponse.Code = http.StatusCreated
+		webResponse.Status = "Created"
+	}
+
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (expenseController *expenseControllerImpl) SuggestCategory(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	suggestRequest := web.CategorySuggestRequest{}
+	helper.ReadFromRequestBody(request, &suggestRequest)
+
+	userId, ok := helper.GetUserIDFromContext(request.Context())
+	if !ok {
+		http.Error(writer, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	suggestRequest.UserId = userId
+
+	suggestResponse := expenseController.ExpenseService.SuggestCategory(request.Context(), suggestRequest)
+
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   suggestResponse,
+	}
+
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (expenseController *expenseControllerImpl) BulkExpense(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	bulkRequest := web.ExpenseBulkRequest{}
+	helper.ReadFromRequestBody(request, &bulkRequest)
+
+	userId, ok := helper.GetUserIDFromContext(request.Context())
+	if !ok {
+		http.Error(writer, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	bulkRequest.UserId = userId
+
+	bulkResponse := expenseController.ExpenseService.BulkExpense(request.Context(), bulkRequest)
+
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   bulkResponse,
+	}
+
+	helper.WriteToResponseBody(writer, webResponse)
+}
@@ -0,0 +1,91 @@
+package controller
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/ryhnfhrza/Expense-Tracker/exception"
+	"github.com/ryhnfhrza/Expense-Tracker/helper"
+	"github.com/ryhnfhrza/Expense-Tracker/model/web"
+	"github.com/ryhnfhrza/Expense-Tracker/service"
+)
+
+const maxStatementSize = 5 << 20
+
+type expenseImportControllerImpl struct {
+	ExpenseImportService service.ExpenseImportService
+}
+
+func NewExpenseImportController(expenseImportService service.ExpenseImportService) ExpenseImportController {
+	return &expenseImportControllerImpl{
+		ExpenseImportService: expenseImportService,
+	}
+}
+
+// ImportStatement accepts the statement either as a multipart upload in the
+// "file" field or as the raw request body.
+func (importController *expenseImportControllerImpl) ImportStatement(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	userId, ok := helper.GetUserIDFromContext(request.Context())
+	if !ok {
+		http.Error(writer, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	query := request.URL.Query()
+	importRequest := web.StatementImportRequest{
+		Format: strings.ToLower(query.Get("format")),
+		DryRun: query.Get("dry_run") == "true",
+		UserId: userId,
+	}
+
+	if v := query.Get("category_id"); v != "" {
+		categoryId, err := strconv.Atoi(v)
+		if err != nil || categoryId <= 0 {
+			panic(exception.NewBadRequest("invalid category_id"))
+		}
+		importRequest.CategoryId = categoryId
+	}
+
+	for _, v := range query["confirm"] {
+		for _, id := range strings.Split(v, ",") {
+			if id = strings.TrimSpace(id); id != "" {
+				importRequest.Confirm = append(importRequest.Confirm, id)
+			}
+		}
+	}
+
+	request.Body = http.MaxBytesReader(writer, request.Body, maxStatementSize)
+
+	var body io.Reader = request.Body
+	if strings.HasPrefix(request.Header.Get("Content-Type"), "multipart/form-data") {
+		file, _, err := request.FormFile("file")
+		if err != nil {
+			panic(exception.NewBadRequest("missing statement file in field \"file\""))
+		}
+		defer file.Close()
+		body = file
+	}
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		panic(exception.NewBadRequest("statement file is too large or unreadable"))
+	}
+	importRequest.Data = data
+
+	importResponse := importController.ExpenseImportService.ImportStatement(request.Context(), importRequest)
+
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   importResponse,
+	}
+	if importResponse.Imported > 0 {
+		webResponse.Code = http.StatusCreated
+		webResponse.Status = "Created"
+	}
+
+	helper.WriteToResponseBody(writer, webResponse)
+}
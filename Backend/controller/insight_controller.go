@@ -0,0 +1,13 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+type InsightController interface {
+	FindAnomalies(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	Forecast(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	FindSubscriptions(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+}
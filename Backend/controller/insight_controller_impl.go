@@ -0,0 +1,108 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/ryhnfhrza/Expense-Tracker/exception"
+	"github.com/ryhnfhrza/Expense-Tracker/helper"
+	"github.com/ryhnfhrza/Expense-Tracker/model/web"
+	"github.com/ryhnfhrza/Expense-Tracker/service"
+)
+
+type insightControllerImpl struct {
+	InsightService service.InsightService
+	UserService    service.UserService
+}
+
+func NewInsightController(insightService service.InsightService, userService service.UserService) InsightController {
+	return &insightControllerImpl{
+		InsightService: insightService,
+		UserService:    userService,
+	}
+}
+
+func (insightController *insightControllerImpl) FindAnomalies(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	userId, ok := helper.GetUserIDFromContext(request.Context())
+	if !ok {
+		http.Error(writer, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	days := 30
+	if v := request.URL.Query().Get("days"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 1 || parsed > 365 {
+			panic(exception.NewBadRequest("invalid days, expected 1-365"))
+		}
+		days = parsed
+	}
+
+	anomalyResponse := insightController.InsightService.FindAnomalies(request.Context(), userId, days)
+
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   anomalyResponse,
+	}
+
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (insightController *insightControllerImpl) Forecast(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	userId, ok := helper.GetUserIDFromContext(request.Context())
+	if !ok {
+		http.Error(writer, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	query := request.URL.Query()
+
+	months := 1
+	if v := query.Get("months"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 0 || parsed > 6 {
+			panic(exception.NewBadRequest("invalid months, expected 0-6"))
+		}
+		months = parsed
+	}
+
+	tz := query.Get("tz")
+	if tz == "" {
+		tz = insightController.UserService.FindPreferences(request.Context(), userId).Timezone
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		panic(exception.NewBadRequest("invalid tz"))
+	}
+
+	forecastResponse := insightController.InsightService.Forecast(request.Context(), userId, months, loc)
+
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   forecastResponse,
+	}
+
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (insightController *insightControllerImpl) FindSubscriptions(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	userId, ok := helper.GetUserIDFromContext(request.Context())
+	if !ok {
+		http.Error(writer, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	subscriptionResponse := insightController.InsightService.FindSubscriptions(request.Context(), userId)
+
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   subscriptionResponse,
+	}
+
+	helper.WriteToResponseBody(writer, webResponse)
+}
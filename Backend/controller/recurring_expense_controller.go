@@ -0,0 +1,13 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+type RecurringExpenseController interface {
+	ConvertSubscription(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	DeleteRecurring(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	FindAllRecurring(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+}
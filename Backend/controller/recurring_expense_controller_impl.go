@@ -0,0 +1,81 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/ryhnfhrza/Expense-Tracker/helper"
+	"github.com/ryhnfhrza/Expense-Tracker/model/web"
+	"github.com/ryhnfhrza/Expense-Tracker/service"
+)
+
+type recurringExpenseControllerImpl struct {
+	RecurringExpenseService service.RecurringExpenseService
+}
+
+func NewRecurringExpenseController(recurringExpenseService service.RecurringExpenseService) RecurringExpenseController {
+	return &recurringExpenseControllerImpl{
+		RecurringExpenseService: recurringExpenseService,
+	}
+}
+
+func (recurringController *recurringExpenseControllerImpl) ConvertSubscription(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	createRequest := web.RecurringExpenseCreateRequest{}
+	helper.ReadFromRequestBody(request, &createRequest)
+
+	userId, ok := helper.GetUserIDFromContext(request.Context())
+	if !ok {
+		http.Error(writer, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	createRequest.UserId = userId
+
+	recurringResponse := recurringController.RecurringExpenseService.ConvertSubscription(request.Context(), createRequest)
+
+	webResponse := web.WebResponse{
+		Code:   http.StatusCreated,
+		Status: "Created",
+		Data:   recurringResponse,
+	}
+
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (recurringController *recurringExpenseControllerImpl) DeleteRecurring(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	recurringId, err := strconv.Atoi(params.ByName("recurringId"))
+	helper.PanicIfError(err)
+
+	userId, ok := helper.GetUserIDFromContext(request.Context())
+	if !ok {
+		http.Error(writer, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	recurringController.RecurringExpenseService.DeleteRecurring(request.Context(), recurringId, userId)
+
+	webResponse := web.WebResponse{
+		Code:   http.StatusNoContent,
+		Status: "NO CONTENT",
+	}
+
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (recurringController *recurringExpenseControllerImpl) FindAllRecurring(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	userId, ok := helper.GetUserIDFromContext(request.Context())
+	if !ok {
+		http.Error(writer, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	recurringResponses := recurringController.RecurringExpenseService.FindAllRecurring(request.Context(), userId)
+
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   recurringResponses,
+	}
+
+	helper.WriteToResponseBody(writer, webResponse)
+}
@@ -0,0 +1,72 @@
+package controller
+
+import (
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/ryhnfhrza/Expense-Tracker/exception"
+	"github.com/ryhnfhrza/Expense-Tracker/helper"
+	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
+	"github.com/ryhnfhrza/Expense-Tracker/model/web"
+	"github.com/ryhnfhrza/Expense-Tracker/service"
+)
+
+const maxTakeoutSize = 50 << 20
+
+type restoreControllerImpl struct {
+	RestoreService service.RestoreService
+}
+
+func NewRestoreController(restoreService service.RestoreService) RestoreController {
+	return &restoreControllerImpl{
+		RestoreService: restoreService,
+	}
+}
+
+// RestoreTakeout accepts the archive either as a multipart upload in the
+// "file" field or as the raw request body.
+func (restoreController *restoreControllerImpl) RestoreTakeout(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	userId, ok := helper.GetUserIDFromContext(request.Context())
+	if !ok {
+		http.Error(writer, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	restoreRequest := web.TakeoutRestoreRequest{
+		Mode:   strings.ToLower(request.URL.Query().Get("mode")),
+		UserId: userId,
+	}
+	if restoreRequest.Mode == "" {
+		restoreRequest.Mode = domain.RestoreModeMerge
+	}
+
+	request.Body = http.MaxBytesReader(writer, request.Body, maxTakeoutSize)
+
+	var body io.Reader = request.Body
+	if strings.HasPrefix(request.Header.Get("Content-Type"), "multipart/form-data") {
+		file, _, err := request.FormFile("file")
+		if err != nil {
+			panic(exception.NewBadRequest("missing archive in field \"file\""))
+		}
+		defer file.Close()
+		body = file
+	}
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		panic(exception.NewBadRequest("archive is too large or unreadable"))
+	}
+	restoreRequest.Data = data
+
+	restoreResponse := restoreController.RestoreService.RestoreTakeout(request.Context(), restoreRequest)
+
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   restoreResponse,
+	}
+
+	helper.WriteToResponseBody(writer, webResponse)
+}
@@ -0,0 +1,15 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+type SavedViewController interface {
+	CreateView(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	UpdateView(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	DeleteView(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	FindViewById(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	FindAllView(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+}
@@ -0,0 +1,128 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/ryhnfhrza/Expense-Tracker/helper"
+	"github.com/ryhnfhrza/Expense-Tracker/model/web"
+	"github.com/ryhnfhrza/Expense-Tracker/service"
+)
+
+type savedViewControllerImpl struct {
+	SavedViewService service.SavedViewService
+}
+
+func NewSavedViewController(savedViewService service.SavedViewService) SavedViewController {
+	return &savedViewControllerImpl{
+		SavedViewService: savedViewService,
+	}
+}
+
+func (savedViewController *savedViewControllerImpl) CreateView(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	viewRequest := web.SavedViewRequest{}
+	helper.ReadFromRequestBody(request, &viewRequest)
+
+	userId, ok := helper.GetUserIDFromContext(request.Context())
+	if !ok {
+		http.Error(writer, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	viewRequest.UserId = userId
+
+	viewResponse := savedViewController.SavedViewService.CreateView(request.Context(), viewRequest)
+
+	webResponse := web.WebResponse{
+		Code:   http.StatusCreated,
+		Status: "Created",
+		Data:   viewResponse,
+	}
+
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (savedViewController *savedViewControllerImpl) UpdateView(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	viewRequest := web.SavedViewRequest{}
+	helper.ReadFromRequestBody(request, &viewRequest)
+
+	viewId, err := strconv.Atoi(params.ByName("viewId"))
+	helper.PanicIfError(err)
+	viewRequest.Id = viewId
+
+	userId, ok := helper.GetUserIDFromContext(request.Context())
+	if !ok {
+		http.Error(writer, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	viewRequest.UserId = userId
+
+	viewResponse := savedViewController.SavedViewService.UpdateView(request.Context(), viewRequest)
+
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   viewResponse,
+	}
+
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (savedViewController *savedViewControllerImpl) DeleteView(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	viewId, err := strconv.Atoi(params.ByName("viewId"))
+	helper.PanicIfError(err)
+
+	userId, ok := helper.GetUserIDFromContext(request.Context())
+	if !ok {
+		http.Error(writer, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	savedViewController.SavedViewService.DeleteView(request.Context(), viewId, userId)
+
+	webResponse := web.WebResponse{
+		Code:   http.StatusNoContent,
+		Status: "NO CONTENT",
+	}
+
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (savedViewController *savedViewControllerImpl) FindViewById(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	viewId, err := strconv.Atoi(params.ByName("viewId"))
+	helper.PanicIfError(err)
+
+	userId, ok := helper.GetUserIDFromContext(request.Context())
+	if !ok {
+		http.Error(writer, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	viewResponse := savedViewController.SavedViewService.FindViewById(request.Context(), viewId, userId)
+
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   viewResponse,
+	}
+
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (savedViewController *savedViewControllerImpl) FindAllView(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	userId, ok := helper.GetUserIDFromContext(request.Context())
+	if !ok {
+		http.Error(writer, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	viewResponses := savedViewController.SavedViewService.FindAllView(request.Context(), userId)
+
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   viewResponses,
+	}
+
+	helper.WriteToResponseBody(writer, webResponse)
+}
@@ -0,0 +1,12 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+type SsoController interface {
+	Authorize(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	Callback(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+}
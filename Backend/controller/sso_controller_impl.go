@@ -0,0 +1,47 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/ryhnfhrza/Expense-Tracker/helper"
+	"github.com/ryhnfhrza/Expense-Tracker/model/web"
+	"github.com/ryhnfhrza/Expense-Tracker/service"
+)
+
+type ssoControllerImpl struct {
+	SsoService service.SsoService
+}
+
+func NewSsoController(ssoService service.SsoService) SsoController {
+	return &ssoControllerImpl{
+		SsoService: ssoService,
+	}
+}
+
+func (controller *ssoControllerImpl) Authorize(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	authorizeResponse := controller.SsoService.Authorize(request.Context())
+
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   authorizeResponse,
+	}
+
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (controller *ssoControllerImpl) Callback(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	callbackRequest := web.SsoCallbackRequest{}
+	helper.ReadFromRequestBody(request, &callbackRequest)
+
+	userResponse := controller.SsoService.Callback(request.Context(), callbackRequest)
+
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   userResponse,
+	}
+
+	helper.WriteToResponseBody(writer, webResponse)
+}
@@ -0,0 +1,12 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+type SyncController interface {
+	FindChanges(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	Push(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+}
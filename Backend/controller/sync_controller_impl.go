@@ -0,0 +1,83 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/ryhnfhrza/Expense-Tracker/exception"
+	"github.com/ryhnfhrza/Expense-Tracker/helper"
+	"github.com/ryhnfhrza/Expense-Tracker/model/web"
+	"github.com/ryhnfhrza/Expense-Tracker/service"
+)
+
+type syncControllerImpl struct {
+	SyncService service.SyncService
+}
+
+func NewSyncController(syncService service.SyncService) SyncController {
+	return &syncControllerImpl{
+		SyncService: syncService,
+	}
+}
+
+func (syncController *syncControllerImpl) FindChanges(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	userId, ok := helper.GetUserIDFromContext(request.Context())
+	if !ok {
+		http.Error(writer, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	query := request.URL.Query()
+
+	var since int64
+	if v := query.Get("since"); v != "" {
+		parsed, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || parsed < 0 {
+			panic(exception.NewBadRequest("invalid since token"))
+		}
+		since = parsed
+	}
+
+	var limit int
+	if v := query.Get("limit"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 1 {
+			panic(exception.NewBadRequest("invalid limit"))
+		}
+		limit = parsed
+	}
+
+	feedResponse := syncController.SyncService.FindChanges(request.Context(), userId, since, limit)
+
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   feedResponse,
+	}
+
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (syncController *syncControllerImpl) Push(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	pushRequest := web.SyncPushRequest{}
+	helper.ReadFromRequestBody(request, &pushRequest)
+
+	userId, ok := helper.GetUserIDFromContext(request.Context())
+	if !ok {
+		http.Error(writer, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	pushRequest.UserId = userId
+
+	pushResponse := syncController.SyncService.Push(request.Context(), pushRequest)
+
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   pushResponse,
+	}
+
+	helper.WriteToResponseBody(writer, webResponse)
+}
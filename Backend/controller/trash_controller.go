@@ -0,0 +1,16 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+type TrashController interface {
+	FindTrash(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	RestoreExpense(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	RestoreCategory(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	PurgeExpense(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	PurgeCategory(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	EmptyTrash(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+}
@@ -0,0 +1,142 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/ryhnfhrza/Expense-Tracker/helper"
+	"github.com/ryhnfhrza/Expense-Tracker/model/web"
+	"github.com/ryhnfhrza/Expense-Tracker/service"
+)
+
+type trashControllerImpl struct {
+	TrashService service.TrashService
+}
+
+func NewTrashController(trashService service.TrashService) TrashController {
+	return &trashControllerImpl{
+		TrashService: trashService,
+	}
+}
+
+func (trashController *trashControllerImpl) FindTrash(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	userId, ok := helper.GetUserIDFromContext(request.Context())
+	if !ok {
+		http.Error(writer, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	trashResponse := trashController.TrashService.FindTrash(request.Context(), userId)
+
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   trashResponse,
+	}
+
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (trashController *trashControllerImpl) RestoreExpense(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	expenseIdString := params.ByName("expenseId")
+	expenseId, err := strconv.Atoi(expenseIdString)
+	helper.PanicIfError(err)
+
+	userId, ok := helper.GetUserIDFromContext(request.Context())
+	if !ok {
+		http.Error(writer, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	expenseResponse := trashController.TrashService.RestoreExpense(request.Context(), expenseId, userId)
+
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   expenseResponse,
+	}
+
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (trashController *trashControllerImpl) RestoreCategory(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	categoryIdString := params.ByName("categoryId")
+	categoryId, err := strconv.Atoi(categoryIdString)
+	helper.PanicIfError(err)
+
+	userId, ok := helper.GetUserIDFromContext(request.Context())
+	if !ok {
+		http.Error(writer, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	categoryResponse := trashController.TrashService.RestoreCategory(request.Context(), categoryId, userId)
+
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   categoryResponse,
+	}
+
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (trashController *trashControllerImpl) PurgeExpense(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	expenseIdString := params.ByName("expenseId")
+	expenseId, err := strconv.Atoi(expenseIdString)
+	helper.PanicIfError(err)
+
+	userId, ok := helper.GetUserIDFromContext(request.Context())
+	if !ok {
+		http.Error(writer, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	trashController.TrashService.PurgeExpense(request.Context(), expenseId, userId)
+
+	webResponse := web.WebResponse{
+		Code:   http.StatusNoContent,
+		Status: "NO CONTENT",
+	}
+
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (trashController *trashControllerImpl) PurgeCategory(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	categoryIdString := params.ByName("categoryId")
+	categoryId, err := strconv.Atoi(categoryIdString)
+	helper.PanicIfError(err)
+
+	userId, ok := helper.GetUserIDFromContext(request.Context())
+	if !ok {
+		http.Error(writer, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	trashController.TrashService.PurgeCategory(request.Context(), categoryId, userId)
+
+	webResponse := web.WebResponse{
+		Code:   http.StatusNoContent,
+		Status: "NO CONTENT",
+	}
+
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (trashController *trashControllerImpl) EmptyTrash(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	userId, ok := helper.GetUserIDFromContext(request.Context())
+	if !ok {
+		http.Error(writer, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	trashController.TrashService.EmptyTrash(request.Context(), userId)
+
+	webResponse := web.WebResponse{
+		Code:   http.StatusNoContent,
+		Status: "NO CONTENT",
+	}
+
+	helper.WriteToResponseBody(writer, webResponse)
+}
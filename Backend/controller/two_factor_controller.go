@@ -0,0 +1,13 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+type TwoFactorController interface {
+	Enroll(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	Confirm(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	Disable(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+}
@@ -0,0 +1,81 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/ryhnfhrza/Expense-Tracker/helper"
+	"github.com/ryhnfhrza/Expense-Tracker/model/web"
+	"github.com/ryhnfhrza/Expense-Tracker/service"
+)
+
+type twoFactorControllerImpl struct {
+	TwoFactorService service.TwoFactorService
+}
+
+func NewTwoFactorController(twoFactorService service.TwoFactorService) TwoFactorController {
+	return &twoFactorControllerImpl{
+		TwoFactorService: twoFactorService,
+	}
+}
+
+func (twoFactorController *twoFactorControllerImpl) Enroll(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	userId, ok := helper.GetUserIDFromContext(request.Context())
+	if !ok {
+		http.Error(writer, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	enrollResponse := twoFactorController.TwoFactorService.Enroll(request.Context(), userId)
+
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   enrollResponse,
+	}
+
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (twoFactorController *twoFactorControllerImpl) Confirm(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	codeRequest := web.TwoFactorCodeRequest{}
+	helper.ReadFromRequestBody(request, &codeRequest)
+
+	userId, ok := helper.GetUserIDFromContext(request.Context())
+	if !ok {
+		http.Error(writer, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	codeRequest.UserId = userId
+
+	confirmResponse := twoFactorController.TwoFactorService.Confirm(request.Context(), codeRequest)
+
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   confirmResponse,
+	}
+
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (twoFactorController *twoFactorControllerImpl) Disable(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	codeRequest := web.TwoFactorCodeRequest{}
+	helper.ReadFromRequestBody(request, &codeRequest)
+
+	userId, ok := helper.GetUserIDFromContext(request.Context())
+	if !ok {
+		http.Error(writer, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	codeRequest.UserId = userId
+
+	twoFactorController.TwoFactorService.Disable(request.Context(), codeRequest)
+
+	webResponse := web.WebResponse{
+		Code:   http.StatusNoContent,
+		Status: "NO CONTENT",
+	}
+
+	helper.WriteToResponseBody(writer, webResponse)
+}
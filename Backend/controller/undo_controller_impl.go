@@ -0,0 +1,38 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/ryhnfhrza/Expense-Tracker/helper"
+	"github.com/ryhnfhrza/Expense-Tracker/model/web"
+	"github.com/ryhnfhrza/Expense-Tracker/service"
+)
+
+type undoControllerImpl struct {
+	UndoService service.UndoService
+}
+
+func NewUndoController(undoService service.UndoService) UndoController {
+	return &undoControllerImpl{
+		UndoService: undoService,
+	}
+}
+
+func (undoController *undoControllerImpl) Undo(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	userId, ok := helper.GetUserIDFromContext(request.Context())
+	if !ok {
+		http.Error(writer, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	undoResponse := undoController.UndoService.Undo(request.Context(), params.ByName("token"), userId)
+
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   undoResponse,
+	}
+
+	helper.WriteToResponseBody(writer, webResponse)
+}
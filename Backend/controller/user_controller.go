@@ -10,4 +10,14 @@ type UserController interface {
 	Register(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
 	VerifyEmail(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
 	Login(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	LoginTwoFactor(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	FindProfile(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	UpdateProfile(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	ChangePassword(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	RequestEmailChange(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	VerifyEmailChange(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	ScheduleDeletion(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	CancelDeletion(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	FindPreferences(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	UpdatePreferences(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
 }
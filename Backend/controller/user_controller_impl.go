@@ -64,3 +64,203 @@ func (controller *userControllerImpl) Login(writer http.ResponseWriter, request
 
 	helper.WriteToResponseBody(writer, webResponse)
 }
+
+func (controller *userControllerImpl) LoginTwoFactor(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	userRequest := web.UserTwoFactorLoginRequest{}
+	helper.ReadFromRequestBody(request, &userRequest)
+
+	userResponse := controller.UserService.LoginTwoFactor(request.Context(), userRequest)
+
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   userResponse,
+	}
+
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (controller *userControllerImpl) FindProfile(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	userId, ok := helper.GetUserIDFromContext(request.Context())
+	if !ok {
+		http.Error(writer, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	profileResponse := controller.UserService.FindProfile(request.Context(), userId)
+
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   profileResponse,
+	}
+
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (controller *userControllerImpl) UpdateProfile(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	userRequest := web.UserProfileUpdateRequest{}
+	helper.ReadFromRequestBody(request, &userRequest)
+
+	userId, ok := helper.GetUserIDFromContext(request.Context())
+	if !ok {
+		http.Error(writer, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	userRequest.UserId = userId
+
+	profileResponse := controller.UserService.UpdateProfile(request.Context(), userRequest)
+
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   profileResponse,
+	}
+
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (controller *userControllerImpl) ChangePassword(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	userRequest := web.UserPasswordChangeRequest{}
+	helper.ReadFromRequestBody(request, &userRequest)
+
+	userId, ok := helper.GetUserIDFromContext(request.Context())
+	if !ok {
+		http.Error(writer, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	userRequest.UserId = userId
+
+	controller.UserService.ChangePassword(request.Context(), userRequest)
+
+	webResponse := web.WebResponse{
+		Code:   http.StatusNoContent,
+		Status: "NO CONTENT",
+	}
+
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (controller *userControllerImpl) RequestEmailChange(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	userRequest := web.UserEmailChangeRequest{}
+	helper.ReadFromRequestBody(request, &userRequest)
+
+	userId, ok := helper.GetUserIDFromContext(request.Context())
+	if !ok {
+		http.Error(writer, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	userRequest.UserId = userId
+
+	userResponse := controller.UserService.RequestEmailChange(request.Context(), userRequest)
+
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   userResponse,
+	}
+
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (controller *userControllerImpl) VerifyEmailChange(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	userRequest := web.UserEmailVerifyRequest{}
+	helper.ReadFromRequestBody(request, &userRequest)
+
+	userId, ok := helper.GetUserIDFromContext(request.Context())
+	if !ok {
+		http.Error(writer, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	userRequest.UserId = userId
+
+	profileResponse := controller.UserService.VerifyEmailChange(request.Context(), userRequest)
+
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   profileResponse,
+	}
+
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (controller *userControllerImpl) ScheduleDeletion(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	userRequest := web.UserDeleteRequest{}
+	helper.ReadFromRequestBody(request, &userRequest)
+
+	userId, ok := helper.GetUserIDFromContext(request.Context())
+	if !ok {
+		http.Error(writer, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	userRequest.UserId = userId
+
+	profileResponse := controller.UserService.ScheduleDeletion(request.Context(), userRequest)
+
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   profileResponse,
+	}
+
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (controller *userControllerImpl) CancelDeletion(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	userId, ok := helper.GetUserIDFromContext(request.Context())
+	if !ok {
+		http.Error(writer, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	profileResponse := controller.UserService.CancelDeletion(request.Context(), userId)
+
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   profileResponse,
+	}
+
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (controller *userControllerImpl) FindPreferences(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	userId, ok := helper.GetUserIDFromContext(request.Context())
+	if !ok {
+		http.Error(writer, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	preferencesResponse := controller.UserService.FindPreferences(request.Context(), userId)
+
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   preferencesResponse,
+	}
+
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (controller *userControllerImpl) UpdatePreferences(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	userRequest := web.UserPreferencesUpdateRequest{}
+	helper.ReadFromRequestBody(request, &userRequest)
+
+	userId, ok := helper.GetUserIDFromContext(request.Context())
+	if !ok {
+		http.Error(writer, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	userRequest.UserId = userId
+
+	preferencesResponse := controller.UserService.UpdatePreferences(request.Context(), userRequest)
+
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   preferencesResponse,
+	}
+
+	helper.WriteToResponseBody(writer, webResponse)
+}
@@ -0,0 +1,15 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+)
+
+type WebhookController interface {
+	CreateWebhook(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	DeleteWebhook(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	FindAllWebhook(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	FindAllDeliveries(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+	RetryDelivery(writer http.ResponseWriter, request *http.Request, params httprouter.Params)
+}
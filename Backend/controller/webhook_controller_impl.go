@@ -0,0 +1,153 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/ryhnfhrza/Expense-Tracker/exception"
+	"github.com/ryhnfhrza/Expense-Tracker/helper"
+	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
+	"github.com/ryhnfhrza/Expense-Tracker/model/web"
+	"github.com/ryhnfhrza/Expense-Tracker/service"
+)
+
+type webhookControllerImpl struct {
+	WebhookService service.WebhookService
+}
+
+func NewWebhookController(webhookService service.WebhookService) WebhookController {
+	return &webhookControllerImpl{
+		WebhookService: webhookService,
+	}
+}
+
+func (webhookController *webhookControllerImpl) CreateWebhook(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	webhookRequest := web.WebhookCreateRequest{}
+	helper.ReadFromRequestBody(request, &webhookRequest)
+
+	userId, ok := helper.GetUserIDFromContext(request.Context())
+	if !ok {
+		http.Error(writer, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	webhookRequest.UserId = userId
+
+	webhookResponse := webhookController.WebhookService.CreateWebhook(request.Context(), webhookRequest)
+
+	webResponse := web.WebResponse{
+		Code:   http.StatusCreated,
+		Status: "Created",
+		Data:   webhookResponse,
+	}
+
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (webhookController *webhookControllerImpl) DeleteWebhook(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	webhookId, err := strconv.Atoi(params.ByName("webhookId"))
+	helper.PanicIfError(err)
+
+	userId, ok := helper.GetUserIDFromContext(request.Context())
+	if !ok {
+		http.Error(writer, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	webhookController.WebhookService.DeleteWebhook(request.Context(), webhookId, userId)
+
+	webResponse := web.WebResponse{
+		Code:   http.StatusNoContent,
+		Status: "NO CONTENT",
+	}
+
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (webhookController *webhookControllerImpl) FindAllWebhook(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	userId, ok := helper.GetUserIDFromContext(request.Context())
+	if !ok {
+		http.Error(writer, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	webhookResponses := webhookController.WebhookService.FindAllWebhook(request.Context(), userId)
+
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   webhookResponses,
+	}
+
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (webhookController *webhookControllerImpl) FindAllDeliveries(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	userId, ok := helper.GetUserIDFromContext(request.Context())
+	if !ok {
+		http.Error(writer, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	query := request.URL.Query()
+
+	var filter domain.WebhookDeliveryFilter
+	switch v := query.Get("status"); v {
+	case "", domain.WebhookDeliveryPending, domain.WebhookDeliveryDelivered, domain.WebhookDeliveryDead:
+		filter.Status = v
+	default:
+		panic(exception.NewBadRequest("invalid status, expected pending, delivered or dead"))
+	}
+	if v := query.Get("webhook_id"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 1 {
+			panic(exception.NewBadRequest("invalid webhook_id"))
+		}
+		filter.WebhookId = parsed
+	}
+	if v := query.Get("limit"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil || parsed < 1 {
+			panic(exception.NewBadRequest("invalid limit"))
+		}
+		filter.Limit = parsed
+	}
+	if v := query.Get("before_id"); v != "" {
+		parsed, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || parsed < 1 {
+			panic(exception.NewBadRequest("invalid before_id"))
+		}
+		filter.BeforeId = parsed
+	}
+
+	deliveryResponse := webhookController.WebhookService.FindAllDeliveries(request.Context(), userId, filter)
+
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   deliveryResponse,
+	}
+
+	helper.WriteToResponseBody(writer, webResponse)
+}
+
+func (webhookController *webhookControllerImpl) RetryDelivery(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	deliveryId, err := strconv.ParseInt(params.ByName("deliveryId"), 10, 64)
+	helper.PanicIfError(err)
+
+	userId, ok := helper.GetUserIDFromContext(request.Context())
+	if !ok {
+		http.Error(writer, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	deliveryResponse := webhookController.WebhookService.RetryDelivery(request.Context(), deliveryId, userId)
+
+	webResponse := web.WebResponse{
+		Code:   http.StatusOK,
+		Status: "OK",
+		Data:   deliveryResponse,
+	}
+
+	helper.WriteToResponseBody(writer, webResponse)
+}
@@ -0,0 +1 @@
+ALTER TABLE `expenses` DROP INDEX `ft_description`;
@@ -0,0 +1 @@
+ALTER TABLE `expenses` ADD FULLTEXT KEY `ft_description` (`description`)
\ No newline at end of file
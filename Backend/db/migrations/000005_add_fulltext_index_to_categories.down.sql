@@ -0,0 +1 @@
+ALTER TABLE `categories` DROP INDEX `ft_name`;
@@ -0,0 +1 @@
+ALTER TABLE `categories` ADD FULLTEXT KEY `ft_name` (`name`)
\ No newline at end of file
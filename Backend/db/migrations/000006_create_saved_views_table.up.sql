@@ -0,0 +1,15 @@
+CREATE TABLE `saved_views` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `user_id` int NOT NULL,
+  `name` varchar(100) NOT NULL,
+  `kind` varchar(20) NOT NULL DEFAULT 'expense',
+  `filter` json NOT NULL,
+  `sort_by` varchar(50) NOT NULL DEFAULT '',
+  `sort_order` varchar(4) NOT NULL DEFAULT '',
+  `is_default` tinyint(1) NOT NULL DEFAULT '0',
+  `created_at` datetime DEFAULT CURRENT_TIMESTAMP,
+  `updated_at` datetime DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uq_saved_views_user_name` (`user_id`,`name`),
+  CONSTRAINT `saved_views_ibfk_1` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci
\ No newline at end of file
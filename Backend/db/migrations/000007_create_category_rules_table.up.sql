@@ -0,0 +1,17 @@
+CREATE TABLE `category_rules` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `user_id` int NOT NULL,
+  `category_id` int NOT NULL,
+  `name` varchar(100) NOT NULL,
+  `description_contains` varchar(100) NOT NULL DEFAULT '',
+  `description_pattern` varchar(200) NOT NULL DEFAULT '',
+  `min_amount` decimal(12,2) DEFAULT NULL,
+  `max_amount` decimal(12,2) DEFAULT NULL,
+  `priority` int NOT NULL DEFAULT '0',
+  `created_at` datetime DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `idx_user_priority` (`user_id`,`priority`),
+  KEY `category_id` (`category_id`),
+  CONSTRAINT `category_rules_ibfk_1` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`),
+  CONSTRAINT `category_rules_ibfk_2` FOREIGN KEY (`category_id`) REFERENCES `categories` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci
\ No newline at end of file
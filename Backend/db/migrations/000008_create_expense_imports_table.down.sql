@@ -0,0 +1 @@
+DROP TABLE IF EXISTS expense_imports;
\ No newline at end of file
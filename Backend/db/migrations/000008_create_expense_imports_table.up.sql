@@ -0,0 +1,13 @@
+CREATE TABLE `expense_imports` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `user_id` int NOT NULL,
+  `expense_id` int DEFAULT NULL,
+  `external_id` varchar(191) NOT NULL,
+  `source` varchar(20) NOT NULL,
+  `imported_at` datetime DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uniq_user_external_id` (`user_id`,`external_id`),
+  KEY `expense_id` (`expense_id`),
+  CONSTRAINT `expense_imports_ibfk_1` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`),
+  CONSTRAINT `expense_imports_ibfk_2` FOREIGN KEY (`expense_id`) REFERENCES `expenses` (`id`) ON DELETE SET NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci
\ No newline at end of file
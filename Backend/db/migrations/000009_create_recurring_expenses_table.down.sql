@@ -0,0 +1 @@
+DROP TABLE IF EXISTS recurring_expenses;
\ No newline at end of file
@@ -0,0 +1,15 @@
+CREATE TABLE `audit_log` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `user_id` int NOT NULL,
+  `actor_id` int DEFAULT NULL,
+  `entity_type` varchar(20) NOT NULL,
+  `entity_id` int NOT NULL,
+  `action` varchar(10) NOT NULL,
+  `before_data` json DEFAULT NULL,
+  `after_data` json DEFAULT NULL,
+  `request_id` varchar(64) NOT NULL DEFAULT '',
+  `created_at` datetime(3) NOT NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_user_entity` (`user_id`,`entity_type`,`entity_id`,`id`),
+  KEY `idx_user_id` (`user_id`,`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci
\ No newline at end of file
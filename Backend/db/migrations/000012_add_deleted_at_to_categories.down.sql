@@ -0,0 +1 @@
+ALTER TABLE `categories` DROP KEY `idx_deleted_at`, DROP COLUMN `deleted_at`
\ No newline at end of file
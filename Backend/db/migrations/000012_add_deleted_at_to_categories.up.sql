@@ -0,0 +1 @@
+ALTER TABLE `categories` ADD COLUMN `deleted_at` datetime DEFAULT NULL, ADD KEY `idx_deleted_at` (`deleted_at`)
\ No newline at end of file
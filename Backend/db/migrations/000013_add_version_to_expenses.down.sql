@@ -0,0 +1 @@
+ALTER TABLE `expenses` DROP COLUMN `version`
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE `expenses` ADD COLUMN `version` int NOT NULL DEFAULT 1
\ No newline at end of file
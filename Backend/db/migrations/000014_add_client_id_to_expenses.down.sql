@@ -0,0 +1 @@
+ALTER TABLE `expenses` DROP KEY `uq_user_client_id`, DROP COLUMN `client_id`
\ No newline at end of file
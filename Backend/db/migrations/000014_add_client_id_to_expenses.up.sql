@@ -0,0 +1 @@
+ALTER TABLE `expenses` ADD COLUMN `client_id` varchar(64) DEFAULT NULL, ADD UNIQUE KEY `uq_user_client_id` (`user_id`,`client_id`)
\ No newline at end of file
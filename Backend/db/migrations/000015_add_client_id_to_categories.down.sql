@@ -0,0 +1 @@
+ALTER TABLE `categories` DROP KEY `uq_user_client_id`, DROP COLUMN `client_id`
\ No newline at end of file
@@ -0,0 +1,18 @@
+CREATE TABLE `webhook_deliveries` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `webhook_id` int NOT NULL,
+  `event_id` bigint NOT NULL,
+  `status` varchar(10) NOT NULL,
+  `attempts` int NOT NULL DEFAULT '0',
+  `next_attempt_at` datetime(3) NOT NULL,
+  `last_status_code` int DEFAULT NULL,
+  `last_error` varchar(255) NOT NULL DEFAULT '',
+  `delivered_at` datetime(3) DEFAULT NULL,
+  `created_at` datetime(3) NOT NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_status_next_attempt` (`status`,`next_attempt_at`),
+  KEY `idx_webhook_id` (`webhook_id`,`id`),
+  KEY `event_id` (`event_id`),
+  CONSTRAINT `webhook_deliveries_ibfk_1` FOREIGN KEY (`webhook_id`) REFERENCES `webhooks` (`id`) ON DELETE CASCADE,
+  CONSTRAINT `webhook_deliveries_ibfk_2` FOREIGN KEY (`event_id`) REFERENCES `webhook_events` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci
\ No newline at end of file
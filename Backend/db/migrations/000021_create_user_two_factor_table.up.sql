@@ -0,0 +1,9 @@
+CREATE TABLE `user_two_factor` (
+  `user_id` int NOT NULL,
+  `secret` varchar(64) NOT NULL,
+  `confirmed_at` datetime DEFAULT NULL,
+  `last_used_step` bigint NOT NULL DEFAULT 0,
+  `created_at` datetime NOT NULL,
+  PRIMARY KEY (`user_id`),
+  CONSTRAINT `user_two_factor_ibfk_1` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci
\ No newline at end of file
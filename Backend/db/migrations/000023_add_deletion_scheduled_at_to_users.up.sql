@@ -0,0 +1 @@
+ALTER TABLE `users` ADD COLUMN `deletion_scheduled_at` datetime DEFAULT NULL, ADD KEY `idx_deletion_scheduled_at` (`deletion_scheduled_at`)
\ No newline at end of file
@@ -0,0 +1,16 @@
+CREATE TABLE `data_exports` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `user_id` int NOT NULL,
+  `status` varchar(10) NOT NULL,
+  `archive` longblob,
+  `size_bytes` bigint NOT NULL DEFAULT '0',
+  `error` varchar(255) NOT NULL DEFAULT '',
+  `requested_at` datetime(3) NOT NULL,
+  `started_at` datetime(3) DEFAULT NULL,
+  `completed_at` datetime(3) DEFAULT NULL,
+  `expires_at` datetime(3) DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_status` (`status`,`id`),
+  KEY `idx_user_id` (`user_id`,`id`),
+  CONSTRAINT `data_exports_ibfk_1` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci
\ No newline at end of file
@@ -0,0 +1,11 @@
+CREATE TABLE `user_preferences` (
+  `user_id` int NOT NULL,
+  `timezone` varchar(64) NOT NULL,
+  `locale` varchar(35) NOT NULL,
+  `currency` char(3) NOT NULL,
+  `week_start` varchar(10) NOT NULL,
+  `date_format` varchar(20) NOT NULL,
+  `updated_at` datetime NOT NULL,
+  PRIMARY KEY (`user_id`),
+  CONSTRAINT `user_preferences_ibfk_1` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci
\ No newline at end of file
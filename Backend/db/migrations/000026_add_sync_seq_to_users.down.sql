@@ -0,0 +1 @@
+ALTER TABLE `users` DROP COLUMN `sync_seq`
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE `users` ADD COLUMN `sync_seq` bigint NOT NULL DEFAULT 0
\ No newline at end of file
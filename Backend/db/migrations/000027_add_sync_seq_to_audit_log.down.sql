@@ -0,0 +1 @@
+ALTER TABLE `audit_log` DROP KEY `idx_user_sync_seq`, DROP COLUMN `sync_seq`
\ No newline at end of file
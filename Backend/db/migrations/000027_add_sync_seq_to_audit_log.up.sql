@@ -0,0 +1 @@
+ALTER TABLE `audit_log` ADD COLUMN `sync_seq` bigint DEFAULT NULL, ADD KEY `idx_user_sync_seq` (`user_id`,`sync_seq`)
\ No newline at end of file
@@ -0,0 +1 @@
+UPDATE `audit_log` SET `sync_seq` = NULL
\ No newline at end of file
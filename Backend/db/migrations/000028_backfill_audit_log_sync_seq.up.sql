@@ -0,0 +1 @@
+UPDATE `audit_log` SET `sync_seq` = `id` WHERE `entity_type` IN ('expense','category')
\ No newline at end of file
@@ -0,0 +1 @@
+UPDATE `users` SET `sync_seq` = 0
\ No newline at end of file
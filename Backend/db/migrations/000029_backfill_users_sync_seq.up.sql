@@ -0,0 +1 @@
+UPDATE `users` u JOIN (SELECT `user_id`, MAX(`id`) AS `last_id` FROM `audit_log` GROUP BY `user_id`) a ON a.`user_id` = u.`id` SET u.`sync_seq` = a.`last_id`
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE `recurring_expenses` DROP COLUMN `anchor_day`
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE `recurring_expenses` ADD COLUMN `anchor_day` tinyint NOT NULL DEFAULT 0
\ No newline at end of file
@@ -0,0 +1 @@
+UPDATE `recurring_expenses` SET `anchor_day` = 0
\ No newline at end of file
@@ -0,0 +1 @@
+UPDATE `recurring_expenses` SET `anchor_day` = DAY(`next_run_at`) WHERE `cadence` IN ('monthly','quarterly','yearly')
\ No newline at end of file
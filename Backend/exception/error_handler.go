@@ -28,16 +28,70 @@ func ErrorHandler(writer http.ResponseWriter, request *http.Request, err interfa
 		return
 	}
 
+	if forbiddenError(writer, request, err) {
+		return
+	}
+
 	if badRequestError(writer, request, err) {
 		return
 	}
 	if tooManyRequestError(writer, request, err) {
 		return
 	}
+	if preconditionFailedError(writer, request, err) {
+		return
+	}
+	if preconditionRequiredError(writer, request, err) {
+		return
+	}
 
 	internalServerError(writer, request, err)
 }
 
+func preconditionFailedError(writer http.ResponseWriter, request *http.Request, err interface{}) bool {
+	exception, ok := err.(*PreconditionFailedError)
+	if ok {
+		writer.Header().Set("Content-Type", "application/json")
+		if exception.ETag != "" {
+			writer.Header().Set("ETag", exception.ETag)
+		}
+		writer.WriteHeader(http.StatusPreconditionFailed)
+
+		webResponse := web.WebResponse{
+			Code:   http.StatusPreconditionFailed,
+			Status: "PRECONDITION FAILED",
+			Data: map[string]interface{}{
+				"message": exception.Message,
+				"current": exception.Current,
+			},
+		}
+		helper.WriteToResponseBody(writer, webResponse)
+		return true
+	} else {
+
+		return false
+	}
+}
+func preconditionRequiredError(writer http.ResponseWriter, request *http.Request, err interface{}) bool {
+	exception, ok := err.(*PreconditionRequiredError)
+	if ok {
+		writer.Header().Set("Content-Type", "application/json")
+		writer.WriteHeader(http.StatusPreconditionRequired)
+
+		webResponse := web.WebResponse{
+			Code:   http.StatusPreconditionRequired,
+			Status: "PRECONDITION REQUIRED",
+			Data: map[string]string{
+				"message": exception.Message,
+			},
+		}
+		helper.WriteToResponseBody(writer, webResponse)
+		return true
+	} else {
+
+		return false
+	}
+}
 func tooManyRequestError(writer http.ResponseWriter, request *http.Request, err interface{}) bool {
 	exception, ok := err.(*TooManyRequestError)
 	if ok {
@@ -100,6 +154,28 @@ func unauthorizedError(writer http.ResponseWriter, request *http.Request, err in
 	}
 }
 
+func forbiddenError(writer http.ResponseWriter, request *http.Request, err interface{}) bool {
+	exception, ok := err.(*ForbiddenError)
+	if ok {
+		writer.Header().Set("Content-Type", "application/json")
+		writer.WriteHeader(http.StatusForbidden)
+
+		webResponse := web.WebResponse{
+			Code:   http.StatusForbidden,
+			Status: "FORBIDDEN",
+			Data: map[string]string{
+				"message": exception.Message,
+			},
+		}
+
+		helper.WriteToResponseBody(writer, webResponse)
+		return true
+	} else {
+
+		return false
+	}
+}
+
 func conflictError(writer http.ResponseWriter, request *http.Request, err interface{}) bool {
 	exception, ok := err.(*ConflictError)
 	if ok {
@@ -0,0 +1,13 @@
+package exception
+
+type ForbiddenError struct {
+	Message string
+}
+
+func (e *ForbiddenError) Error() string {
+	return e.Message
+}
+
+func NewForbiddenError(msg string) error {
+	return &ForbiddenError{Message: msg}
+}
@@ -0,0 +1,18 @@
+package exception
+
+// PreconditionFailedError is raised when an If-Match header no longer
+// matches the stored row. Current is the row as it is now, so the client
+// can merge and retry with ETag.
+type PreconditionFailedError struct {
+	Message string
+	Current interface{}
+	ETag    string
+}
+
+func (e *PreconditionFailedError) Error() string {
+	return e.Message
+}
+
+func NewPreconditionFailedError(msg string, current interface{}, etag string) error {
+	return &PreconditionFailedError{Message: msg, Current: current, ETag: etag}
+}
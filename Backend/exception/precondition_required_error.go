@@ -0,0 +1,13 @@
+package exception
+
+type PreconditionRequiredError struct {
+	Message string
+}
+
+func (e *PreconditionRequiredError) Error() string {
+	return e.Message
+}
+
+func NewPreconditionRequiredError(msg string) error {
+	return &PreconditionRequiredError{Message: msg}
+}
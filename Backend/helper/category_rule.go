@@ -0,0 +1,69 @@
+package helper
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
+)
+
+type compiledCategoryRule struct {
+	rule    domain.CategoryRule
+	pattern *regexp.Regexp
+}
+
+// CategoryRuleMatcher evaluates a user's rules in the order they are given.
+// All conditions set on a rule must hold; the first matching rule wins.
+type CategoryRuleMatcher struct {
+	rules []compiledCategoryRule
+}
+
+func NewCategoryRuleMatcher(rules []domain.CategoryRule) (*CategoryRuleMatcher, error) {
+	matcher := &CategoryRuleMatcher{}
+	for _, rule := range rules {
+		compiled := compiledCategoryRule{rule: rule}
+		if rule.DescriptionPattern != "" {
+			pattern, err := CompileRulePattern(rule.DescriptionPattern)
+			if err != nil {
+				return nil, fmt.Errorf("rule %d: %w", rule.Id, err)
+			}
+			compiled.pattern = pattern
+		}
+		matcher.rules = append(matcher.rules, compiled)
+	}
+	return matcher, nil
+}
+
+// CompileRulePattern compiles a description pattern case-insensitively.
+func CompileRulePattern(pattern string) (*regexp.Regexp, error) {
+	compiled, err := regexp.Compile("(?i)" + pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid description_pattern: %v", err)
+	}
+	return compiled, nil
+}
+
+func (matcher *CategoryRuleMatcher) Match(description string, amount float64) *domain.CategoryRule {
+	lower := strings.ToLower(description)
+	for i := range matcher.rules {
+		compiled := &matcher.rules[i]
+		rule := compiled.rule
+
+		if rule.DescriptionContains != "" && !strings.Contains(lower, strings.ToLower(rule.DescriptionContains)) {
+			continue
+		}
+		if compiled.pattern != nil && !compiled.pattern.MatchString(description) {
+			continue
+		}
+		if rule.MinAmount != nil && amount < *rule.MinAmount {
+			continue
+		}
+		if rule.MaxAmount != nil && amount > *rule.MaxAmount {
+			continue
+		}
+
+		return &compiled.rule
+	}
+	return nil
+}
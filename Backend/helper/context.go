@@ -1,6 +1,9 @@
 package helper
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 type contextKey string
 
@@ -14,3 +17,27 @@ func GetUserIDFromContext(ctx context.Context) (int, bool) {
 	userID, ok := ctx.Value(userIDKey).(int)
 	return userID, ok
 }
+
+const requestIDKey contextKey = "request_id"
+
+func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+func GetRequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey).(string)
+	return requestID
+}
+
+const signedInAtKey contextKey = "signed_in_at"
+
+// ContextWithSignedInAt records when the user signed in to get the JWT the
+// request carries. Requests made with personal access tokens have none.
+func ContextWithSignedInAt(ctx context.Context, signedInAt time.Time) context.Context {
+	return context.WithValue(ctx, signedInAtKey, signedInAt)
+}
+
+func GetSignedInAtFromContext(ctx context.Context) (time.Time, bool) {
+	signedInAt, ok := ctx.Value(signedInAtKey).(time.Time)
+	return signedInAt, ok
+}
@@ -0,0 +1,34 @@
+package helper
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FormatETag renders a row version as a strong entity tag.
+func FormatETag(version int) string {
+	return fmt.Sprintf(`"%d"`, version)
+}
+
+// ParseIfMatch reads the version out of an If-Match header. "*" matches
+// any version and comes back as 0. Weak tags are accepted since versions
+// are only ever compared for equality.
+func ParseIfMatch(header string) (int, error) {
+	header = strings.TrimSpace(header)
+	if header == "*" {
+		return 0, nil
+	}
+
+	tag := strings.TrimPrefix(header, "W/")
+	if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+		return 0, fmt.Errorf("invalid If-Match header %q", header)
+	}
+
+	version, err := strconv.Atoi(tag[1 : len(tag)-1])
+	if err != nil || version <= 0 {
+		return 0, fmt.Errorf("invalid If-Match header %q", header)
+	}
+
+	return version, nil
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/url"
 	"strconv"
+	"strings"
 
 	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
 )
@@ -13,15 +14,23 @@ func ParseExpenseFilter(query url.Values) (domain.ExpenseFilter, error) {
 
 	tz := query.Get("tz")
 	if tz == "" {
-		tz = "Asia/Jakarta"
+		tz = domain.DefaultTimezone
 	}
 
 	if catStr := query.Get("category_id"); catStr != "" {
-		catID, err := strconv.Atoi(catStr)
-		if err != nil || catID <= 0 {
+		catIDs, err := parseIdList(catStr)
+		if err != nil {
 			return filter, fmt.Errorf("invalid category_id")
 		}
-		filter.CategoryId = &catID
+		filter.CategoryIds = catIDs
+	}
+
+	if exStr := query.Get("exclude_category_id"); exStr != "" {
+		exIDs, err := parseIdList(exStr)
+		if err != nil {
+			return filter, fmt.Errorf("invalid exclude_category_id")
+		}
+		filter.ExcludeCategoryIds = exIDs
 	}
 
 	if minStr := query.Get("min_amount"); minStr != "" {
@@ -78,9 +87,29 @@ func ParseExpenseFilter(query url.Values) (domain.ExpenseFilter, error) {
 		filter.Offset = o
 	}
 	if descStr := query.Get("description"); descStr != "" {
-    filter.Description = &descStr
+		filter.Description = &descStr
 	}
 
+	if expr := query.Get("q"); expr != "" {
+		node, err := ParseFilterExpression(expr, tz)
+		if err != nil {
+			return filter, err
+		}
+		filter.Expression = node
+	}
 
 	return filter, nil
 }
+
+// parseIdList parses a comma separated list of positive ids such as "1,2,3".
+func parseIdList(raw string) ([]int, error) {
+	var ids []int
+	for _, part := range strings.Split(raw, ",") {
+		id, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || id <= 0 {
+			return nil, fmt.Errorf("invalid id %q", part)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
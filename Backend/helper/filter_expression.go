@@ -0,0 +1,320 @@
+package helper
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
+)
+
+// The shortest condition joined with " OR " takes 12 characters, so the
+// comparison cap has to stay below 500/12 to be reachable at all.
+const (
+	maxFilterExpressionLength = 500
+	maxFilterExpressionDepth  = 10
+	maxFilterComparisons      = 30
+)
+
+// filterFieldOperators lists the fields a `q` expression may reference and
+// the comparison operators each of them accepts.
+var filterFieldOperators = map[string][]string{
+	"amount":      {">", ">=", "<", "<=", "=", "!="},
+	"category":    {":", "=", "!="},
+	"category_id": {"=", "!="},
+	"description": {":", "=", "!="},
+	"date":        {">", ">=", "<", "<=", "="},
+}
+
+type filterTokenKind int
+
+const (
+	filterTokenWord filterTokenKind = iota
+	filterTokenString
+	filterTokenOperator
+	filterTokenOpenParen
+	filterTokenCloseParen
+)
+
+type filterToken struct {
+	kind filterTokenKind
+	text string
+	pos  int
+}
+
+type filterParser struct {
+	tokens      []filterToken
+	pos         int
+	comparisons int
+	tz          string
+}
+
+// ParseFilterExpression parses expressions such as
+// `amount>100 AND (category:food OR category:transport)` into a FilterNode
+// tree. Only whitelisted fields and operators are accepted; values are kept
+// as typed Go values so the repository can bind them as query arguments.
+func ParseFilterExpression(input string, tz string) (*domain.FilterNode, error) {
+	input = strings.TrimSpace(input)
+	if len(input) > maxFilterExpressionLength {
+		return nil, fmt.Errorf("invalid q: expression must be at most %d characters", maxFilterExpressionLength)
+	}
+
+	tokens, err := tokenizeFilterExpression(input)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("invalid q: expression is empty")
+	}
+
+	parser := &filterParser{tokens: tokens, tz: tz}
+	node, err := parser.parseOr(0)
+	if err != nil {
+		return nil, err
+	}
+
+	if tok, ok := parser.peek(); ok {
+		return nil, fmt.Errorf("invalid q: unexpected %q at position %d", tok.text, tok.pos+1)
+	}
+
+	return &node, nil
+}
+
+func tokenizeFilterExpression(input string) ([]filterToken, error) {
+	var tokens []filterToken
+
+	runes := []rune(input)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, filterToken{kind: filterTokenOpenParen, text: "(", pos: i})
+			i++
+		case r == ')':
+			tokens = append(tokens, filterToken{kind: filterTokenCloseParen, text: ")", pos: i})
+			i++
+		case r == '"':
+			end := i + 1
+			for end < len(runes) && runes[end] != '"' {
+				end++
+			}
+			if end >= len(runes) {
+				return nil, fmt.Errorf("invalid q: unterminated string starting at position %d", i+1)
+			}
+			tokens = append(tokens, filterToken{kind: filterTokenString, text: string(runes[i+1 : end]), pos: i})
+			i = end + 1
+		case strings.ContainsRune("<>=!:", r):
+			op := string(r)
+			if i+1 < len(runes) && runes[i+1] == '=' && r != '=' && r != ':' {
+				op += "="
+			}
+			if op == "!" {
+				return nil, fmt.Errorf("invalid q: unexpected \"!\" at position %d, did you mean \"!=\"?", i+1)
+			}
+			tokens = append(tokens, filterToken{kind: filterTokenOperator, text: op, pos: i})
+			i += len([]rune(op))
+		case isFilterWordRune(r):
+			end := i
+			for end < len(runes) && isFilterWordRune(runes[end]) {
+				end++
+			}
+			tokens = append(tokens, filterToken{kind: filterTokenWord, text: string(runes[i:end]), pos: i})
+			i = end
+		default:
+			return nil, fmt.Errorf("invalid q: unexpected character %q at position %d", r, i+1)
+		}
+	}
+
+	return tokens, nil
+}
+
+func isFilterWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '-' || r == '.'
+}
+
+func (parser *filterParser) peek() (filterToken, bool) {
+	if parser.pos >= len(parser.tokens) {
+		return filterToken{}, false
+	}
+	return parser.tokens[parser.pos], true
+}
+
+func (parser *filterParser) peekKeyword(keyword string) bool {
+	tok, ok := parser.peek()
+	return ok && tok.kind == filterTokenWord && strings.EqualFold(tok.text, keyword)
+}
+
+func (parser *filterParser) parseOr(depth int) (domain.FilterNode, error) {
+	left, err := parser.parseAnd(depth)
+	if err != nil {
+		return left, err
+	}
+
+	node := domain.FilterNode{Kind: domain.FilterOr, Children: []domain.FilterNode{left}}
+	for parser.peekKeyword("OR") {
+		parser.pos++
+		right, err := parser.parseAnd(depth)
+		if err != nil {
+			return right, err
+		}
+		node.Children = append(node.Children, right)
+	}
+
+	if len(node.Children) == 1 {
+		return left, nil
+	}
+	return node, nil
+}
+
+func (parser *filterParser) parseAnd(depth int) (domain.FilterNode, error) {
+	left, err := parser.parseUnary(depth)
+	if err != nil {
+		return left, err
+	}
+
+	node := domain.FilterNode{Kind: domain.FilterAnd, Children: []domain.FilterNode{left}}
+	for parser.peekKeyword("AND") {
+		parser.pos++
+		right, err := parser.parseUnary(depth)
+		if err != nil {
+			return right, err
+		}
+		node.Children = append(node.Children, right)
+	}
+
+	if len(node.Children) == 1 {
+		return left, nil
+	}
+	return node, nil
+}
+
+func (parser *filterParser) parseUnary(depth int) (domain.FilterNode, error) {
+	if depth > maxFilterExpressionDepth {
+		return domain.FilterNode{}, fmt.Errorf("invalid q: expression is nested more than %d levels deep", maxFilterExpressionDepth)
+	}
+
+	tok, ok := parser.peek()
+	if !ok {
+		return domain.FilterNode{}, fmt.Errorf("invalid q: unexpected end of expression")
+	}
+
+	switch {
+	case tok.kind == filterTokenWord && strings.EqualFold(tok.text, "NOT"):
+		parser.pos++
+		child, err := parser.parseUnary(depth + 1)
+		if err != nil {
+			return child, err
+		}
+		return domain.FilterNode{Kind: domain.FilterNot, Children: []domain.FilterNode{child}}, nil
+
+	case tok.kind == filterTokenOpenParen:
+		parser.pos++
+		node, err := parser.parseOr(depth + 1)
+		if err != nil {
+			return node, err
+		}
+		closing, ok := parser.peek()
+		if !ok || closing.kind != filterTokenCloseParen {
+			return node, fmt.Errorf("invalid q: missing closing parenthesis for \"(\" at position %d", tok.pos+1)
+		}
+		parser.pos++
+		return node, nil
+
+	default:
+		return parser.parseComparison()
+	}
+}
+
+func (parser *filterParser) parseComparison() (domain.FilterNode, error) {
+	fieldTok, _ := parser.peek()
+	if fieldTok.kind != filterTokenWord {
+		return domain.FilterNode{}, fmt.Errorf("invalid q: expected a field name at position %d, got %q", fieldTok.pos+1, fieldTok.text)
+	}
+
+	field := strings.ToLower(fieldTok.text)
+	if field == "created_at" {
+		field = "date"
+	}
+	operators, ok := filterFieldOperators[field]
+	if !ok {
+		return domain.FilterNode{}, fmt.Errorf("invalid q: unknown field %q at position %d (allowed: amount, category, category_id, description, date)", fieldTok.text, fieldTok.pos+1)
+	}
+	parser.pos++
+
+	opTok, ok := parser.peek()
+	if !ok || opTok.kind != filterTokenOperator {
+		return domain.FilterNode{}, fmt.Errorf("invalid q: expected an operator after %q", fieldTok.text)
+	}
+	if !containsString(operators, opTok.text) {
+		return domain.FilterNode{}, fmt.Errorf("invalid q: operator %q is not supported for %s (allowed: %s)", opTok.text, field, strings.Join(operators, " "))
+	}
+	parser.pos++
+
+	valueTok, ok := parser.peek()
+	if !ok || (valueTok.kind != filterTokenWord && valueTok.kind != filterTokenString) {
+		return domain.FilterNode{}, fmt.Errorf("invalid q: expected a value after %s%s", fieldTok.text, opTok.text)
+	}
+	parser.pos++
+
+	parser.comparisons++
+	if parser.comparisons > maxFilterComparisons {
+		return domain.FilterNode{}, fmt.Errorf("invalid q: expression has more than %d conditions", maxFilterComparisons)
+	}
+
+	value, err := parser.parseValue(field, valueTok.text)
+	if err != nil {
+		return domain.FilterNode{}, fmt.Errorf("invalid q: %v at position %d", err, valueTok.pos+1)
+	}
+
+	return domain.FilterNode{
+		Kind:     domain.FilterCompare,
+		Field:    field,
+		Operator: opTok.text,
+		Value:    value,
+	}, nil
+}
+
+func (parser *filterParser) parseValue(field, raw string) (interface{}, error) {
+	switch field {
+	case "amount":
+		amount, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, fmt.Errorf("amount must be a number, got %q", raw)
+		}
+		return amount, nil
+	case "category_id":
+		id, err := strconv.Atoi(raw)
+		if err != nil || id <= 0 {
+			return nil, fmt.Errorf("category_id must be a positive integer, got %q", raw)
+		}
+		return id, nil
+	case "date":
+		loc, err := time.LoadLocation(parser.tz)
+		if err != nil {
+			loc = time.UTC
+		}
+		date, err := time.ParseInLocation("2006-01-02", raw, loc)
+		if err != nil {
+			return nil, fmt.Errorf("date must use YYYY-MM-DD, got %q", raw)
+		}
+		return date.UTC(), nil
+	default:
+		if raw == "" {
+			return nil, fmt.Errorf("%s must not be empty", field)
+		}
+		return raw, nil
+	}
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
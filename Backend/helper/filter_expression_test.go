@@ -0,0 +1,152 @@
+package helper
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
+)
+
+// formatFilterNode renders a tree as "(AND a>1 (NOT b=2))" so expectations
+// stay readable.
+func formatFilterNode(node domain.FilterNode) string {
+	switch node.Kind {
+	case domain.FilterAnd, domain.FilterOr, domain.FilterNot:
+		name := map[domain.FilterNodeKind]string{domain.FilterAnd: "AND", domain.FilterOr: "OR", domain.FilterNot: "NOT"}[node.Kind]
+		parts := []string{name}
+		for _, child := range node.Children {
+			parts = append(parts, formatFilterNode(child))
+		}
+		return "(" + strings.Join(parts, " ") + ")"
+	}
+
+	value := fmt.Sprint(node.Value)
+	if t, ok := node.Value.(time.Time); ok {
+		value = t.Format(time.RFC3339)
+	}
+	return node.Field + node.Operator + value
+}
+
+func TestParseFilterExpression(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		tz    string
+		want  string
+	}{
+		{"single comparison", "amount>100", "", "amount>100"},
+		{"AND binds tighter than OR", "amount>1 OR amount<2 AND category:food", "", "(OR amount>1 (AND amount<2 category:food))"},
+		{"parentheses override precedence", "(amount>1 OR amount<2) AND category:food", "", "(AND (OR amount>1 amount<2) category:food)"},
+		{"chained AND stays flat", "amount>1 AND amount<9 AND category_id=3", "", "(AND amount>1 amount<9 category_id=3)"},
+		{"keywords are case-insensitive", "amount>1 and not category:food or amount=5", "", "(OR (AND amount>1 (NOT category:food)) amount=5)"},
+		{"NOT applies to the next operand only", "NOT amount>1 AND amount<9", "", "(AND (NOT amount>1) amount<9)"},
+		{"NOT of a group", "NOT (category:food OR category:fun)", "", "(NOT (OR category:food category:fun))"},
+		{"double NOT", "NOT NOT amount>1", "", "(NOT (NOT amount>1))"},
+		{"quoted value keeps spaces and keywords", `description:"coffee AND cake"`, "", "description:coffee AND cake"},
+		{"quoted value with operators", `category="a>b (c)"`, "", "category=a>b (c)"},
+		{"two-character operators", "amount>=1 AND amount<=2 AND amount!=3", "", "(AND amount>=1 amount<=2 amount!=3)"},
+		{"field names are case-insensitive", "AMOUNT>1", "", "amount>1"},
+		{"created_at is an alias of date", "created_at>=2024-01-01", "", "date>=2024-01-01T00:00:00Z"},
+		{"date in the user's time zone", "date=2024-01-01", "Asia/Jakarta", "date=2023-12-31T17:00:00Z"},
+		{"unknown time zone falls back to UTC", "date=2024-01-01", "Mars/Base", "date=2024-01-01T00:00:00Z"},
+		{"spaces around operators", "amount > 100", "", "amount>100"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node, err := ParseFilterExpression(tt.input, tt.tz)
+			if err != nil {
+				t.Fatalf("ParseFilterExpression(%q): %v", tt.input, err)
+			}
+			if got := formatFilterNode(*node); got != tt.want {
+				t.Errorf("ParseFilterExpression(%q) = %s, want %s", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseFilterExpressionValueTypes(t *testing.T) {
+	node, err := ParseFilterExpression("amount>1.5 AND category_id=7 AND description=x", "")
+	if err != nil {
+		t.Fatalf("ParseFilterExpression: %v", err)
+	}
+
+	if _, ok := node.Children[0].Value.(float64); !ok {
+		t.Errorf("amount value is %T, want float64", node.Children[0].Value)
+	}
+	if _, ok := node.Children[1].Value.(int); !ok {
+		t.Errorf("category_id value is %T, want int", node.Children[1].Value)
+	}
+	if _, ok := node.Children[2].Value.(string); !ok {
+		t.Errorf("description value is %T, want string", node.Children[2].Value)
+	}
+}
+
+func TestParseFilterExpressionRejects(t *testing.T) {
+	nested := func(depth int) string {
+		return strings.Repeat("(", depth) + "amount>1" + strings.Repeat(")", depth)
+	}
+	conditions := func(n int) string {
+		parts := make([]string, n)
+		for i := range parts {
+			parts[i] = "amount>1"
+		}
+		return strings.Join(parts, " OR ")
+	}
+
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"empty", "   ", "expression is empty"},
+		{"too long", "description:" + strings.Repeat("a", maxFilterExpressionLength), "at most 500 characters"},
+		{"nested too deep", nested(maxFilterExpressionDepth + 1), "nested more than 10 levels"},
+		{"NOT nested too deep", strings.Repeat("NOT ", maxFilterExpressionDepth+1) + "amount>1", "nested more than 10 levels"},
+		{"too many conditions", conditions(maxFilterComparisons + 1), "more than 30 conditions"},
+		{"unknown field", "user_id=1", `unknown field "user_id"`},
+		{"SQL in field position", "amount>1 OR 1=1", `unknown field "1"`},
+		{"operator not allowed for field", "category>food", `operator ">" is not supported for category`},
+		{"contains on amount", "amount:5", `operator ":" is not supported for amount`},
+		{"not-equal on date", "date!=2024-01-01", `operator "!=" is not supported for date`},
+		{"bare bang", "amount!5", `did you mean "!="`},
+		{"unexpected character", "amount>1; drop table expenses", `unexpected character ';'`},
+		{"unterminated string", `description:"coffee`, "unterminated string"},
+		{"missing closing parenthesis", "(amount>1", "missing closing parenthesis"},
+		{"stray closing parenthesis", "amount>1)", `unexpected ")"`},
+		{"missing operator", "amount", `expected an operator after "amount"`},
+		{"missing value", "amount>", "expected a value after amount>"},
+		{"dangling AND", "amount>1 AND", "unexpected end of expression"},
+		{"two comparisons without keyword", "amount>1 amount<2", `unexpected "amount"`},
+		{"amount not a number", "amount>ten", "amount must be a number"},
+		{"category_id not positive", "category_id=0", "category_id must be a positive integer"},
+		{"date in another format", "date>01/02/2024", "invalid q:"},
+		{"impossible date", "date=2024-02-30", "date must use YYYY-MM-DD"},
+		{"empty quoted value", `description=""`, "description must not be empty"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseFilterExpression(tt.input, "")
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("ParseFilterExpression(%q) error = %v, want one containing %q", tt.input, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseFilterExpressionLimitsAreInclusive(t *testing.T) {
+	inputs := []string{
+		strings.Repeat("(", maxFilterExpressionDepth) + "amount>1" + strings.Repeat(")", maxFilterExpressionDepth),
+		strings.TrimSuffix(strings.Repeat("amount>1 OR ", maxFilterComparisons), " OR "),
+		"description:" + strings.Repeat("a", maxFilterExpressionLength-len("description:")),
+	}
+
+	for _, input := range inputs {
+		if _, err := ParseFilterExpression(input, ""); err != nil {
+			t.Errorf("ParseFilterExpression(%.40q...) at the limit: %v", input, err)
+		}
+	}
+}
@@ -1,6 +1,12 @@
 package helper
 
 import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/ryhnfhrza/Expense-Tracker/classifier"
 	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
 	"github.com/ryhnfhrza/Expense-Tracker/model/web"
 )
@@ -44,6 +50,7 @@ func ToExpenseResponse(expense domain.Expense, categoryName string) web.ExpenseR
 		Description:  expense.Description,
 		Amount:       expense.Amount,
 		CreatedAt:    expense.CreatedAt,
+		Version:      expense.Version,
 	}
 }
 
@@ -62,41 +69,580 @@ func ToExpenseResponses(expenses []domain.Expense, categories []domain.Category)
 	return expenseResponses
 }
 
-func ToExpenseRecordResponse(record domain.ExpenseRecord) web.ExpenseRecordResponse {
+func ToExpenseRecordResponse(record domain.ExpenseRecord, preferences domain.UserPreferences) web.ExpenseRecordResponse {
 	return web.ExpenseRecordResponse{
-		Amount:      record.Amount,
-		Date:        record.Date,
-		Description: record.Description,
+		Amount:          record.Amount,
+		AmountFormatted: FormatAmount(record.Amount, preferences),
+		Date:            FormatDate(record.CreatedAt, preferences),
+		Description:     record.Description,
 	}
 }
 
-func ToExpenseRecordResponses(records []domain.ExpenseRecord) []web.ExpenseRecordResponse {
+func ToExpenseRecordResponses(records []domain.ExpenseRecord, preferences domain.UserPreferences) []web.ExpenseRecordResponse {
 	var responses []web.ExpenseRecordResponse
 	for _, r := range records {
-		responses = append(responses, ToExpenseRecordResponse(r))
+		responses = append(responses, ToExpenseRecordResponse(r, preferences))
 	}
 	return responses
 }
 
-func ToSummaryResponse(detail domain.SummaryDetail) web.SummaryDetailResponse {
+func ToSummaryResponse(detail domain.SummaryDetail, preferences domain.UserPreferences) web.SummaryDetailResponse {
 	return web.SummaryDetailResponse{
-		CategoryName: detail.CategoryName,
-		Records:      ToExpenseRecordResponses(detail.Records),
-		Total:        detail.Total,
+		CategoryName:   detail.CategoryName,
+		Records:        ToExpenseRecordResponses(detail.Records, preferences),
+		Total:          detail.Total,
+		TotalFormatted: FormatAmount(detail.Total, preferences),
 	}
 }
 
-func ToSummaryResponses(details []domain.SummaryDetail) []web.SummaryDetailResponse {
+func ToSummaryResponses(details []domain.SummaryDetail, preferences domain.UserPreferences) []web.SummaryDetailResponse {
 	var response []web.SummaryDetailResponse
 	for _, d := range details {
-		response = append(response, ToSummaryResponse(d))
+		response = append(response, ToSummaryResponse(d, preferences))
 	}
 	return response
 }
 
-func ToSummaryResult(totalAll float64, details []domain.SummaryDetail) web.SummaryResponse {
+func ToSummaryResult(totalAll float64, details []domain.SummaryDetail, preferences domain.UserPreferences) web.SummaryResponse {
 	return web.SummaryResponse{
-		TotalAll:   totalAll,
-		Categories: ToSummaryResponses(details),
+		Currency:          preferences.Currency,
+		TotalAll:          totalAll,
+		TotalAllFormatted: FormatAmount(totalAll, preferences),
+		Categories:        ToSummaryResponses(details, preferences),
+	}
+}
+
+func ToExpenseSearchResultResponse(result domain.ExpenseSearchResult, terms []domain.SearchTerm) web.ExpenseSearchResultResponse {
+	return web.ExpenseSearchResultResponse{
+		Id:           result.Expense.Id,
+		CategoryId:   result.Expense.CategoryId,
+		CategoryName: result.CategoryName,
+		Description:  result.Expense.Description,
+		Snippet:      BuildSearchSnippet(result.Expense.Description, terms),
+		Amount:       result.Expense.Amount,
+		CreatedAt:    result.Expense.CreatedAt,
+		Score:        result.Score,
+	}
+}
+
+func ToExpenseSearchResponse(results []domain.ExpenseSearchResult, query domain.ExpenseSearchQuery, total int) web.ExpenseSearchResponse {
+	responses := []web.ExpenseSearchResultResponse{}
+	for _, result := range results {
+		responses = append(responses, ToExpenseSearchResultResponse(result, query.Terms))
+	}
+
+	return web.ExpenseSearchResponse{
+		Total:   total,
+		Limit:   query.Limit,
+		Offset:  query.Offset,
+		Results: responses,
+	}
+}
+
+func ToSavedViewResponse(view domain.SavedView) web.SavedViewResponse {
+	return web.SavedViewResponse{
+		Id:        view.Id,
+		Name:      view.Name,
+		Kind:      view.Kind,
+		Filter:    view.Filter,
+		SortBy:    view.SortBy,
+		Order:     view.Order,
+		IsDefault: view.IsDefault,
+		CreatedAt: view.CreatedAt,
+		UpdatedAt: view.UpdatedAt,
+	}
+}
+
+func ToSavedViewResponses(views []domain.SavedView) []web.SavedViewResponse {
+	responses := []web.SavedViewResponse{}
+	for _, view := range views {
+		responses = append(responses, ToSavedViewResponse(view))
+	}
+	return responses
+}
+
+func ToParsedExpenseResponse(parsed domain.ParsedExpense) web.ParsedExpenseResponse {
+	response := web.ParsedExpenseResponse{
+		Amount:          parsed.Amount,
+		AmountText:      parsed.AmountText,
+		CreatedAt:       parsed.CreatedAt.UTC(),
+		DateText:        parsed.DateText,
+		CategoryName:    parsed.CategoryName,
+		CategoryKeyword: parsed.CategoryKeyword,
+		Description:     parsed.Description,
+	}
+	if parsed.CategoryId > 0 {
+		categoryId := parsed.CategoryId
+		response.CategoryId = &categoryId
+	}
+	return response
+}
+
+func ToCategoryRuleResponse(rule domain.CategoryRule, categoryName string) web.CategoryRuleResponse {
+	return web.CategoryRuleResponse{
+		Id:                  rule.Id,
+		Name:                rule.Name,
+		CategoryId:          rule.CategoryId,
+		CategoryName:        categoryName,
+		DescriptionContains: rule.DescriptionContains,
+		DescriptionPattern:  rule.DescriptionPattern,
+		MinAmount:           rule.MinAmount,
+		MaxAmount:           rule.MaxAmount,
+		Priority:            rule.Priority,
+		CreatedAt:           rule.CreatedAt,
+	}
+}
+
+func ToCategoryRuleResponses(rules []domain.CategoryRule, categories []domain.Category) []web.CategoryRuleResponse {
+	categoryMap := make(map[int]string)
+	for _, c := range categories {
+		categoryMap[c.Id] = c.Name
+	}
+
+	responses := []web.CategoryRuleResponse{}
+	for _, rule := range rules {
+		responses = append(responses, ToCategoryRuleResponse(rule, categoryMap[rule.CategoryId]))
+	}
+	return responses
+}
+
+func ToCategoryRuleApplyResponse(dryRun bool, checked int, changes []domain.CategoryRuleChange) web.CategoryRuleApplyResponse {
+	response := web.CategoryRuleApplyResponse{
+		DryRun:  dryRun,
+		Checked: checked,
+		Changes: []web.CategoryRuleChangeResponse{},
+	}
+	if !dryRun {
+		response.Updated = len(changes)
+	}
+
+	for _, change := range changes {
+		response.Changes = append(response.Changes, web.CategoryRuleChangeResponse{
+			ExpenseId:      change.ExpenseId,
+			FromCategoryId: change.FromCategoryId,
+			ToCategoryId:   change.ToCategoryId,
+			RuleId:         change.RuleId,
+		})
+	}
+	return response
+}
+
+// ToCategorySuggestResponse keeps the predictions whose category still exists
+// and attaches its name.
+func ToCategorySuggestResponse(trainedOn int, predictions []classifier.Prediction, categories []domain.Category) web.CategorySuggestResponse {
+	names := make(map[int]string, len(categories))
+	for _, category := range categories {
+		names[category.Id] = category.Name
+	}
+
+	suggestions := []web.CategorySuggestionResponse{}
+	for _, prediction := range predictions {
+		name, ok := names[prediction.CategoryId]
+		if !ok {
+			continue
+		}
+		suggestions = append(suggestions, web.CategorySuggestionResponse{
+			CategoryId:   prediction.CategoryId,
+			CategoryName: name,
+			Confidence:   prediction.Confidence,
+		})
+	}
+
+	return web.CategorySuggestResponse{
+		TrainedOn:   trainedOn,
+		Suggestions: suggestions,
+	}
+}
+
+func ToStatementImportResponse(result domain.StatementImportResult) web.StatementImportResponse {
+	response := web.StatementImportResponse{
+		Format:         result.Format,
+		DryRun:         result.DryRun,
+		Debits:         result.Debits,
+		SkippedCredits: result.SkippedCredits,
+		Items:          []web.StatementImportItemResponse{},
+	}
+
+	for _, item := range result.Items {
+		switch item.Status {
+		case domain.StatementItemImported:
+			response.Imported++
+		case domain.StatementItemDuplicate:
+			response.Duplicates++
+		}
+
+		response.Items = append(response.Items, web.StatementImportItemResponse{
+			ExternalId:     item.Transaction.ExternalId,
+			PostedAt:       item.Transaction.PostedAt,
+			Amount:         item.Transaction.Amount,
+			Currency:       item.Transaction.Currency,
+			Description:    item.Transaction.Description,
+			Status:         item.Status,
+			ExpenseId:      item.ExpenseId,
+			CategoryId:     item.CategoryId,
+			DuplicateOf:    item.DuplicateOf,
+			DuplicateScore: item.DuplicateScore,
+		})
+	}
+
+	return response
+}
+
+func ToAnomalyResponse(anomaly domain.Anomaly, categoryName string) web.AnomalyResponse {
+	var message string
+	switch anomaly.Kind {
+	case domain.AnomalyAmountSpike:
+		message = fmt.Sprintf("%.1fx the usual amount for this category", anomaly.Observed/anomaly.Baseline)
+	case domain.AnomalyFrequencySpike:
+		message = fmt.Sprintf("%d expenses in this category in the last 7 days, usually %.1f a week", int(anomaly.Observed), anomaly.Baseline)
+	case domain.AnomalyDormantCategory:
+		message = fmt.Sprintf("first expense in this category for %d days", int(anomaly.Baseline))
+	}
+
+	return web.AnomalyResponse{
+		Kind:         anomaly.Kind,
+		Message:      message,
+		ExpenseId:    anomaly.Expense.Id,
+		CategoryId:   anomaly.Expense.CategoryId,
+		CategoryName: categoryName,
+		Description:  anomaly.Expense.Description,
+		Amount:       anomaly.Expense.Amount,
+		Baseline:     math.Round(anomaly.Baseline*100) / 100,
+		Observed:     anomaly.Observed,
+		OccurredAt:   anomaly.OccurredAt,
+	}
+}
+
+func ToAnomalyListResponse(from, to time.Time, anomalies []domain.Anomaly, categories []domain.Category) web.AnomalyListResponse {
+	names := make(map[int]string, len(categories))
+	for _, category := range categories {
+		names[category.Id] = category.Name
+	}
+
+	response := web.AnomalyListResponse{From: from, To: to, Anomalies: []web.AnomalyResponse{}}
+	for _, anomaly := range anomalies {
+		response.Anomalies = append(response.Anomalies, ToAnomalyResponse(anomaly, names[anomaly.Expense.CategoryId]))
+	}
+	return response
+}
+
+func ToForecastLineResponse(line domain.ForecastLine) web.ForecastLineResponse {
+	round := func(v float64) float64 { return math.Round(v*100) / 100 }
+	return web.ForecastLineResponse{
+		CategoryId:   line.CategoryId,
+		CategoryName: line.CategoryName,
+		SpentToDate:  round(line.SpentToDate),
+		Recurring:    round(line.Recurring),
+		Point:        round(line.Point),
+		Low:          round(line.Low),
+		High:         round(line.High),
+	}
+}
+
+func ToMonthForecastResponse(month domain.MonthForecast) web.MonthForecastResponse {
+	response := web.MonthForecastResponse{
+		Month:      month.Month.Format("2006-01"),
+		Categories: []web.ForecastLineResponse{},
+		Total:      ToForecastLineResponse(month.Total),
+	}
+	for _, line := range month.Lines {
+		response.Categories = append(response.Categories, ToForecastLineResponse(line))
+	}
+	return response
+}
+
+func ToForecastResponse(forecast domain.Forecast) web.ForecastResponse {
+	response := web.ForecastResponse{
+		Timezone:    forecast.AsOf.Location().String(),
+		AsOf:        forecast.AsOf,
+		DaysElapsed: forecast.DaysElapsed,
+		DaysInMonth: forecast.DaysInMonth,
+		Current:     ToMonthForecastResponse(forecast.Current),
+		Next:        []web.MonthForecastResponse{},
+	}
+	for _, month := range forecast.Next {
+		response.Next = append(response.Next, ToMonthForecastResponse(month))
+	}
+	return response
+}
+
+// ToSubscriptionListResponse attaches category names and the id of the
+// recurring expense a subscription was converted to, if any. Only active
+// subscriptions count towards the annualized total.
+func ToSubscriptionListResponse(subscriptions []domain.Subscription, categories []domain.Category, recurring []domain.RecurringExpense) web.SubscriptionListResponse {
+	names := make(map[int]string, len(categories))
+	for _, category := range categories {
+		names[category.Id] = category.Name
+	}
+	converted := make(map[string]int, len(recurring))
+	for _, r := range recurring {
+		converted[r.SourceKey] = r.Id
 	}
+
+	response := web.SubscriptionListResponse{Subscriptions: []web.SubscriptionResponse{}}
+	for _, s := range subscriptions {
+		item := web.SubscriptionResponse{
+			Key:            s.Key,
+			CategoryId:     s.CategoryId,
+			CategoryName:   names[s.CategoryId],
+			Description:    s.Description,
+			Cadence:        s.Cadence,
+			Amount:         s.Amount,
+			AnnualizedCost: s.AnnualizedCost,
+			Occurrences:    s.Occurrences,
+			FirstSeen:      s.FirstSeen,
+			LastSeen:       s.LastSeen,
+			NextExpected:   s.NextExpected,
+			Active:         s.Active,
+		}
+		if s.PriceChange != nil {
+			item.PriceChange = &web.PriceChangeResponse{
+				Previous:  s.PriceChange.Previous,
+				Current:   s.PriceChange.Current,
+				Percent:   math.Round((s.PriceChange.Current-s.PriceChange.Previous)/s.PriceChange.Previous*1000) / 10,
+				ChangedAt: s.PriceChange.ChangedAt,
+			}
+		}
+		if id, ok := converted[s.Key]; ok {
+			item.RecurringId = &id
+		}
+		if s.Active {
+			response.AnnualizedTotal += s.AnnualizedCost
+		}
+		response.Subscriptions = append(response.Subscriptions, item)
+	}
+	response.AnnualizedTotal = math.Round(response.AnnualizedTotal*100) / 100
+
+	return response
+}
+
+func ToRecurringExpenseResponse(recurring domain.RecurringExpense, categoryName string) web.RecurringExpenseResponse {
+	return web.RecurringExpenseResponse{
+		Id:           recurring.Id,
+		CategoryId:   recurring.CategoryId,
+		CategoryName: categoryName,
+		Description:  recurring.Description,
+		Amount:       recurring.Amount,
+		Cadence:      recurring.Cadence,
+		NextRunAt:    recurring.NextRunAt,
+		SourceKey:    recurring.SourceKey,
+		CreatedAt:    recurring.CreatedAt,
+	}
+}
+
+func ToRecurringExpenseResponses(recurring []domain.RecurringExpense, categories []domain.Category) []web.RecurringExpenseResponse {
+	names := make(map[int]string, len(categories))
+	for _, category := range categories {
+		names[category.Id] = category.Name
+	}
+
+	responses := []web.RecurringExpenseResponse{}
+	for _, r := range recurring {
+		responses = append(responses, ToRecurringExpenseResponse(r, names[r.CategoryId]))
+	}
+	return responses
+}
+
+func ToAuditEntryResponse(entry domain.AuditEntry) web.AuditEntryResponse {
+	response := web.AuditEntryResponse{
+		Id:         entry.Id,
+		EntityType: entry.EntityType,
+		EntityId:   entry.EntityId,
+		Action:     entry.Action,
+		ActorId:    entry.ActorId,
+		RequestId:  entry.RequestId,
+		Before:     entry.Before,
+		After:      entry.After,
+		CreatedAt:  entry.CreatedAt,
+	}
+	if len(response.Before) == 0 {
+		response.Before = json.RawMessage("null")
+	}
+	if len(response.After) == 0 {
+		response.After = json.RawMessage("null")
+	}
+
+	return response
+}
+
+// ToAuditListResponse sets NextBeforeId only when the page is full, so a
+// client keeps paging until it is absent.
+func ToAuditListResponse(entries []domain.AuditEntry, limit int) web.AuditListResponse {
+	response := web.AuditListResponse{Entries: []web.AuditEntryResponse{}}
+	for _, entry := range entries {
+		response.Entries = append(response.Entries, ToAuditEntryResponse(entry))
+	}
+	if len(entries) == limit && limit > 0 {
+		response.NextBeforeId = entries[len(entries)-1].Id
+	}
+
+	return response
+}
+
+// ToTrashResponse lists the trash; purge_at is when the purge job will
+// delete each item for good.
+func ToTrashResponse(expenses []domain.TrashedExpense, categories []domain.TrashedCategory, retention time.Duration) web.TrashResponse {
+	response := web.TrashResponse{
+		RetentionDays: int(retention / (24 * time.Hour)),
+		Expenses:      []web.TrashedExpenseResponse{},
+		Categories:    []web.TrashedCategoryResponse{},
+	}
+
+	for _, trashed := range expenses {
+		response.Expenses = append(response.Expenses, web.TrashedExpenseResponse{
+			ExpenseResponse: ToExpenseResponse(trashed.Expense, trashed.CategoryName),
+			DeletedAt:       trashed.DeletedAt,
+			PurgeAt:         trashed.DeletedAt.Add(retention),
+		})
+	}
+
+	for _, trashed := range categories {
+		response.Categories = append(response.Categories, web.TrashedCategoryResponse{
+			CategoryResponse: ToCategoryResponse(trashed.Category),
+			ExpenseCount:     trashed.ExpenseCount,
+			DeletedAt:        trashed.DeletedAt,
+			PurgeAt:          trashed.DeletedAt.Add(retention),
+		})
+	}
+
+	return response
+}
+
+func ToSyncExpenseChange(synced domain.SyncedExpense) web.SyncExpenseChange {
+	change := web.SyncExpenseChange{
+		Id:       synced.Expense.Id,
+		ClientId: synced.ClientId,
+		Deleted:  synced.DeletedAt != nil,
+	}
+	if !change.Deleted {
+		expense := ToExpenseResponse(synced.Expense, synced.CategoryName)
+		change.Expense = &expense
+	}
+
+	return change
+}
+
+func ToSyncCategoryChange(synced domain.SyncedCategory) web.SyncCategoryChange {
+	change := web.SyncCategoryChange{
+		Id:       synced.Category.Id,
+		ClientId: synced.ClientId,
+		Deleted:  synced.DeletedAt != nil,
+	}
+	if !change.Deleted {
+		category := ToCategoryResponse(synced.Category)
+		change.Category = &category
+	}
+
+	return change
+}
+
+func ToWebhookResponse(webhook domain.Webhook) web.WebhookResponse {
+	return web.WebhookResponse{
+		Id:        webhook.Id,
+		Url:       webhook.Url,
+		Events:    webhook.Events,
+		CreatedAt: webhook.CreatedAt,
+	}
+}
+
+func ToWebhookResponses(webhooks []domain.Webhook) []web.WebhookResponse {
+	responses := []web.WebhookResponse{}
+	for _, webhook := range webhooks {
+		responses = append(responses, ToWebhookResponse(webhook))
+	}
+	return responses
+}
+
+func ToWebhookDeliveryResponse(delivery domain.WebhookDelivery) web.WebhookDeliveryResponse {
+	response := web.WebhookDeliveryResponse{
+		Id:             delivery.Id,
+		WebhookId:      delivery.WebhookId,
+		EventId:        delivery.EventId,
+		EventType:      delivery.EventType,
+		Status:         delivery.Status,
+		Attempts:       delivery.Attempts,
+		LastStatusCode: delivery.LastStatusCode,
+		LastError:      delivery.LastError,
+		DeliveredAt:    delivery.DeliveredAt,
+		CreatedAt:      delivery.CreatedAt,
+	}
+	if delivery.Status == domain.WebhookDeliveryPending {
+		nextAttemptAt := delivery.NextAttemptAt
+		response.NextAttemptAt = &nextAttemptAt
+	}
+
+	return response
+}
+
+func ToWebhookDeliveryListResponse(deliveries []domain.WebhookDelivery, limit int) web.WebhookDeliveryListResponse {
+	response := web.WebhookDeliveryListResponse{Deliveries: []web.WebhookDeliveryResponse{}}
+	for _, delivery := range deliveries {
+		response.Deliveries = append(response.Deliveries, ToWebhookDeliveryResponse(delivery))
+	}
+	if len(deliveries) == limit && limit > 0 {
+		response.NextBeforeId = deliveries[len(deliveries)-1].Id
+	}
+
+	return response
+}
+
+func ToAccessTokenResponse(token domain.AccessToken) web.AccessTokenResponse {
+	return web.AccessTokenResponse{
+		Id:         token.Id,
+		Name:       token.Name,
+		Prefix:     token.Prefix,
+		Scopes:     token.Scopes,
+		LastUsedAt: token.LastUsedAt,
+		ExpiresAt:  token.ExpiresAt,
+		CreatedAt:  token.CreatedAt,
+	}
+}
+
+func ToAccessTokenResponses(tokens []domain.AccessToken) []web.AccessTokenResponse {
+	responses := []web.AccessTokenResponse{}
+	for _, token := range tokens {
+		responses = append(responses, ToAccessTokenResponse(token))
+	}
+	return responses
+}
+
+func ToUserProfileResponse(user domain.User, twoFactorEnabled bool) web.UserProfileResponse {
+	return web.UserProfileResponse{
+		Id:                  user.Id,
+		Username:            user.Username,
+		Email:               user.Email,
+		HasPassword:         user.PasswordHash != "",
+		TwoFactorEnabled:    twoFactorEnabled,
+		DeletionScheduledAt: user.DeletionScheduledAt,
+	}
+}
+
+func ToUserPreferencesResponse(preferences domain.UserPreferences) web.UserPreferencesResponse {
+	return web.UserPreferencesResponse{
+		Timezone:   preferences.Timezone,
+		Locale:     preferences.Locale,
+		Currency:   preferences.Currency,
+		WeekStart:  preferences.WeekStart,
+		DateFormat: preferences.DateFormat,
+	}
+}
+
+func ToDataExportResponse(export domain.DataExport) web.DataExportResponse {
+	return web.DataExportResponse{
+		Id:          export.Id,
+		Status:      export.Status,
+		SizeBytes:   export.SizeBytes,
+		Error:       export.Error,
+		RequestedAt: export.RequestedAt,
+		CompletedAt: export.CompletedAt,
+		ExpiresAt:   export.ExpiresAt,
+	}
+}
+
+func ToDataExportResponses(exports []domain.DataExport) []web.DataExportResponse {
+	responses := []web.DataExportResponse{}
+	for _, export := range exports {
+		responses = append(responses, ToDataExportResponse(export))
+	}
+	return responses
 }
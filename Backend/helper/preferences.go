@@ -0,0 +1,112 @@
+package helper
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
+)
+
+// currencySymbols are the symbols shown for common currencies; others are
+// shown by their code.
+var currencySymbols = map[string]string{
+	"IDR": "Rp",
+	"USD": "$",
+	"EUR": "€",
+	"GBP": "£",
+	"JPY": "¥",
+	"SGD": "S$",
+	"MYR": "RM",
+	"AUD": "A$",
+}
+
+// decimalCommaSeparators holds the thousands separator of languages that
+// write decimals with a comma. Every other language gets 1,234.56.
+var decimalCommaSeparators = map[string]string{
+	"id": ".", "de": ".", "es": ".", "it": ".", "nl": ".", "pt": ".", "tr": ".", "da": ".", "vi": ".",
+	"fr": " ", "ru": " ", "pl": " ", "sv": " ", "nb": " ", "fi": " ", "cs": " ",
+}
+
+// PreferredLocation loads the user's time zone, falling back to the default
+// one if it no longer loads.
+func PreferredLocation(preferences domain.UserPreferences) *time.Location {
+	if loc, err := time.LoadLocation(preferences.Timezone); err == nil {
+		return loc
+	}
+	loc, err := time.LoadLocation(domain.DefaultTimezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// FormatDate writes the day t falls on in the user's time zone, in the
+// user's date format.
+func FormatDate(t time.Time, preferences domain.UserPreferences) string {
+	layout, ok := domain.DateLayouts[preferences.DateFormat]
+	if !ok {
+		layout = domain.DateLayouts[domain.DefaultDateFormat]
+	}
+	return t.In(PreferredLocation(preferences)).Format(layout)
+}
+
+// FormatAmount writes an amount in the user's currency with the separators
+// of the user's locale, such as "Rp 25.000" or "$1,234.50". Cents are only
+// shown when there are any.
+func FormatAmount(amount float64, preferences domain.UserPreferences) string {
+	language := strings.ToLower(strings.SplitN(preferences.Locale, "-", 2)[0])
+	group, decimal := ",", "."
+	if separator, ok := decimalCommaSeparators[language]; ok {
+		group, decimal = separator, ","
+	}
+
+	cents := int64(math.Round(math.Abs(amount) * 100))
+	digits := strconv.FormatInt(cents/100, 10)
+
+	var sb strings.Builder
+	if amount < 0 && cents > 0 {
+		sb.WriteString("-")
+	}
+	symbol, ok := currencySymbols[preferences.Currency]
+	if !ok {
+		symbol = preferences.Currency
+	}
+	sb.WriteString(symbol)
+	// Letter symbols like "Rp" or "CHF" are set apart from the digits;
+	// signs like "$" or "€" are not.
+	if last, _ := utf8.DecodeLastRuneInString(symbol); unicode.IsLetter(last) {
+		sb.WriteString(" ")
+	}
+
+	for i, digit := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			sb.WriteString(group)
+		}
+		sb.WriteRune(digit)
+	}
+	if cents%100 != 0 {
+		sb.WriteString(decimal)
+		sb.WriteString(strconv.FormatInt(cents%100+100, 10)[1:])
+	}
+
+	return sb.String()
+}
+
+// WeekRange returns the week around day, starting on the user's first day
+// of the week, as [from, to) in the user's time zone.
+func WeekRange(day time.Time, preferences domain.UserPreferences) (time.Time, time.Time) {
+	weekStart, ok := domain.WeekStarts[preferences.WeekStart]
+	if !ok {
+		weekStart = domain.WeekStarts[domain.DefaultWeekStart]
+	}
+
+	loc := PreferredLocation(preferences)
+	date := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, loc)
+	from := date.AddDate(0, 0, -((int(date.Weekday()) - int(weekStart) + 7) % 7))
+
+	return from, from.AddDate(0, 0, 7)
+}
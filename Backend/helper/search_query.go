@@ -0,0 +1,110 @@
+package helper
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
+)
+
+const maxSearchQueryLength = 200
+
+func ParseExpenseSearchQuery(query url.Values) (domain.ExpenseSearchQuery, error) {
+	var search domain.ExpenseSearchQuery
+
+	q := strings.TrimSpace(query.Get("q"))
+	if q == "" {
+		return search, fmt.Errorf("q is required")
+	}
+	if len(q) > maxSearchQueryLength {
+		return search, fmt.Errorf("q must be at most %d characters", maxSearchQueryLength)
+	}
+
+	search.Terms = parseSearchTerms(q)
+	if len(search.Terms) == 0 {
+		return search, fmt.Errorf("q does not contain any searchable word")
+	}
+
+	if limitStr := query.Get("limit"); limitStr != "" {
+		l, err := strconv.Atoi(limitStr)
+		if err != nil || l < 0 {
+			return search, fmt.Errorf("invalid limit")
+		}
+		search.Limit = l
+	}
+
+	if offsetStr := query.Get("offset"); offsetStr != "" {
+		o, err := strconv.Atoi(offsetStr)
+		if err != nil || o < 0 {
+			return search, fmt.Errorf("invalid offset")
+		}
+		search.Offset = o
+	}
+
+	return search, nil
+}
+
+// parseSearchTerms splits the raw query into words, "quoted phrases" and
+// prefix words ending in *. Every other boolean-mode operator is dropped so
+// user input can never change the meaning of the MATCH expression.
+func parseSearchTerms(q string) []domain.SearchTerm {
+	var terms []domain.SearchTerm
+
+	for len(q) > 0 {
+		q = strings.TrimLeftFunc(q, unicode.IsSpace)
+		if q == "" {
+			break
+		}
+
+		if q[0] == '"' {
+			end := strings.IndexByte(q[1:], '"')
+			var raw string
+			if end < 0 {
+				raw, q = q[1:], ""
+			} else {
+				raw, q = q[1:end+1], q[end+2:]
+			}
+
+			words := strings.Fields(cleanSearchWord(raw))
+			switch len(words) {
+			case 0:
+			case 1:
+				terms = append(terms, domain.SearchTerm{Text: words[0]})
+			default:
+				terms = append(terms, domain.SearchTerm{Text: strings.Join(words, " "), Phrase: true})
+			}
+			continue
+		}
+
+		end := strings.IndexFunc(q, func(r rune) bool { return unicode.IsSpace(r) || r == '"' })
+		var raw string
+		if end < 0 {
+			raw, q = q, ""
+		} else {
+			raw, q = q[:end], q[end:]
+		}
+
+		prefix := strings.HasSuffix(raw, "*")
+		before := len(terms)
+		for _, word := range strings.Fields(cleanSearchWord(raw)) {
+			terms = append(terms, domain.SearchTerm{Text: word})
+		}
+		if prefix && len(terms) > before {
+			terms[len(terms)-1].Prefix = true
+		}
+	}
+
+	return terms
+}
+
+func cleanSearchWord(raw string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return ' '
+	}, raw)
+}
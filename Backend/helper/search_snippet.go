@@ -0,0 +1,123 @@
+package helper
+
+import (
+	"html"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
+)
+
+const (
+	snippetLength  = 120
+	snippetContext = 40
+)
+
+// BuildSearchSnippet cuts a window of text around the first match and wraps
+// every matched term in <mark>. The text is HTML-escaped so the snippet can be
+// rendered as-is by the dashboard.
+func BuildSearchSnippet(text string, terms []domain.SearchTerm) string {
+	runes := []rune(text)
+	lower := make([]rune, len(runes))
+	for i, r := range runes {
+		lower[i] = unicode.ToLower(r)
+	}
+
+	var matches [][2]int
+	for _, term := range terms {
+		matches = append(matches, findTerm(lower, []rune(term.Text), term.Prefix)...)
+	}
+	matches = mergeRanges(matches)
+
+	start, end := 0, len(runes)
+	if len(runes) > snippetLength {
+		if len(matches) > 0 && matches[0][0] > snippetContext {
+			start = matches[0][0] - snippetContext
+		}
+		end = start + snippetLength
+		if end > len(runes) {
+			end = len(runes)
+			start = end - snippetLength
+		}
+	}
+
+	var sb strings.Builder
+	if start > 0 {
+		sb.WriteString("…")
+	}
+
+	cursor := start
+	for _, m := range matches {
+		if m[1] <= start || m[0] >= end {
+			continue
+		}
+		from, to := max(m[0], cursor), min(m[1], end)
+		sb.WriteString(html.EscapeString(string(runes[cursor:from])))
+		sb.WriteString("<mark>")
+		sb.WriteString(html.EscapeString(string(runes[from:to])))
+		sb.WriteString("</mark>")
+		cursor = to
+	}
+	sb.WriteString(html.EscapeString(string(runes[cursor:end])))
+
+	if end < len(runes) {
+		sb.WriteString("…")
+	}
+
+	return sb.String()
+}
+
+func findTerm(text, term []rune, prefix bool) [][2]int {
+	var found [][2]int
+	if len(term) == 0 {
+		return found
+	}
+
+	for i := 0; i+len(term) <= len(text); i++ {
+		if i > 0 && isWordRune(text[i-1]) {
+			continue
+		}
+		if string(text[i:i+len(term)]) != string(term) {
+			continue
+		}
+
+		end := i + len(term)
+		if prefix {
+			for end < len(text) && isWordRune(text[end]) {
+				end++
+			}
+		} else if end < len(text) && isWordRune(text[end]) {
+			continue
+		}
+
+		found = append(found, [2]int{i, end})
+		i = end - 1
+	}
+
+	return found
+}
+
+func mergeRanges(ranges [][2]int) [][2]int {
+	if len(ranges) == 0 {
+		return ranges
+	}
+
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i][0] < ranges[j][0] })
+
+	merged := [][2]int{ranges[0]}
+	for _, r := range ranges[1:] {
+		last := &merged[len(merged)-1]
+		if r[0] <= last[1] {
+			last[1] = max(last[1], r[1])
+			continue
+		}
+		merged = append(merged, r)
+	}
+
+	return merged
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
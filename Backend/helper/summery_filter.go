@@ -43,10 +43,28 @@ func ParseSummaryFilter(query url.Values) (domain.SummaryFilter, error) {
 		}
 		filter.Year = &yearVal
 	}
+	if weekStr := query.Get("week"); weekStr != "" {
+		weekVal, err := time.Parse("2006-01-02", weekStr)
+		if err != nil {
+			return filter, fmt.Errorf("invalid week, expected YYYY-MM-DD")
+		}
+		filter.Week = &weekVal
+	}
 	if descStr := query.Get("description"); descStr != "" {
 		filter.Description = &descStr
 	}
 
+	if tz := query.Get("tz"); tz != "" {
+		if _, err := time.LoadLocation(tz); err != nil {
+			return filter, fmt.Errorf("invalid tz")
+		}
+		filter.Timezone = tz
+	}
+
+	if filter.Week != nil && (filter.Day != nil || filter.Month != nil || filter.Year != nil) {
+		return filter, fmt.Errorf("week cannot be combined with day, month or year")
+	}
+
 	if filter.Day != nil && (filter.Month == nil || filter.Year == nil) {
 		return filter, fmt.Errorf("day requires month and year")
 	}
@@ -15,3 +15,25 @@ func CommitOrRollback(tx *sql.Tx) {
 		PanicIfError(errCommit)
 	}
 }
+
+// AfterCommit runs fn once the surrounding function has returned without a
+// panic. Deferred before CommitOrRollback, it therefore only runs after the
+// transaction has been committed.
+func AfterCommit(fn func()) {
+	err := recover()
+	if err != nil {
+		panic(err)
+	}
+	fn()
+}
+
+// OnRollback runs fn when the surrounding function panics and passes the
+// panic on. Deferred before CommitOrRollback, it therefore only runs once the
+// transaction has been rolled back or failed to commit.
+func OnRollback(fn func()) {
+	err := recover()
+	if err != nil {
+		fn()
+		panic(err)
+	}
+}
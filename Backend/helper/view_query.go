@@ -0,0 +1,31 @@
+package helper
+
+import (
+	"net/url"
+
+	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
+)
+
+// MergeViewQuery builds the query a saved view stands for, then lets any
+// parameter set on the incoming request override it.
+func MergeViewQuery(view domain.SavedView, query url.Values) url.Values {
+	merged := url.Values{}
+	for key, value := range view.Filter {
+		merged.Set(key, value)
+	}
+	if view.SortBy != "" {
+		merged.Set("sort_by", view.SortBy)
+	}
+	if view.Order != "" {
+		merged.Set("order", view.Order)
+	}
+
+	for key, values := range query {
+		if key == "view" {
+			continue
+		}
+		merged[key] = values
+	}
+
+	return merged
+}
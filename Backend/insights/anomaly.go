@@ -0,0 +1,153 @@
+// Package insights derives observations about a user's spending from their
+// expense history. The functions are pure so they can run on whatever slice
+// of history the caller loaded.
+package insights
+
+import (
+	"sort"
+	"time"
+
+	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
+)
+
+const (
+	// AmountSpikeRatio is how many times the category median an expense must
+	// reach to be flagged.
+	AmountSpikeRatio = 3.0
+	// MinAmountSamples is the history needed before the median is trusted.
+	MinAmountSamples = 5
+	// FrequencySpikeRatio and MinFrequencyCount flag a week with at least
+	// that many expenses in a category and that many times its usual rate.
+	FrequencySpikeRatio = 3.0
+	MinFrequencyCount   = 3
+	// FrequencyBaselineWeeks is how far back the usual weekly rate is taken.
+	FrequencyBaselineWeeks = 12
+	// DormantAfter is the gap after which using a category again is flagged.
+	DormantAfter = 90 * 24 * time.Hour
+
+	week = 7 * 24 * time.Hour
+)
+
+// BaselineLookback is how much history before the inspected window the
+// detection needs to judge the first expenses in it.
+const BaselineLookback = 365 * 24 * time.Hour
+
+// DetectAnomalies inspects the expenses created in [from, to] against the
+// earlier expenses of the same category. history may hold expenses of any
+// category and in any order; it should reach BaselineLookback before from.
+func DetectAnomalies(history []domain.Expense, from, to time.Time) []domain.Anomaly {
+	byCategory := map[int][]domain.Expense{}
+	for _, expense := range history {
+		byCategory[expense.CategoryId] = append(byCategory[expense.CategoryId], expense)
+	}
+
+	anomalies := []domain.Anomaly{}
+	for _, expenses := range byCategory {
+		sort.SliceStable(expenses, func(i, j int) bool {
+			return expenses[i].CreatedAt.Before(expenses[j].CreatedAt)
+		})
+		anomalies = append(anomalies, detectCategory(expenses, from, to)...)
+	}
+
+	sort.SliceStable(anomalies, func(i, j int) bool {
+		if !anomalies[i].OccurredAt.Equal(anomalies[j].OccurredAt) {
+			return anomalies[i].OccurredAt.After(anomalies[j].OccurredAt)
+		}
+		return anomalies[i].Kind < anomalies[j].Kind
+	})
+
+	return anomalies
+}
+
+// detectCategory works on the expenses of one category sorted by time.
+func detectCategory(expenses []domain.Expense, from, to time.Time) []domain.Anomaly {
+	var (
+		anomalies      []domain.Anomaly
+		lastSpikeStart time.Time
+	)
+
+	for i, expense := range expenses {
+		if expense.CreatedAt.Before(from) || expense.CreatedAt.After(to) {
+			continue
+		}
+		earlier := expenses[:i]
+
+		if len(earlier) >= MinAmountSamples {
+			amounts := make([]float64, len(earlier))
+			for j, e := range earlier {
+				amounts[j] = e.Amount
+			}
+			if typical := median(amounts); typical > 0 && expense.Amount >= AmountSpikeRatio*typical {
+				anomalies = append(anomalies, domain.Anomaly{
+					Kind:       domain.AnomalyAmountSpike,
+					Expense:    expense,
+					Baseline:   typical,
+					Observed:   expense.Amount,
+					OccurredAt: expense.CreatedAt,
+				})
+			}
+		}
+
+		if len(earlier) > 0 {
+			idle := expense.CreatedAt.Sub(earlier[len(earlier)-1].CreatedAt)
+			if idle >= DormantAfter {
+				days := float64(idle / (24 * time.Hour))
+				anomalies = append(anomalies, domain.Anomaly{
+					Kind:       domain.AnomalyDormantCategory,
+					Expense:    expense,
+					Baseline:   days,
+					Observed:   days,
+					OccurredAt: expense.CreatedAt,
+				})
+			}
+		}
+
+		weekStart := expense.CreatedAt.Add(-week)
+		if !lastSpikeStart.IsZero() && expense.CreatedAt.Sub(lastSpikeStart) < week {
+			continue
+		}
+		current := countBetween(expenses[:i+1], weekStart, expense.CreatedAt)
+		previous := countBetween(earlier, weekStart.Add(-FrequencyBaselineWeeks*week), weekStart)
+		if previous == 0 || current < MinFrequencyCount {
+			continue
+		}
+		rate := float64(previous) / FrequencyBaselineWeeks
+		if float64(current) >= FrequencySpikeRatio*rate {
+			lastSpikeStart = expense.CreatedAt
+			anomalies = append(anomalies, domain.Anomaly{
+				Kind:       domain.AnomalyFrequencySpike,
+				Expense:    expense,
+				Baseline:   rate,
+				Observed:   float64(current),
+				OccurredAt: expense.CreatedAt,
+			})
+		}
+	}
+
+	return anomalies
+}
+
+// countBetween counts the sorted expenses created after from and up to to.
+func countBetween(expenses []domain.Expense, from, to time.Time) int {
+	count := 0
+	for _, e := range expenses {
+		if e.CreatedAt.After(from) && !e.CreatedAt.After(to) {
+			count++
+		}
+	}
+	return count
+}
+
+func median(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	n := len(sorted)
+	if n == 0 {
+		return 0
+	}
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
@@ -0,0 +1,261 @@
+package insights
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
+)
+
+const (
+	// ForecastHistoryMonths is how much history the forecast wants before the
+	// current month.
+	ForecastHistoryMonths = 24
+	// forecastZ gives an 80% range under a normal approximation.
+	forecastZ = 1.28
+	// MinSeasonalMonths of history are needed before seasonality is applied.
+	MinSeasonalMonths = 12
+)
+
+type categoryHistory struct {
+	name       string
+	firstSeen  time.Time
+	monthly    map[time.Time]float64
+	spent      float64
+	variable   float64
+	pending    float64
+	recurring  float64
+	mean       float64
+	stddev     float64
+	months     []time.Time
+	hasHistory bool
+}
+
+// ForecastSpending projects the month-end total of every category and the
+// totals of the next months. now must be in the user's time zone: month
+// boundaries and the elapsed share of the month follow it.
+//
+// Recurring charges are forecast separately at their usual amount; the rest
+// of the spending blends the run rate of this month with the history of
+// previous full months (weighted by how much of the month has passed) and is
+// scaled by seasonality when a year of history exists. A category's first
+// month is left out of its history because it is usually partial.
+func ForecastSpending(records []domain.SummaryRecord, recurring []domain.RecurringCharge, now time.Time, months int) domain.Forecast {
+	loc := now.Location()
+	monthStart := startOfMonth(now)
+	nextMonthStart := monthStart.AddDate(0, 1, 0)
+	daysInMonth := int(nextMonthStart.Sub(monthStart).Hours()/24 + 0.5)
+	elapsed := now.Sub(monthStart).Hours() / 24
+	fraction := elapsed / float64(daysInMonth)
+
+	charges := map[string]domain.RecurringCharge{}
+	for _, charge := range recurring {
+		charges[charge.Key] = charge
+	}
+
+	categories := map[int]*categoryHistory{}
+	category := func(id int, name string) *categoryHistory {
+		c, ok := categories[id]
+		if !ok {
+			c = &categoryHistory{name: name, monthly: map[time.Time]float64{}}
+			categories[id] = c
+		}
+		if c.name == "" {
+			c.name = name
+		}
+		return c
+	}
+
+	for _, record := range records {
+		local := record.CreatedAt.In(loc)
+		if !local.Before(nextMonthStart) {
+			continue
+		}
+		c := category(record.CategoryId, record.CategoryName)
+		if c.firstSeen.IsZero() || local.Before(c.firstSeen) {
+			c.firstSeen = local
+		}
+
+		charge, isRecurring := charges[recurringKey(record.CategoryId, record.Description)]
+		isRecurring = isRecurring && math.Abs(record.Amount-charge.Amount) <= RecurringAmountTolerance*charge.Amount
+
+		if !local.Before(monthStart) {
+			c.spent += record.Amount
+			if !isRecurring {
+				c.variable += record.Amount
+			}
+			continue
+		}
+		if !isRecurring {
+			c.monthly[startOfMonth(local)] += record.Amount
+		}
+	}
+
+	for _, charge := range recurring {
+		c := category(charge.CategoryId, "")
+		c.recurring += charge.Amount
+		if charge.LastSeen.In(loc).Before(monthStart) {
+			c.pending += charge.Amount
+		}
+	}
+
+	for _, c := range categories {
+		c.computeBaseline(monthStart)
+	}
+
+	forecast := domain.Forecast{
+		AsOf:        now,
+		DaysElapsed: math.Round(elapsed*10) / 10,
+		DaysInMonth: daysInMonth,
+		Current:     domain.MonthForecast{Month: monthStart},
+	}
+
+	for id, c := range categories {
+		historical := c.mean * c.seasonality(monthStart)
+		runRate := 0.0
+		if fraction > 0 {
+			runRate = c.variable / fraction
+		}
+
+		weight := fraction
+		if !c.hasHistory {
+			weight = 1
+		}
+		remaining := (weight*runRate + (1-weight)*historical) * (1 - fraction)
+
+		spread := 0.5 * remaining
+		if c.hasHistory {
+			spread = forecastZ * c.stddev * math.Sqrt(1-fraction)
+		}
+
+		line := domain.ForecastLine{
+			CategoryId:   id,
+			CategoryName: c.name,
+			SpentToDate:  c.spent,
+			Recurring:    c.pending,
+			Point:        c.spent + c.pending + remaining,
+		}
+		line.Low = c.spent + c.pending + math.Max(0, remaining-spread)
+		line.High = line.Point + spread
+		if line.Point > 0 {
+			forecast.Current.Lines = append(forecast.Current.Lines, line)
+		}
+	}
+	finishMonth(&forecast.Current)
+
+	for k := 1; k <= months; k++ {
+		month := monthStart.AddDate(0, k, 0)
+		next := domain.MonthForecast{Month: month}
+
+		for id, c := range categories {
+			variable, stddev := c.mean*c.seasonality(month), c.stddev
+			if !c.hasHistory {
+				// Only this month to go on: assume it repeats, loosely.
+				if fraction > 0 {
+					variable = c.variable / fraction
+				}
+				stddev = 0.5 * variable / forecastZ
+			}
+
+			spread := forecastZ * stddev
+			line := domain.ForecastLine{
+				CategoryId:   id,
+				CategoryName: c.name,
+				Recurring:    c.recurring,
+				Point:        c.recurring + variable,
+				Low:          c.recurring + math.Max(0, variable-spread),
+				High:         c.recurring + variable + spread,
+			}
+			if line.Point > 0 {
+				next.Lines = append(next.Lines, line)
+			}
+		}
+
+		finishMonth(&next)
+		forecast.Next = append(forecast.Next, next)
+	}
+
+	return forecast
+}
+
+// computeBaseline takes the mean and standard deviation of the non-recurring
+// monthly totals over the full months between the category's first month and
+// the current one. Months without spending count as zero.
+func (c *categoryHistory) computeBaseline(monthStart time.Time) {
+	if c.firstSeen.IsZero() || !c.firstSeen.Before(monthStart) {
+		return
+	}
+
+	first := startOfMonth(c.firstSeen)
+	for m := first.AddDate(0, 1, 0); m.Before(monthStart); m = m.AddDate(0, 1, 0) {
+		c.months = append(c.months, m)
+	}
+	if len(c.months) == 0 {
+		// Only the partial first month is known; better than nothing.
+		c.months = []time.Time{first}
+	}
+
+	var sum float64
+	for _, m := range c.months {
+		sum += c.monthly[m]
+	}
+	c.mean = sum / float64(len(c.months))
+
+	var squares float64
+	for _, m := range c.months {
+		squares += (c.monthly[m] - c.mean) * (c.monthly[m] - c.mean)
+	}
+	if len(c.months) > 1 {
+		c.stddev = math.Sqrt(squares / float64(len(c.months)-1))
+	} else {
+		c.stddev = 0.5 * c.mean
+	}
+	c.hasHistory = true
+}
+
+// seasonality is how the same calendar month compared to the average month
+// in previous years, clamped to [0.5, 2].
+func (c *categoryHistory) seasonality(month time.Time) float64 {
+	if len(c.months) < MinSeasonalMonths || c.mean <= 0 {
+		return 1
+	}
+
+	var (
+		sum   float64
+		count int
+	)
+	for _, m := range c.months {
+		if m.Month() == month.Month() {
+			sum += c.monthly[m]
+			count++
+		}
+	}
+	if count == 0 {
+		return 1
+	}
+
+	return math.Min(2, math.Max(0.5, sum/float64(count)/c.mean))
+}
+
+// finish sorts the lines by forecast and adds the total. The total range
+// combines the category ranges as independent errors.
+func finishMonth(month *domain.MonthForecast) {
+	sort.Slice(month.Lines, func(i, j int) bool {
+		if month.Lines[i].Point != month.Lines[j].Point {
+			return month.Lines[i].Point > month.Lines[j].Point
+		}
+		return month.Lines[i].CategoryId < month.Lines[j].CategoryId
+	})
+
+	var below, above float64
+	for _, line := range month.Lines {
+		month.Total.SpentToDate += line.SpentToDate
+		month.Total.Recurring += line.Recurring
+		month.Total.Point += line.Point
+		below += (line.Point - line.Low) * (line.Point - line.Low)
+		above += (line.High - line.Point) * (line.High - line.Point)
+	}
+	month.Total.Low = month.Total.Point - math.Sqrt(below)
+	month.Total.High = month.Total.Point + math.Sqrt(above)
+}
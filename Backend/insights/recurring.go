@@ -0,0 +1,112 @@
+package insights
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
+)
+
+const (
+	// RecurringLookbackMonths complete months are searched for repeats, and a
+	// charge must show up in at least MinRecurringMonths of them.
+	RecurringLookbackMonths = 4
+	MinRecurringMonths      = 3
+	// RecurringAmountTolerance is how far an occurrence may be from the
+	// median amount and still count as the same charge.
+	RecurringAmountTolerance = 0.15
+)
+
+// DescriptionKey normalises a description for grouping: lowercased, letters
+// only, so "Netflix 12/24" and "NETFLIX" fall together.
+func DescriptionKey(description string) string {
+	words := strings.FieldsFunc(strings.ToLower(description), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+	return strings.Join(words, " ")
+}
+
+func recurringKey(categoryId int, description string) string {
+	return strconv.Itoa(categoryId) + "|" + DescriptionKey(description)
+}
+
+// FindRecurringCharges looks for monthly charges in the records: the same
+// category and description, a stable amount and about one occurrence per
+// month. now should be in the user's time zone; months follow its calendar.
+func FindRecurringCharges(records []domain.SummaryRecord, now time.Time) []domain.RecurringCharge {
+	monthStart := startOfMonth(now)
+	lookbackStart := monthStart.AddDate(0, -RecurringLookbackMonths, 0)
+
+	groups := map[string][]domain.SummaryRecord{}
+	for _, record := range records {
+		if record.CreatedAt.Before(lookbackStart) || DescriptionKey(record.Description) == "" {
+			continue
+		}
+		key := recurringKey(record.CategoryId, record.Description)
+		groups[key] = append(groups[key], record)
+	}
+
+	charges := []domain.RecurringCharge{}
+	for key, group := range groups {
+		amounts := make([]float64, len(group))
+		for i, record := range group {
+			amounts[i] = record.Amount
+		}
+		typical := median(amounts)
+
+		var (
+			matching []domain.SummaryRecord
+			months   = map[time.Time]bool{}
+		)
+		for _, record := range group {
+			if math.Abs(record.Amount-typical) > RecurringAmountTolerance*typical {
+				continue
+			}
+			matching = append(matching, record)
+			if local := record.CreatedAt.In(now.Location()); local.Before(monthStart) {
+				months[startOfMonth(local)] = true
+			}
+		}
+
+		// More than about one a month is a habit, not a subscription.
+		if len(months) < MinRecurringMonths || len(matching) > len(months)+1 {
+			continue
+		}
+
+		days := make([]float64, len(matching))
+		charge := domain.RecurringCharge{
+			Key:         key,
+			CategoryId:  matching[0].CategoryId,
+			Description: matching[len(matching)-1].Description,
+			Amount:      typical,
+			Occurrences: len(matching),
+		}
+		for i, record := range matching {
+			days[i] = float64(record.CreatedAt.In(now.Location()).Day())
+			if record.CreatedAt.After(charge.LastSeen) {
+				charge.LastSeen = record.CreatedAt
+				charge.Description = record.Description
+			}
+		}
+		charge.DayOfMonth = int(median(days))
+
+		charges = append(charges, charge)
+	}
+
+	sort.Slice(charges, func(i, j int) bool {
+		if charges[i].CategoryId != charges[j].CategoryId {
+			return charges[i].CategoryId < charges[j].CategoryId
+		}
+		return charges[i].Key < charges[j].Key
+	})
+
+	return charges
+}
+
+func startOfMonth(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,187 @@
+package insights
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
+)
+
+const (
+	// MinSubscriptionOccurrences is how many payments a cadence needs.
+	MinSubscriptionOccurrences = 3
+	// SubscriptionLookback is how much history FindSubscriptions expects.
+	SubscriptionLookback = 2 * 365 * 24 * time.Hour
+	// subscriptionAmountTolerance keeps a price change within the same
+	// subscription while telling unrelated payments apart.
+	subscriptionAmountTolerance = 0.35
+)
+
+var cadences = []struct {
+	name    string
+	days    float64
+	slack   float64
+	perYear float64
+}{
+	{domain.CadenceWeekly, 7, 1.5, 52},
+	{domain.CadenceBiweekly, 14, 2.5, 26},
+	{domain.CadenceMonthly, 30.44, 4.5, 12},
+	{domain.CadenceQuarterly, 91.3, 8, 4},
+	{domain.CadenceYearly, 365.25, 15, 1},
+}
+
+// NextOccurrence returns the date after t for the cadence. Monthly and
+// longer cadences land on anchorDay, or on the last day of months too short
+// for it, so a rule billed on the 31st comes back to the 31st after
+// February. An anchorDay of 0 keeps the day of t.
+func NextOccurrence(cadence string, t time.Time, anchorDay int) time.Time {
+	switch cadence {
+	case domain.CadenceWeekly:
+		return t.AddDate(0, 0, 7)
+	case domain.CadenceBiweekly:
+		return t.AddDate(0, 0, 14)
+	case domain.CadenceQuarterly:
+		return addMonths(t, 3, anchorDay)
+	case domain.CadenceYearly:
+		return addMonths(t, 12, anchorDay)
+	default:
+		return addMonths(t, 1, anchorDay)
+	}
+}
+
+// addMonths moves t by months without the overflow of time.AddDate, which
+// turns January 31 plus one month into March 3.
+func addMonths(t time.Time, months, day int) time.Time {
+	if day <= 0 {
+		day = t.Day()
+	}
+
+	first := time.Date(t.Year(), t.Month()+time.Month(months), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	if last := first.AddDate(0, 1, -1).Day(); day > last {
+		day = last
+	}
+
+	return time.Date(first.Year(), first.Month(), day, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+}
+
+// anchorDay is the day of month a group of payments is billed on. A payment
+// on the last day of a month may have been moved there from a later day, so
+// the latest day seen is taken then.
+func anchorDay(group []domain.Expense) int {
+	last := group[len(group)-1].CreatedAt
+	if last.AddDate(0, 0, 1).Day() != 1 {
+		return last.Day()
+	}
+
+	day := last.Day()
+	for _, expense := range group {
+		day = max(day, expense.CreatedAt.Day())
+	}
+	return day
+}
+
+// FindSubscriptions groups expenses by normalised description and keeps the
+// groups paid at a regular cadence with similar amounts. A subscription is
+// active while it is less than two periods overdue; the last payment
+// differing from the one before is reported as a price change.
+func FindSubscriptions(expenses []domain.Expense, now time.Time) []domain.Subscription {
+	groups := map[string][]domain.Expense{}
+	for _, expense := range expenses {
+		key := DescriptionKey(expense.Description)
+		if key == "" {
+			continue
+		}
+		groups[key] = append(groups[key], expense)
+	}
+
+	subscriptions := []domain.Subscription{}
+	for key, group := range groups {
+		if len(group) < MinSubscriptionOccurrences {
+			continue
+		}
+		sort.SliceStable(group, func(i, j int) bool {
+			return group[i].CreatedAt.Before(group[j].CreatedAt)
+		})
+
+		subscription, ok := detectSubscription(key, group, now)
+		if ok {
+			subscriptions = append(subscriptions, subscription)
+		}
+	}
+
+	sort.Slice(subscriptions, func(i, j int) bool {
+		if subscriptions[i].AnnualizedCost != subscriptions[j].AnnualizedCost {
+			return subscriptions[i].AnnualizedCost > subscriptions[j].AnnualizedCost
+		}
+		return subscriptions[i].Key < subscriptions[j].Key
+	})
+
+	return subscriptions
+}
+
+func detectSubscription(key string, group []domain.Expense, now time.Time) (domain.Subscription, bool) {
+	amounts := make([]float64, len(group))
+	for i, e := range group {
+		amounts[i] = e.Amount
+	}
+	typical := median(amounts)
+	for _, a := range amounts {
+		if typical <= 0 || math.Abs(a-typical) > subscriptionAmountTolerance*typical {
+			return domain.Subscription{}, false
+		}
+	}
+
+	intervals := make([]float64, len(group)-1)
+	for i := 1; i < len(group); i++ {
+		intervals[i-1] = group[i].CreatedAt.Sub(group[i-1].CreatedAt).Hours() / 24
+	}
+	interval := median(intervals)
+
+	for _, cadence := range cadences {
+		if math.Abs(interval-cadence.days) > cadence.slack {
+			continue
+		}
+
+		regular := 0
+		for _, days := range intervals {
+			if math.Abs(days-cadence.days) <= cadence.slack {
+				regular++
+			}
+		}
+		if float64(regular) < 0.75*float64(len(intervals)) {
+			return domain.Subscription{}, false
+		}
+
+		first, last := group[0], group[len(group)-1]
+		subscription := domain.Subscription{
+			Key:            key,
+			CategoryId:     last.CategoryId,
+			Description:    last.Description,
+			Cadence:        cadence.name,
+			Amount:         last.Amount,
+			AnnualizedCost: math.Round(last.Amount*cadence.perYear*100) / 100,
+			Occurrences:    len(group),
+			FirstSeen:      first.CreatedAt,
+			LastSeen:       last.CreatedAt,
+			AnchorDay:      anchorDay(group),
+		}
+		subscription.NextExpected = NextOccurrence(cadence.name, last.CreatedAt, subscription.AnchorDay)
+
+		overdue := now.Sub(subscription.NextExpected).Hours() / 24
+		subscription.Active = overdue < cadence.days
+
+		previous := group[len(group)-2]
+		if math.Abs(last.Amount-previous.Amount) >= 0.01 {
+			subscription.PriceChange = &domain.PriceChange{
+				Previous:  previous.Amount,
+				Current:   last.Amount,
+				ChangedAt: last.CreatedAt,
+			}
+		}
+
+		return subscription, true
+	}
+
+	return domain.Subscription{}, false
+}
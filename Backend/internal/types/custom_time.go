@@ -8,6 +8,9 @@ import (
 
 type CustomTime struct {
 	time.Time
+	// floating is set for input without a UTC offset. Time then holds the
+	// wall clock as if it were UTC, until InLocation places it.
+	floating bool
 }
 
 func (ct *CustomTime) UnmarshalJSON(b []byte) error {
@@ -29,6 +32,7 @@ func (ct *CustomTime) UnmarshalJSON(b []byte) error {
 		parsed, err = time.Parse(f, s)
 		if err == nil {
 			ct.Time = parsed.UTC()
+			ct.floating = f != time.RFC3339
 			return nil
 		}
 	}
@@ -39,3 +43,14 @@ func (ct *CustomTime) UnmarshalJSON(b []byte) error {
 func (ct CustomTime) MarshalJSON() ([]byte, error) {
 	return json.Marshal(ct.Time.Format(time.RFC3339))
 }
+
+// InLocation returns the time in UTC, reading input that had no UTC offset
+// as wall clock time in loc.
+func (ct CustomTime) InLocation(loc *time.Location) time.Time {
+	if !ct.floating {
+		return ct.Time.UTC()
+	}
+
+	t := ct.Time
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), loc).UTC()
+}
@@ -1,16 +1,19 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/joho/godotenv"
 	"github.com/ryhnfhrza/Expense-Tracker/app"
+	"github.com/ryhnfhrza/Expense-Tracker/classifier"
 	"github.com/ryhnfhrza/Expense-Tracker/controller"
 	"github.com/ryhnfhrza/Expense-Tracker/exception"
 	"github.com/ryhnfhrza/Expense-Tracker/helper"
@@ -49,26 +52,126 @@ func main() {
 
 	userRepository := repository.NewUserRepository()
 	userRedisRepository := repository.NewUserRedisRepository(redisClient)
-	userService := service.NewUserService(userRepository, userRedisRepository, validate, DB)
+	twoFactorRepository := repository.NewTwoFactorRepository()
+	twoFactorRedisRepository := repository.NewTwoFactorRedisRepository(redisClient)
+	userPreferencesRepository := repository.NewUserPreferencesRepository()
+	userService := service.NewUserService(userRepository, userRedisRepository, twoFactorRepository, twoFactorRedisRepository, userPreferencesRepository, validate, DB, app.AccountDeletionGrace())
 	userController := controller.NewUserController(userService)
 
+	userIdentityRepository := repository.NewUserIdentityRepository()
+	ssoRedisRepository := repository.NewSsoRedisRepository(redisClient)
+	ssoService := service.NewSsoService(DB, validate, userRepository, userRedisRepository, userIdentityRepository, ssoRedisRepository, twoFactorRepository, twoFactorRedisRepository, app.NewOIDCClient())
+	ssoController := controller.NewSsoController(ssoService)
+
+	undoRedisRepository := repository.NewUndoRedisRepository(redisClient)
+
+	auditRepository := repository.NewAuditRepository()
+	webhookRepository := repository.NewWebhookRepository()
+	auditService := service.NewAuditService(DB, auditRepository)
+	auditController := controller.NewAuditController(auditService)
+
 	catogoryRepository := repository.NewCategoryRepository()
-	categoryService := service.NewCategoryService(DB, validate, catogoryRepository)
+	expenseRepository := repository.NewExpenseRepository()
+	categoryModels := classifier.NewStore()
+
+	categoryService := service.NewCategoryService(DB, validate, catogoryRepository, expenseRepository, auditRepository, webhookRepository, undoRedisRepository, categoryModels)
 	categoryController := controller.NewCategoryController(categoryService)
 
 	expenseSummeryRepository := repository.NewExpenseSummeryRepository()
+	expenseSearchRepository := repository.NewExpenseSearchRepository()
 
-	expenseRepository := repository.NewExpenseRepository()
-	expenseService := service.NewExpenseService(DB, validate, expenseRepository, catogoryRepository, expenseSummeryRepository)
-	expenseController := controller.NewExpenseController(expenseService)
+	savedViewRepository := repository.NewSavedViewRepository()
+	savedViewService := service.NewSavedViewService(DB, validate, savedViewRepository)
+	savedViewController := controller.NewSavedViewController(savedViewService)
+
+	categoryRuleRepository := repository.NewCategoryRuleRepository()
+
+	expenseService := service.NewExpenseService(DB, validate, expenseRepository, catogoryRepository, expenseSummeryRepository, expenseSearchRepository, categoryRuleRepository, auditRepository, webhookRepository, undoRedisRepository, categoryModels, userPreferencesRepository)
+	expenseController := controller.NewExpenseController(expenseService, savedViewService, userService)
+
+	categoryRuleService := service.NewCategoryRuleService(DB, validate, categoryRuleRepository, catogoryRepository, expenseRepository, auditRepository, webhookRepository, undoRedisRepository, categoryModels)
+	categoryRuleController := controller.NewCategoryRuleController(categoryRuleService)
+
+	expenseImportRepository := repository.NewExpenseImportRepository()
+	expenseImportService := service.NewExpenseImportService(DB, validate, expenseRepository, catogoryRepository, categoryRuleRepository, expenseImportRepository, auditRepository, webhookRepository, undoRedisRepository, categoryModels)
+	expenseImportController := controller.NewExpenseImportController(expenseImportService)
+
+	recurringExpenseRepository := repository.NewRecurringExpenseRepository()
+	recurringExpenseService := service.NewRecurringExpenseService(DB, validate, recurringExpenseRepository, expenseRepository, catogoryRepository, auditRepository, webhookRepository, categoryModels)
+	recurringExpenseController := controller.NewRecurringExpenseController(recurringExpenseService)
+
+	insightService := service.NewInsightService(DB, expenseRepository, catogoryRepository, expenseSummeryRepository, recurringExpenseRepository)
+	insightController := controller.NewInsightController(insightService, userService)
+
+	trashRepository := repository.NewTrashRepository()
+	trashService := service.NewTrashService(DB, trashRepository, catogoryRepository, auditRepository, webhookRepository, categoryModels, app.TrashRetention())
+	trashController := controller.NewTrashController(trashService)
+
+	undoService := service.NewUndoService(DB, undoRedisRepository, expenseRepository, catogoryRepository, trashRepository, expenseImportRepository, auditRepository, webhookRepository, categoryModels)
+	undoController := controller.NewUndoController(undoService)
 
-	router := app.NewRouter(userController, categoryController, expenseController)
+	syncRepository := repository.NewSyncRepository()
+	syncService := service.NewSyncService(DB, validate, syncRepository, expenseRepository, catogoryRepository, trashRepository, auditRepository, webhookRepository, categoryModels)
+	syncController := controller.NewSyncController(syncService)
+
+	webhookService := service.NewWebhookService(DB, validate, webhookRepository, app.NewWebhookClient())
+	webhookController := controller.NewWebhookController(webhookService)
+
+	accessTokenRepository := repository.NewAccessTokenRepository()
+	accessTokenService := service.NewAccessTokenService(DB, validate, accessTokenRepository)
+	accessTokenController := controller.NewAccessTokenController(accessTokenService)
+
+	twoFactorService := service.NewTwoFactorService(DB, validate, userRepository, twoFactorRepository, twoFactorRedisRepository)
+	twoFactorController := controller.NewTwoFactorController(twoFactorService)
+
+	dataExportRepository := repository.NewDataExportRepository()
+	dataExportService := service.NewDataExportService(DB, dataExportRepository, userRepository, userIdentityRepository, twoFactorRepository, catogoryRepository, expenseRepository, trashRepository, recurringExpenseRepository, categoryRuleRepository, savedViewRepository, webhookRepository, accessTokenRepository, auditRepository)
+	dataExportController := controller.NewDataExportController(dataExportService)
+
+	restoreService := service.NewRestoreService(DB, validate, catogoryRepository, expenseRepository, auditRepository, webhookRepository, categoryModels)
+	restoreController := controller.NewRestoreController(restoreService)
+
+	idempotencyRedisRepository := repository.NewIdempotencyRedisRepository(redisClient)
+
+	router := app.NewRouter(userController, ssoController, categoryController, expenseController, savedViewController, categoryRuleController, expenseImportController, insightController, recurringExpenseController, auditController, trashController, undoController, syncController, webhookController, accessTokenController, twoFactorController, dataExportController, restoreController, accessTokenService, idempotencyRedisRepository)
 
 	router.PanicHandler = exception.ErrorHandler
 
+	app.StartJob("recurring-expenses", time.Hour, func() {
+		if created := recurringExpenseService.RunDue(context.Background(), time.Now().UTC()); created > 0 {
+			log.Printf("recurring expenses: created %d expenses", created)
+		}
+	})
+
+	app.StartJob("trash-purge", time.Hour, func() {
+		if purged := trashService.PurgeExpired(context.Background(), time.Now().UTC()); purged > 0 {
+			log.Printf("trash: purged %d expired items", purged)
+		}
+	})
+
+	app.StartJob("webhook-delivery", 5*time.Second, func() {
+		ctx := context.Background()
+		webhookService.DispatchEvents(ctx, time.Now().UTC())
+		webhookService.DeliverDue(ctx, time.Now().UTC())
+	})
+
+	app.StartJob("data-export", 10*time.Second, func() {
+		ctx := context.Background()
+		dataExportService.RunPending(ctx, time.Now().UTC())
+		if expired := dataExportService.ExpireArchives(ctx, time.Now().UTC()); expired > 0 {
+			log.Printf("data exports: deleted %d expired archives", expired)
+		}
+	})
+
+	app.StartJob("account-deletion", time.Hour, func() {
+		if purged := userService.PurgeDeletedAccounts(context.Background(), time.Now().UTC()); purged > 0 {
+			log.Printf("accounts: deleted %d accounts", purged)
+		}
+	})
+
 	server := http.Server{
 		Addr:    ":" + port,
-		Handler: app.CORS(router),
+		Handler: app.CORS(app.RequestID(router)),
 	}
 
 	log.Printf("Server running on port %s", port)
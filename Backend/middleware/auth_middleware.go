@@ -2,41 +2,69 @@ package middleware
 
 import (
 	"net/http"
+	"slices"
 	"strings"
 
 	"github.com/julienschmidt/httprouter"
 	"github.com/ryhnfhrza/Expense-Tracker/exception"
 	"github.com/ryhnfhrza/Expense-Tracker/helper"
+	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
+	"github.com/ryhnfhrza/Expense-Tracker/service"
 	"github.com/ryhnfhrza/Expense-Tracker/util"
 )
 
-func AuthMiddleware(next httprouter.Handle) httprouter.Handle {
-	return func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
-		defer func() {
-			if r := recover(); r != nil {
-				exception.ErrorHandler(writer, request, r)
-			}
-		}()
+// AuthMiddleware returns the wrapper for authenticated routes. It accepts
+// the JWT from Login, which may use any route, and personal access tokens,
+// which must hold every scope the route lists. Routes listed without scopes
+// take JWTs only.
+func AuthMiddleware(accessTokenService service.AccessTokenService) func(next httprouter.Handle, scopes ...string) httprouter.Handle {
+	return func(next httprouter.Handle, scopes ...string) httprouter.Handle {
+		return func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+			defer func() {
+				if r := recover(); r != nil {
+					exception.ErrorHandler(writer, request, r)
+				}
+			}()
 
-		authHeader := request.Header.Get("Authorization")
-		if authHeader == "" {
-			panic(exception.NewUnauthorizedError("missing Authorization header"))
-		}
+			authHeader := request.Header.Get("Authorization")
+			if authHeader == "" {
+				panic(exception.NewUnauthorizedError("missing Authorization header"))
+			}
 
-		if !strings.HasPrefix(authHeader, "Bearer ") {
-			panic(exception.NewUnauthorizedError("invalid token format"))
-		}
+			if !strings.HasPrefix(authHeader, "Bearer ") {
+				panic(exception.NewUnauthorizedError("invalid token format"))
+			}
 
-		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+			tokenString := strings.TrimPrefix(authHeader, "Bearer ")
 
-		claims, err := util.ValidateToken(tokenString)
-		if err != nil {
-			panic(exception.NewUnauthorizedError("invalid or expired token"))
-		}
+			ctx := request.Context()
+			var userId int
+			if strings.HasPrefix(tokenString, domain.AccessTokenPrefix) {
+				token := accessTokenService.Authenticate(request.Context(), tokenString)
+				if len(scopes) == 0 {
+					panic(exception.NewForbiddenError("personal access tokens cannot be used for this endpoint"))
+				}
+				for _, scope := range scopes {
+					if !slices.Contains(token.Scopes, scope) {
+						panic(exception.NewForbiddenError("token is missing scope " + scope))
+					}
+				}
+				userId = token.UserId
+			} else {
+				claims, err := util.ValidateToken(tokenString)
+				if err != nil {
+					panic(exception.NewUnauthorizedError("invalid or expired token"))
+				}
+				userId = claims.UserId
+				if claims.IssuedAt != nil {
+					ctx = helper.ContextWithSignedInAt(ctx, claims.IssuedAt.Time)
+				}
+			}
 
-		ctx := helper.ContextWithUserID(request.Context(), claims.UserId)
-		request = request.WithContext(ctx)
+			ctx = helper.ContextWithUserID(ctx, userId)
+			request = request.WithContext(ctx)
 
-		next(writer, request, params)
+			next(writer, request, params)
+		}
 	}
 }
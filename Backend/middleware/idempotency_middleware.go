@@ -0,0 +1,214 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/ryhnfhrza/Expense-Tracker/exception"
+	"github.com/ryhnfhrza/Expense-Tracker/helper"
+	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
+	"github.com/ryhnfhrza/Expense-Tracker/repository"
+)
+
+const (
+	maxIdempotencyKeyLength = 255
+	// maxIdempotentBodySize leaves room for a statement upload plus the
+	// multipart framing around it.
+	maxIdempotentBodySize = 8 << 20
+	// idempotencyLockTTL bounds how long a key stays in flight when the
+	// server dies before the first request finishes.
+	idempotencyLockTTL = 5 * time.Minute
+)
+
+// replayedHeaders are the response headers stored alongside the body.
+var replayedHeaders = []string{"Content-Type", "ETag", "Location"}
+
+// IdempotencyMiddleware makes a request sent with an Idempotency-Key header
+// safe to retry. The first request with a key runs and its response is kept
+// for ttl; a retry with the same key and body gets that response back
+// without running the handler again, and one that arrives while the first
+// is still running gets a conflict. Keys are scoped per user, so this has to
+// run inside AuthMiddleware.
+func IdempotencyMiddleware(idempotencyRepository repository.IdempotencyRedisRepository, ttl time.Duration) func(httprouter.Handle) httprouter.Handle {
+	return func(next httprouter.Handle) httprouter.Handle {
+		return func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+			key := request.Header.Get("Idempotency-Key")
+			if key == "" {
+				next(writer, request, params)
+				return
+			}
+			if len(key) > maxIdempotencyKeyLength {
+				panic(exception.NewBadRequest(fmt.Sprintf("Idempotency-Key must be at most %d characters", maxIdempotencyKeyLength)))
+			}
+
+			userId, ok := helper.GetUserIDFromContext(request.Context())
+			if !ok {
+				http.Error(writer, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+
+			body, err := io.ReadAll(io.LimitReader(request.Body, maxIdempotentBodySize+1))
+			helper.PanicIfError(err)
+			if len(body) > maxIdempotentBodySize {
+				panic(exception.NewBadRequest("request body is too large"))
+			}
+			request.Body = io.NopCloser(bytes.NewReader(body))
+
+			fingerprint := requestFingerprint(request, body)
+			storeKey := fmt.Sprintf("%d:%s", userId, key)
+
+			reserved, err := idempotencyRepository.ReserveIdempotency(request.Context(), storeKey, domain.IdempotencyRecord{
+				State:       domain.IdempotencyInFlight,
+				Fingerprint: fingerprint,
+			}, idempotencyLockTTL)
+			helper.PanicIfError(err)
+
+			if !reserved {
+				record, err := idempotencyRepository.GetIdempotency(request.Context(), storeKey)
+				helper.PanicIfError(err)
+
+				switch {
+				case record == nil || record.State == domain.IdempotencyInFlight:
+					writer.Header().Set("Retry-After", "1")
+					panic(exception.NewConflictError("a request with this Idempotency-Key is still being processed"))
+				case record.Fingerprint != fingerprint:
+					panic(exception.NewBadRequest("Idempotency-Key was already used for a different request"))
+				}
+
+				for name, value := range record.Header {
+					writer.Header().Set(name, value)
+				}
+				writer.Header().Set("Idempotent-Replayed", "true")
+				writer.WriteHeader(record.StatusCode)
+				_, _ = writer.Write(record.Body)
+				return
+			}
+
+			recorder := &responseRecorder{ResponseWriter: writer}
+			defer func() {
+				if r := recover(); r != nil {
+					exception.ErrorHandler(recorder, request, r)
+				}
+
+				// Server errors are forgotten so the client can retry them.
+				if recorder.StatusCode() >= http.StatusInternalServerError {
+					if err := idempotencyRepository.DeleteIdempotency(context.Background(), storeKey); err != nil {
+						log.Printf("idempotency: failed to release key %s: %v", storeKey, err)
+					}
+					return
+				}
+
+				record := domain.IdempotencyRecord{
+					State:       domain.IdempotencyCompleted,
+					Fingerprint: fingerprint,
+					StatusCode:  recorder.StatusCode(),
+					Header:      map[string]string{},
+					Body:        recorder.body.Bytes(),
+				}
+				for _, name := range replayedHeaders {
+					if value := writer.Header().Get(name); value != "" {
+						record.Header[name] = value
+					}
+				}
+				if err := idempotencyRepository.SaveIdempotency(context.Background(), storeKey, record, ttl); err != nil {
+					log.Printf("idempotency: failed to save response for key %s: %v", storeKey, err)
+				}
+			}()
+
+			next(recorder, request, params)
+		}
+	}
+}
+
+// requestFingerprint tells requests apart by method, URL and body. Multipart
+// bodies are compared by their parts, since every retry of an upload comes
+// with a new random boundary.
+func requestFingerprint(request *http.Request, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(request.Method + " " + request.URL.RequestURI() + "\n"))
+
+	if parts, ok := multipartParts(request.Header.Get("Content-Type"), body); ok {
+		for _, part := range parts {
+			// Lengths keep one part's bytes from passing for another's.
+			fmt.Fprintf(hash, "%d:%s|%d:%s|%d:", len(part.name), part.name, len(part.fileName), part.fileName, len(part.content))
+			hash.Write(part.content)
+		}
+	} else {
+		hash.Write(body)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+type multipartPart struct {
+	name     string
+	fileName string
+	content  []byte
+}
+
+// multipartParts reads a multipart/form-data body, reporting false when the
+// body is not one or cannot be read.
+func multipartParts(contentType string, body []byte) ([]multipartPart, bool) {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil || mediaType != "multipart/form-data" || params["boundary"] == "" {
+		return nil, false
+	}
+
+	var parts []multipartPart
+	reader := multipart.NewReader(bytes.NewReader(body), params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return parts, true
+		}
+		if err != nil {
+			return nil, false
+		}
+
+		content, err := io.ReadAll(part)
+		if err != nil {
+			return nil, false
+		}
+		parts = append(parts, multipartPart{name: part.FormName(), fileName: part.FileName(), content: content})
+	}
+}
+
+// responseRecorder passes a response through while keeping a copy of its
+// status and body.
+type responseRecorder struct {
+	http.ResponseWriter
+	statusCode int
+	body       bytes.Buffer
+}
+
+func (recorder *responseRecorder) WriteHeader(statusCode int) {
+	if recorder.statusCode == 0 {
+		recorder.statusCode = statusCode
+	}
+	recorder.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (recorder *responseRecorder) Write(data []byte) (int, error) {
+	if recorder.statusCode == 0 {
+		recorder.statusCode = http.StatusOK
+	}
+	recorder.body.Write(data)
+	return recorder.ResponseWriter.Write(data)
+}
+
+func (recorder *responseRecorder) StatusCode() int {
+	if recorder.statusCode == 0 {
+		return http.StatusOK
+	}
+	return recorder.statusCode
+}
@@ -0,0 +1,43 @@
+package domain
+
+import "time"
+
+// AccessTokenPrefix starts every personal access token, which tells them
+// apart from the JWTs issued by Login.
+const AccessTokenPrefix = "pat_"
+
+// Scopes a personal access token can be granted. Each route names the
+// scopes it needs; a JWT from Login has all of them.
+const (
+	ScopeReadExpenses    = "read:expenses"
+	ScopeWriteExpenses   = "write:expenses"
+	ScopeReadCategories  = "read:categories"
+	ScopeWriteCategories = "write:categories"
+	ScopeReadRules       = "read:rules"
+	ScopeWriteRules      = "write:rules"
+	ScopeReadViews       = "read:views"
+	ScopeWriteViews      = "write:views"
+	ScopeReadRecurring   = "read:recurring"
+	ScopeWriteRecurring  = "write:recurring"
+	ScopeReadInsights    = "read:insights"
+	ScopeReadActivity    = "read:activity"
+	ScopeReadTrash       = "read:trash"
+	ScopeWriteTrash      = "write:trash"
+	ScopeReadWebhooks    = "read:webhooks"
+	ScopeWriteWebhooks   = "write:webhooks"
+)
+
+// AccessToken is a personal access token. Only the SHA-256 hash of the
+// token is stored; Prefix keeps its first characters so users can tell
+// their tokens apart.
+type AccessToken struct {
+	Id         int
+	UserId     int
+	Name       string
+	Prefix     string
+	Hash       string
+	Scopes     []string
+	LastUsedAt *time.Time
+	ExpiresAt  *time.Time
+	CreatedAt  time.Time
+}
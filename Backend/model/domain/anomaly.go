@@ -0,0 +1,22 @@
+package domain
+
+import "time"
+
+const (
+	AnomalyAmountSpike     = "amount_spike"
+	AnomalyFrequencySpike  = "frequency_spike"
+	AnomalyDormantCategory = "dormant_category"
+)
+
+// Anomaly is an expense that stands out against the user's history for its
+// category. Baseline is what the expense is compared with: the median amount
+// for amount spikes, the average weekly count for frequency spikes and the
+// number of idle days for dormant categories. Observed is the matching value
+// for the flagged expense.
+type Anomaly struct {
+	Kind       string
+	Expense    Expense
+	Baseline   float64
+	Observed   float64
+	OccurredAt time.Time
+}
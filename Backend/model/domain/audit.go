@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	AuditEntityExpense  = "expense"
+	AuditEntityCategory = "category"
+
+	AuditActionCreate  = "create"
+	AuditActionUpdate  = "update"
+	AuditActionDelete  = "delete"
+	AuditActionRestore = "restore"
+	AuditActionPurge   = "purge"
+)
+
+// AuditEntry is one change to an expense or category. Before is empty for
+// creates and After for deletes. ActorId is nil for changes made by the
+// server itself, such as recurring expenses.
+type AuditEntry struct {
+	Id         int64
+	UserId     int
+	ActorId    *int
+	EntityType string
+	EntityId   int
+	Action     string
+	Before     json.RawMessage
+	After      json.RawMessage
+	RequestId  string
+	CreatedAt  time.Time
+}
+
+type AuditFilter struct {
+	EntityType string
+	EntityId   int
+	BeforeId   int64
+	Limit      int
+}
@@ -0,0 +1,23 @@
+package domain
+
+import "time"
+
+type CategoryRule struct {
+	Id                  int
+	UserId              int
+	CategoryId          int
+	Name                string
+	DescriptionContains string
+	DescriptionPattern  string
+	MinAmount           *float64
+	MaxAmount           *float64
+	Priority            int
+	CreatedAt           time.Time
+}
+
+type CategoryRuleChange struct {
+	ExpenseId      int
+	FromCategoryId int
+	ToCategoryId   int
+	RuleId         int
+}
@@ -0,0 +1,26 @@
+package domain
+
+import "time"
+
+const (
+	DataExportPending = "pending"
+	DataExportRunning = "running"
+	DataExportReady   = "ready"
+	DataExportFailed  = "failed"
+	// DataExportExpired marks an export whose archive has been deleted.
+	DataExportExpired = "expired"
+)
+
+// DataExport is a takeout requested by a user. The archive itself is only
+// loaded for downloads.
+type DataExport struct {
+	Id          int64
+	UserId      int
+	Status      string
+	SizeBytes   int64
+	Error       string
+	RequestedAt time.Time
+	StartedAt   *time.Time
+	CompletedAt *time.Time
+	ExpiresAt   *time.Time
+}
@@ -9,4 +9,6 @@ type Expense struct {
 	Amount      float64
 	CreatedAt   time.Time
 	UserId      int
+	// Version starts at 1 and goes up with every update.
+	Version int
 }
@@ -0,0 +1,19 @@
+package domain
+
+type SearchTerm struct {
+	Text   string
+	Prefix bool
+	Phrase bool
+}
+
+type ExpenseSearchQuery struct {
+	Terms  []SearchTerm
+	Limit  int
+	Offset int
+}
+
+type ExpenseSearchResult struct {
+	Expense      Expense
+	CategoryName string
+	Score        float64
+}
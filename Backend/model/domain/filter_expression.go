@@ -0,0 +1,20 @@
+package domain
+
+type FilterNodeKind int
+
+const (
+	FilterAnd FilterNodeKind = iota
+	FilterOr
+	FilterNot
+	FilterCompare
+)
+
+// FilterNode is one node of a parsed `q` filter expression. And/Or/Not nodes
+// only use Children; Compare nodes only use Field, Operator and Value.
+type FilterNode struct {
+	Kind     FilterNodeKind
+	Children []FilterNode
+	Field    string
+	Operator string
+	Value    interface{}
+}
@@ -0,0 +1,39 @@
+package domain
+
+import "time"
+
+// RecurringCharge is a payment that showed up about monthly with a stable
+// amount, recognised from its category and description.
+type RecurringCharge struct {
+	Key         string
+	CategoryId  int
+	Description string
+	Amount      float64
+	DayOfMonth  int
+	Occurrences int
+	LastSeen    time.Time
+}
+
+type ForecastLine struct {
+	CategoryId   int
+	CategoryName string
+	SpentToDate  float64
+	Recurring    float64
+	Point        float64
+	Low          float64
+	High         float64
+}
+
+type MonthForecast struct {
+	Month time.Time
+	Lines []ForecastLine
+	Total ForecastLine
+}
+
+type Forecast struct {
+	AsOf        time.Time
+	DaysElapsed float64
+	DaysInMonth int
+	Current     MonthForecast
+	Next        []MonthForecast
+}
@@ -0,0 +1,19 @@
+package domain
+
+const (
+	IdempotencyInFlight  = "in_flight"
+	IdempotencyCompleted = "completed"
+)
+
+// IdempotencyRecord is what an Idempotency-Key maps to in Redis. While the
+// first request runs it only holds the fingerprint; once it completes the
+// response is stored so retries can be answered from it.
+type IdempotencyRecord struct {
+	State string
+	// Fingerprint hashes the method, path and body, so the key cannot be
+	// reused for a different request.
+	Fingerprint string
+	StatusCode  int
+	Header      map[string]string
+	Body        []byte
+}
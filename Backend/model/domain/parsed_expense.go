@@ -0,0 +1,14 @@
+package domain
+
+import "time"
+
+type ParsedExpense struct {
+	Amount          *float64
+	AmountText      string
+	CreatedAt       time.Time
+	DateText        string
+	CategoryId      int
+	CategoryName    string
+	CategoryKeyword string
+	Description     string
+}
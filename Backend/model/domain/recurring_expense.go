@@ -0,0 +1,19 @@
+package domain
+
+import "time"
+
+// RecurringExpense creates an expense every cadence period, starting at
+// NextRunAt. Monthly and longer cadences keep to AnchorDay. SourceKey links
+// it to the subscription it was converted from.
+type RecurringExpense struct {
+	Id          int
+	UserId      int
+	CategoryId  int
+	Description string
+	Amount      float64
+	Cadence     string
+	NextRunAt   time.Time
+	AnchorDay   int
+	SourceKey   string
+	CreatedAt   time.Time
+}
@@ -0,0 +1,10 @@
+package domain
+
+const (
+	// RestoreModeMerge adds the archive to what the account has. Categories
+	// are matched by name and expenses already there are skipped.
+	RestoreModeMerge = "merge"
+	// RestoreModeReplace moves the account's categories and expenses to the
+	// trash before restoring.
+	RestoreModeReplace = "replace"
+)
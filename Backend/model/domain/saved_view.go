@@ -0,0 +1,21 @@
+package domain
+
+import "time"
+
+const (
+	SavedViewKindExpense = "expense"
+	SavedViewKindSummary = "summary"
+)
+
+type SavedView struct {
+	Id        int
+	UserId    int
+	Name      string
+	Kind      string
+	Filter    map[string]string
+	SortBy    string
+	Order     string
+	IsDefault bool
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
@@ -0,0 +1,43 @@
+package domain
+
+import "time"
+
+// StatementTransaction is one booked transaction read from a bank statement
+// file. Amount is always positive; Debit tells money leaving the account.
+// ExternalId is BankId scoped by Account, since banks only keep their
+// transaction ids unique per account.
+type StatementTransaction struct {
+	ExternalId  string
+	BankId      string
+	Account     string
+	PostedAt    time.Time
+	Amount      float64
+	Debit       bool
+	Currency    string
+	Description string
+}
+
+const (
+	StatementItemImported        = "imported"
+	StatementItemWouldImport     = "would_import"
+	StatementItemAlreadyImported = "already_imported"
+	StatementItemDuplicate       = "possible_duplicate"
+	StatementItemUncategorized   = "uncategorized"
+)
+
+type StatementImportItem struct {
+	Transaction    StatementTransaction
+	Status         string
+	ExpenseId      int
+	CategoryId     int
+	DuplicateOf    int
+	DuplicateScore float64
+}
+
+type StatementImportResult struct {
+	Format         string
+	DryRun         bool
+	Debits         int
+	SkippedCredits int
+	Items          []StatementImportItem
+}
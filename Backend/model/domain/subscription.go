@@ -0,0 +1,37 @@
+package domain
+
+import "time"
+
+const (
+	CadenceWeekly    = "weekly"
+	CadenceBiweekly  = "biweekly"
+	CadenceMonthly   = "monthly"
+	CadenceQuarterly = "quarterly"
+	CadenceYearly    = "yearly"
+)
+
+type PriceChange struct {
+	Previous  float64
+	Current   float64
+	ChangedAt time.Time
+}
+
+// Subscription is a merchant or description the user pays at a regular
+// cadence, detected from their expenses.
+type Subscription struct {
+	Key            string
+	CategoryId     int
+	Description    string
+	Cadence        string
+	Amount         float64
+	AnnualizedCost float64
+	Occurrences    int
+	FirstSeen      time.Time
+	LastSeen       time.Time
+	// AnchorDay is the day of month payments are due on, for monthly and
+	// longer cadences.
+	AnchorDay    int
+	NextExpected time.Time
+	Active       bool
+	PriceChange  *PriceChange
+}
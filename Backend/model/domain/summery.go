@@ -1,8 +1,10 @@
 package domain
 
+import "time"
+
 type ExpenseRecord struct {
 	Amount      float64
-	Date        string
+	CreatedAt   time.Time
 	Description string
 }
 
@@ -17,5 +19,19 @@ type SummaryFilter struct {
 	Day         *int
 	Month       *int
 	Year        *int
+	Week        *time.Time
 	Description *string
+	Timezone    string
+	// From and To bound created_at to [From, To). They are worked out from
+	// Day, Month, Year or Week in the user's time zone.
+	From *time.Time
+	To   *time.Time
+}
+
+type SummaryRecord struct {
+	CategoryId   int
+	CategoryName string
+	Description  string
+	Amount       float64
+	CreatedAt    time.Time
 }
@@ -0,0 +1,28 @@
+package domain
+
+import "time"
+
+// SyncChange is one audit log entry seen by the change feed. Seq is the
+// entry's id, which is what sync tokens count.
+type SyncChange struct {
+	Seq        int64
+	EntityType string
+	EntityId   int
+}
+
+// SyncedExpense is an expense as the sync API sees it: trashed ones are
+// included, with DeletedAt set, and ClientId is the id the client created
+// it under, if any.
+type SyncedExpense struct {
+	Expense      Expense
+	CategoryName string
+	ClientId     string
+	DeletedAt    *time.Time
+}
+
+// SyncedCategory is the category counterpart of SyncedExpense.
+type SyncedCategory struct {
+	Category  Category
+	ClientId  string
+	DeletedAt *time.Time
+}
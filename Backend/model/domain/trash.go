@@ -0,0 +1,26 @@
+package domain
+
+import "time"
+
+// TrashedExpense is a soft-deleted expense. CategoryName is filled even when
+// the category is in the trash as well.
+type TrashedExpense struct {
+	Expense      Expense
+	CategoryName string
+	DeletedAt    time.Time
+}
+
+// TrashedCategory is a soft-deleted category. ExpenseCount counts the
+// expenses that were deleted together with it.
+type TrashedCategory struct {
+	Category     Category
+	ExpenseCount int
+	DeletedAt    time.Time
+}
+
+// TrashItem identifies a row in the trash of any user, used by the purge job.
+type TrashItem struct {
+	UserId     int
+	EntityType string
+	EntityId   int
+}
@@ -0,0 +1,29 @@
+package domain
+
+import "time"
+
+// TwoFactor is a user's TOTP setup. It only guards logins once
+// ConfirmedAt is set, i.e. after the user has proven their authenticator
+// app produces matching codes.
+type TwoFactor struct {
+	UserId      int
+	Secret      string
+	ConfirmedAt *time.Time
+	// LastUsedStep is the TOTP time step of the last accepted code; codes
+	// at or before it are rejected so none can be replayed.
+	LastUsedStep int64
+	CreatedAt    time.Time
+}
+
+type RecoveryCode struct {
+	Id     int
+	UserId int
+	Hash   string
+	UsedAt *time.Time
+}
+
+// TwoFactorChallenge is a login that passed the password check and waits
+// for the second factor.
+type TwoFactorChallenge struct {
+	UserId int
+}
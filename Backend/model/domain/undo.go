@@ -0,0 +1,41 @@
+package domain
+
+import "time"
+
+const (
+	UndoKindDeleteExpense  = "delete_expense"
+	UndoKindDeleteCategory = "delete_category"
+	UndoKindApplyRules     = "apply_rules"
+	UndoKindImport         = "import"
+	UndoKindBulkCreate     = "bulk_create"
+	UndoKindBulkUpdate     = "bulk_update"
+	UndoKindBulkDelete     = "bulk_delete"
+
+	// UndoRestoreExpense and UndoRestoreCategory take a deleted row out of
+	// the trash.
+	UndoRestoreExpense  = "restore_expense"
+	UndoRestoreCategory = "restore_category"
+	// UndoRevertExpense writes Before back over an updated expense.
+	UndoRevertExpense = "revert_expense"
+	// UndoRemoveExpense deletes a created expense for good.
+	UndoRemoveExpense = "remove_expense"
+)
+
+// UndoStep reverses one change. After is the expense as the operation left
+// it; the step refuses to run once the expense has changed since.
+type UndoStep struct {
+	Action   string
+	EntityId int
+	Before   *Expense
+	After    *Expense
+}
+
+// UndoOperation is a pending undo kept in Redis until it is used or expires.
+type UndoOperation struct {
+	Token     string
+	UserId    int
+	Kind      string
+	Steps     []UndoStep
+	CreatedAt time.Time
+	ExpiresAt time.Time
+}
@@ -1,8 +1,13 @@
 package domain
 
+import "time"
+
 type User struct {
 	Id           int
 	Username     string
 	Email        string
 	PasswordHash string
+	// DeletionScheduledAt is when the account and all its data are deleted;
+	// nil unless the user has asked for deletion.
+	DeletionScheduledAt *time.Time
 }
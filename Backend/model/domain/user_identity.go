@@ -0,0 +1,21 @@
+package domain
+
+import "time"
+
+// UserIdentity links an account at an OpenID Connect provider, named by
+// issuer and subject, to a user.
+type UserIdentity struct {
+	Id        int
+	UserId    int
+	Issuer    string
+	Subject   string
+	Email     string
+	CreatedAt time.Time
+}
+
+// SsoState is what a sign-in started with /sso/authorize needs to finish
+// it: the PKCE verifier and the nonce expected in the ID token.
+type SsoState struct {
+	Verifier string
+	Nonce    string
+}
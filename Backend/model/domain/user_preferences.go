@@ -0,0 +1,49 @@
+package domain
+
+import "time"
+
+// The defaults are what the app assumed before preferences existed.
+const (
+	DefaultTimezone   = "Asia/Jakarta"
+	DefaultLocale     = "id-ID"
+	DefaultCurrency   = "IDR"
+	DefaultWeekStart  = "monday"
+	DefaultDateFormat = "YYYY-MM-DD"
+)
+
+// WeekStarts are the days a week can start on.
+var WeekStarts = map[string]time.Weekday{
+	"saturday": time.Saturday,
+	"sunday":   time.Sunday,
+	"monday":   time.Monday,
+}
+
+// DateLayouts maps the date formats a user can pick to Go layouts.
+var DateLayouts = map[string]string{
+	"YYYY-MM-DD": "2006-01-02",
+	"DD/MM/YYYY": "02/01/2006",
+	"MM/DD/YYYY": "01/02/2006",
+	"DD.MM.YYYY": "02.01.2006",
+	"DD-MM-YYYY": "02-01-2006",
+}
+
+type UserPreferences struct {
+	UserId     int
+	Timezone   string
+	Locale     string
+	Currency   string
+	WeekStart  string
+	DateFormat string
+	UpdatedAt  time.Time
+}
+
+func DefaultUserPreferences(userId int) UserPreferences {
+	return UserPreferences{
+		UserId:     userId,
+		Timezone:   DefaultTimezone,
+		Locale:     DefaultLocale,
+		Currency:   DefaultCurrency,
+		WeekStart:  DefaultWeekStart,
+		DateFormat: DefaultDateFormat,
+	}
+}
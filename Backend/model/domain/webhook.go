@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	WebhookEventExpenseCreated = "expense.created"
+	WebhookEventExpenseUpdated = "expense.updated"
+	WebhookEventExpenseDeleted = "expense.deleted"
+
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliveryDelivered = "delivered"
+	// WebhookDeliveryDead marks a delivery that ran out of attempts. It
+	// stays in the dead-letter list until it is retried by hand.
+	WebhookDeliveryDead = "dead"
+)
+
+type Webhook struct {
+	Id        int
+	UserId    int
+	Url       string
+	Secret    string
+	Events    []string
+	CreatedAt time.Time
+}
+
+// WebhookEvent is a row in the outbox. It is written in the transaction of
+// the change it describes and fanned out to deliveries afterwards.
+type WebhookEvent struct {
+	Id        int64
+	UserId    int
+	Type      string
+	Payload   json.RawMessage
+	CreatedAt time.Time
+}
+
+// WebhookDelivery is one event on its way to one webhook. Url, Secret and
+// Payload are only filled in for deliveries claimed for sending.
+type WebhookDelivery struct {
+	Id             int64
+	WebhookId      int
+	EventId        int64
+	EventType      string
+	Status         string
+	Attempts       int
+	NextAttemptAt  time.Time
+	LastStatusCode *int
+	LastError      string
+	DeliveredAt    *time.Time
+	CreatedAt      time.Time
+	Url            string
+	Secret         string
+	Payload        json.RawMessage
+	EventCreatedAt time.Time
+}
+
+type WebhookDeliveryFilter struct {
+	Status    string
+	WebhookId int
+	BeforeId  int64
+	Limit     int
+}
@@ -0,0 +1,9 @@
+package web
+
+type AccessTokenCreateRequest struct {
+	Name   string   `json:"name" validate:"required,max=100"`
+	Scopes []string `json:"scopes" validate:"required,min=1,dive,oneof=read:expenses write:expenses read:categories write:categories read:rules write:rules read:views write:views read:recurring write:recurring read:insights read:activity read:trash write:trash read:webhooks write:webhooks"`
+	// ExpiresInDays is optional; a token without it lasts until revoked.
+	ExpiresInDays int `json:"expires_in_days" validate:"omitempty,min=1,max=3650"`
+	UserId        int `json:"user_id" validate:"required,gt=0"`
+}
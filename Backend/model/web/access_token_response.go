@@ -0,0 +1,15 @@
+package web
+
+import "time"
+
+type AccessTokenResponse struct {
+	Id     int      `json:"id"`
+	Name   string   `json:"name"`
+	Prefix string   `json:"prefix"`
+	Scopes []string `json:"scopes"`
+	// Token is only returned when the token is created.
+	Token      string     `json:"token,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
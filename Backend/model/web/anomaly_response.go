@@ -0,0 +1,22 @@
+package web
+
+import "time"
+
+type AnomalyResponse struct {
+	Kind         string    `json:"kind"`
+	Message      string    `json:"message"`
+	ExpenseId    int       `json:"expense_id"`
+	CategoryId   int       `json:"category_id"`
+	CategoryName string    `json:"category_name,omitempty"`
+	Description  string    `json:"description,omitempty"`
+	Amount       float64   `json:"amount"`
+	Baseline     float64   `json:"baseline"`
+	Observed     float64   `json:"observed"`
+	OccurredAt   time.Time `json:"occurred_at"`
+}
+
+type AnomalyListResponse struct {
+	From      time.Time         `json:"from"`
+	To        time.Time         `json:"to"`
+	Anomalies []AnomalyResponse `json:"anomalies"`
+}
@@ -0,0 +1,23 @@
+package web
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type AuditEntryResponse struct {
+	Id         int64           `json:"id"`
+	EntityType string          `json:"entity_type"`
+	EntityId   int             `json:"entity_id"`
+	Action     string          `json:"action"`
+	ActorId    *int            `json:"actor_id"`
+	RequestId  string          `json:"request_id,omitempty"`
+	Before     json.RawMessage `json:"before"`
+	After      json.RawMessage `json:"after"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+type AuditListResponse struct {
+	Entries      []AuditEntryResponse `json:"entries"`
+	NextBeforeId int64                `json:"next_before_id,omitempty"`
+}
@@ -0,0 +1,18 @@
+package web
+
+type CategoryRuleRequest struct {
+	Id                  int      `json:"id"`
+	Name                string   `json:"name" validate:"required,max=100"`
+	CategoryId          int      `json:"category_id" validate:"required,gt=0"`
+	DescriptionContains string   `json:"description_contains" validate:"max=100"`
+	DescriptionPattern  string   `json:"description_pattern" validate:"max=200"`
+	MinAmount           *float64 `json:"min_amount" validate:"omitempty,gte=0"`
+	MaxAmount           *float64 `json:"max_amount" validate:"omitempty,gte=0"`
+	Priority            int      `json:"priority" validate:"gte=0"`
+	UserId              int      `json:"user_id" validate:"required,gt=0"`
+}
+
+type CategoryRuleApplyRequest struct {
+	DryRun bool `json:"dry_run"`
+	UserId int  `json:"user_id" validate:"required,gt=0"`
+}
@@ -0,0 +1,32 @@
+package web
+
+import "time"
+
+type CategoryRuleResponse struct {
+	Id                  int       `json:"id"`
+	Name                string    `json:"name"`
+	CategoryId          int       `json:"category_id"`
+	CategoryName        string    `json:"category_name"`
+	DescriptionContains string    `json:"description_contains"`
+	DescriptionPattern  string    `json:"description_pattern"`
+	MinAmount           *float64  `json:"min_amount"`
+	MaxAmount           *float64  `json:"max_amount"`
+	Priority            int       `json:"priority"`
+	CreatedAt           time.Time `json:"created_at"`
+}
+
+type CategoryRuleChangeResponse struct {
+	ExpenseId      int `json:"expense_id"`
+	FromCategoryId int `json:"from_category_id"`
+	ToCategoryId   int `json:"to_category_id"`
+	RuleId         int `json:"rule_id"`
+}
+
+type CategoryRuleApplyResponse struct {
+	DryRun  bool                         `json:"dry_run"`
+	Checked int                          `json:"checked"`
+	Updated int                          `json:"updated"`
+	Skipped int                          `json:"skipped"`
+	Changes []CategoryRuleChangeResponse `json:"changes"`
+	Undo    *UndoTokenResponse           `json:"undo,omitempty"`
+}
@@ -0,0 +1,8 @@
+package web
+
+type CategorySuggestRequest struct {
+	Description string  `json:"description" validate:"required,max=255"`
+	Amount      float64 `json:"amount" validate:"gte=0"`
+	Limit       int     `json:"limit" validate:"gte=0,lte=20"`
+	UserId      int     `json:"user_id" validate:"required,gt=0"`
+}
@@ -0,0 +1,12 @@
+package web
+
+type CategorySuggestionResponse struct {
+	CategoryId   int     `json:"category_id"`
+	CategoryName string  `json:"category_name"`
+	Confidence   float64 `json:"confidence"`
+}
+
+type CategorySuggestResponse struct {
+	TrainedOn   int                          `json:"trained_on"`
+	Suggestions []CategorySuggestionResponse `json:"suggestions"`
+}
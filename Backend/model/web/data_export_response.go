@@ -0,0 +1,18 @@
+package web
+
+import "time"
+
+type DataExportResponse struct {
+	Id          int64      `json:"id"`
+	Status      string     `json:"status"`
+	SizeBytes   int64      `json:"size_bytes,omitempty"`
+	Error       string     `json:"error,omitempty"`
+	RequestedAt time.Time  `json:"requested_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	// ExpiresAt is when the archive is deleted.
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	// DownloadUrl works without a token until DownloadExpiresAt. It is only
+	// set on a ready export fetched by id.
+	DownloadUrl       string     `json:"download_url,omitempty"`
+	DownloadExpiresAt *time.Time `json:"download_expires_at,omitempty"`
+}
@@ -3,7 +3,7 @@ package web
 import "github.com/ryhnfhrza/Expense-Tracker/internal/types"
 
 type ExpenseCreateRequest struct {
-	CategoryId  int               `json:"category_id" validate:"required,gt=0"`
+	CategoryId  int               `json:"category_id" validate:"omitempty,gt=0"`
 	Description string            `json:"description"`
 	Amount      float64           `json:"amount" validate:"required"`
 	CreatedAt   *types.CustomTime `json:"created_at"`
@@ -17,4 +17,34 @@ type ExpenseUpdateRequest struct {
 	Amount      *float64          `json:"amount"`
 	CreatedAt   *types.CustomTime `json:"created_at"`
 	UserId      int               `json:"user_id" validate:"required,gt=0"`
+	// Version comes from the If-Match header; 0 matches any version.
+	Version int `json:"-"`
+}
+
+type ExpenseQuickAddRequest struct {
+	Text       string `json:"text" validate:"required,max=255"`
+	Create     bool   `json:"create"`
+	Timezone   string `json:"tz"`
+	CategoryId *int   `json:"category_id"`
+	UserId     int    `json:"user_id" validate:"required,gt=0"`
+}
+
+// ExpenseBulkRequest applies one action to many expenses. Create takes Items;
+// update and delete take either Ids or Filter, which accepts the same keys as
+// the GET /api/expense query string.
+type ExpenseBulkRequest struct {
+	Action string                 `json:"action" validate:"required,oneof=create update delete"`
+	Items  []ExpenseCreateRequest `json:"items" validate:"max=1000"`
+	Ids    []int                  `json:"ids" validate:"max=1000"`
+	Filter map[string]string      `json:"filter"`
+	Set    *ExpenseBulkSet        `json:"set"`
+	UserId int                    `json:"user_id" validate:"required,gt=0"`
+}
+
+// ExpenseBulkSet lists the fields a bulk update changes; nil fields are kept.
+type ExpenseBulkSet struct {
+	CategoryId  *int              `json:"category_id" validate:"omitempty,gt=0"`
+	Description *string           `json:"description" validate:"omitempty,max=255"`
+	Amount      *float64          `json:"amount" validate:"omitempty,gt=0"`
+	CreatedAt   *types.CustomTime `json:"created_at"`
 }
@@ -9,4 +9,41 @@ type ExpenseResponse struct {
 	Description  string    `json:"description"`
 	Amount       float64   `json:"amount"`
 	CreatedAt    time.Time `json:"created_at"`
+	Version      int       `json:"version,omitempty"`
+	// Anomalies is only filled in by CreateExpense.
+	Anomalies []AnomalyResponse `json:"anomalies,omitempty"`
+}
+
+type ParsedExpenseResponse struct {
+	Amount          *float64  `json:"amount"`
+	AmountText      string    `json:"amount_text"`
+	CreatedAt       time.Time `json:"created_at"`
+	DateText        string    `json:"date_text"`
+	CategoryId      *int      `json:"category_id"`
+	CategoryName    string    `json:"category_name"`
+	CategoryKeyword string    `json:"category_keyword"`
+	Description     string    `json:"description"`
+}
+
+type ExpenseQuickAddResponse struct {
+	Parsed  ParsedExpenseResponse `json:"parsed"`
+	Expense *ExpenseResponse      `json:"expense,omitempty"`
+}
+
+type ExpenseBulkItemResponse struct {
+	// Index is the position in items or ids, or in the filter's results.
+	Index   int              `json:"index"`
+	Id      int              `json:"id,omitempty"`
+	Status  string           `json:"status"`
+	Error   string           `json:"error,omitempty"`
+	Expense *ExpenseResponse `json:"expense,omitempty"`
+}
+
+type ExpenseBulkResponse struct {
+	Action    string                    `json:"action"`
+	Total     int                       `json:"total"`
+	Succeeded int                       `json:"succeeded"`
+	Failed    int                       `json:"failed"`
+	Items     []ExpenseBulkItemResponse `json:"items"`
+	Undo      *UndoTokenResponse        `json:"undo,omitempty"`
 }
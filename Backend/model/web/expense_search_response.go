@@ -0,0 +1,21 @@
+package web
+
+import "time"
+
+type ExpenseSearchResultResponse struct {
+	Id           int       `json:"id"`
+	CategoryId   int       `json:"category_id"`
+	CategoryName string    `json:"category_name"`
+	Description  string    `json:"description"`
+	Snippet      string    `json:"snippet"`
+	Amount       float64   `json:"amount"`
+	CreatedAt    time.Time `json:"created_at"`
+	Score        float64   `json:"score"`
+}
+
+type ExpenseSearchResponse struct {
+	Total   int                           `json:"total"`
+	Limit   int                           `json:"limit"`
+	Offset  int                           `json:"offset"`
+	Results []ExpenseSearchResultResponse `json:"results"`
+}
@@ -0,0 +1,28 @@
+package web
+
+import "time"
+
+type ForecastLineResponse struct {
+	CategoryId   int     `json:"category_id,omitempty"`
+	CategoryName string  `json:"category_name,omitempty"`
+	SpentToDate  float64 `json:"spent_to_date"`
+	Recurring    float64 `json:"recurring"`
+	Point        float64 `json:"point"`
+	Low          float64 `json:"low"`
+	High         float64 `json:"high"`
+}
+
+type MonthForecastResponse struct {
+	Month      string                 `json:"month"`
+	Categories []ForecastLineResponse `json:"categories"`
+	Total      ForecastLineResponse   `json:"total"`
+}
+
+type ForecastResponse struct {
+	Timezone    string                  `json:"tz"`
+	AsOf        time.Time               `json:"as_of"`
+	DaysElapsed float64                 `json:"days_elapsed"`
+	DaysInMonth int                     `json:"days_in_month"`
+	Current     MonthForecastResponse   `json:"current"`
+	Next        []MonthForecastResponse `json:"next"`
+}
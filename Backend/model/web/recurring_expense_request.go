@@ -0,0 +1,6 @@
+package web
+
+type RecurringExpenseCreateRequest struct {
+	SubscriptionKey string `json:"subscription_key" validate:"required,max=191"`
+	UserId          int    `json:"user_id" validate:"required,gt=0"`
+}
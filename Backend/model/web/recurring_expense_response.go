@@ -0,0 +1,15 @@
+package web
+
+import "time"
+
+type RecurringExpenseResponse struct {
+	Id           int       `json:"id"`
+	CategoryId   int       `json:"category_id"`
+	CategoryName string    `json:"category_name"`
+	Description  string    `json:"description"`
+	Amount       float64   `json:"amount"`
+	Cadence      string    `json:"cadence"`
+	NextRunAt    time.Time `json:"next_run_at"`
+	SourceKey    string    `json:"subscription_key"`
+	CreatedAt    time.Time `json:"created_at"`
+}
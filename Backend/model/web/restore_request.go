@@ -0,0 +1,7 @@
+package web
+
+type TakeoutRestoreRequest struct {
+	Mode   string `validate:"required,oneof=merge replace"`
+	Data   []byte `validate:"required"`
+	UserId int    `validate:"required,gt=0"`
+}
@@ -0,0 +1,21 @@
+package web
+
+type TakeoutRestoreResponse struct {
+	Mode              string `json:"mode"`
+	CategoriesCreated int    `json:"categories_created"`
+	CategoriesMatched int    `json:"categories_matched"`
+	ExpensesCreated   int    `json:"expenses_created"`
+	// ExpensesSkipped counts expenses the account already had, in merge
+	// mode.
+	ExpensesSkipped int `json:"expenses_skipped"`
+	// TrashedSkipped counts categories and expenses that were in the trash
+	// when the archive was made. They are not restored.
+	TrashedSkipped int `json:"trashed_skipped"`
+	// CategoriesTrashed and ExpensesTrashed count what replace mode moved
+	// to the trash.
+	CategoriesTrashed int `json:"categories_trashed"`
+	ExpensesTrashed   int `json:"expenses_trashed"`
+	// CategoryIds maps the category ids in the archive to the ids in this
+	// account.
+	CategoryIds map[int]int `json:"category_ids"`
+}
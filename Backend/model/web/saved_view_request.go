@@ -0,0 +1,12 @@
+package web
+
+type SavedViewRequest struct {
+	Id        int               `json:"id"`
+	Name      string            `json:"name" validate:"required,max=100"`
+	Kind      string            `json:"kind" validate:"omitempty,oneof=expense summary"`
+	Filter    map[string]string `json:"filter"`
+	SortBy    string            `json:"sort_by" validate:"omitempty,oneof=created_at amount description category_id id"`
+	Order     string            `json:"order" validate:"omitempty,oneof=asc desc"`
+	IsDefault bool              `json:"is_default"`
+	UserId    int               `json:"user_id" validate:"required,gt=0"`
+}
@@ -0,0 +1,15 @@
+package web
+
+import "time"
+
+type SavedViewResponse struct {
+	Id        int               `json:"id"`
+	Name      string            `json:"name"`
+	Kind      string            `json:"kind"`
+	Filter    map[string]string `json:"filter"`
+	SortBy    string            `json:"sort_by"`
+	Order     string            `json:"order"`
+	IsDefault bool              `json:"is_default"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
@@ -0,0 +1,6 @@
+package web
+
+type SsoCallbackRequest struct {
+	Code  string `json:"code" validate:"required"`
+	State string `json:"state" validate:"required"`
+}
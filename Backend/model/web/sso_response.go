@@ -0,0 +1,6 @@
+package web
+
+type SsoAuthorizeResponse struct {
+	AuthorizationUrl string `json:"authorization_url"`
+	State            string `json:"state"`
+}
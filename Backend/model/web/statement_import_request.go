@@ -0,0 +1,10 @@
+package web
+
+type StatementImportRequest struct {
+	Format     string `validate:"omitempty,oneof=ofx qfx camt053"`
+	CategoryId int    `validate:"gte=0"`
+	DryRun     bool
+	Confirm    []string
+	Data       []byte `validate:"required"`
+	UserId     int    `validate:"required,gt=0"`
+}
@@ -0,0 +1,27 @@
+package web
+
+import "time"
+
+type StatementImportItemResponse struct {
+	ExternalId     string    `json:"external_id"`
+	PostedAt       time.Time `json:"posted_at"`
+	Amount         float64   `json:"amount"`
+	Currency       string    `json:"currency,omitempty"`
+	Description    string    `json:"description"`
+	Status         string    `json:"status"`
+	ExpenseId      int       `json:"expense_id,omitempty"`
+	CategoryId     int       `json:"category_id,omitempty"`
+	DuplicateOf    int       `json:"duplicate_of,omitempty"`
+	DuplicateScore float64   `json:"duplicate_score,omitempty"`
+}
+
+type StatementImportResponse struct {
+	Format         string                        `json:"format"`
+	DryRun         bool                          `json:"dry_run"`
+	Debits         int                           `json:"debits"`
+	SkippedCredits int                           `json:"skipped_credits"`
+	Imported       int                           `json:"imported"`
+	Duplicates     int                           `json:"duplicates"`
+	Items          []StatementImportItemResponse `json:"items"`
+	Undo           *UndoTokenResponse            `json:"undo,omitempty"`
+}
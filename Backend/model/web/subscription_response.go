@@ -0,0 +1,32 @@
+package web
+
+import "time"
+
+type PriceChangeResponse struct {
+	Previous  float64   `json:"previous"`
+	Current   float64   `json:"current"`
+	Percent   float64   `json:"percent"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+type SubscriptionResponse struct {
+	Key            string               `json:"key"`
+	CategoryId     int                  `json:"category_id"`
+	CategoryName   string               `json:"category_name"`
+	Description    string               `json:"description"`
+	Cadence        string               `json:"cadence"`
+	Amount         float64              `json:"amount"`
+	AnnualizedCost float64              `json:"annualized_cost"`
+	Occurrences    int                  `json:"occurrences"`
+	FirstSeen      time.Time            `json:"first_seen"`
+	LastSeen       time.Time            `json:"last_seen"`
+	NextExpected   time.Time            `json:"next_expected"`
+	Active         bool                 `json:"active"`
+	PriceChange    *PriceChangeResponse `json:"price_change"`
+	RecurringId    *int                 `json:"recurring_id"`
+}
+
+type SubscriptionListResponse struct {
+	AnnualizedTotal float64                `json:"annualized_total"`
+	Subscriptions   []SubscriptionResponse `json:"subscriptions"`
+}
@@ -1,18 +1,22 @@
 package web
 
 type SummaryDetailResponse struct {
-	CategoryName string                  `json:"category_name"`
-	Records      []ExpenseRecordResponse `json:"records"`
-	Total        float64                 `json:"total"`
+	CategoryName   string                  `json:"category_name"`
+	Records        []ExpenseRecordResponse `json:"records"`
+	Total          float64                 `json:"total"`
+	TotalFormatted string                  `json:"total_formatted"`
 }
 
 type ExpenseRecordResponse struct {
-	Amount      float64 `json:"amount"`
-	Date        string  `json:"date"`
-	Description string  `json:"description"`
+	Amount          float64 `json:"amount"`
+	AmountFormatted string  `json:"amount_formatted"`
+	Date            string  `json:"date"`
+	Description     string  `json:"description"`
 }
 
 type SummaryResponse struct {
-	TotalAll   float64                 `json:"total_all"`
-	Categories []SummaryDetailResponse `json:"categories"`
+	Currency          string                  `json:"currency"`
+	TotalAll          float64                 `json:"total_all"`
+	TotalAllFormatted string                  `json:"total_all_formatted"`
+	Categories        []SummaryDetailResponse `json:"categories"`
 }
@@ -0,0 +1,37 @@
+package web
+
+import "github.com/ryhnfhrza/Expense-Tracker/internal/types"
+
+type SyncPushRequest struct {
+	Mutations []SyncMutationRequest `json:"mutations" validate:"required,min=1,max=500"`
+	UserId    int                   `json:"user_id" validate:"required,gt=0"`
+}
+
+// SyncMutationRequest is one change a client made while offline. The entity
+// is addressed by id once the server has assigned one, otherwise by the
+// client_id it was created under.
+type SyncMutationRequest struct {
+	ClientId string `json:"client_id" validate:"max=64"`
+	Entity   string `json:"entity" validate:"required,oneof=expense category"`
+	Op       string `json:"op" validate:"required,oneof=create update delete"`
+	Id       int    `json:"id" validate:"omitempty,gt=0"`
+	// BaseVersion is the expense version the client made the change on.
+	BaseVersion int               `json:"base_version" validate:"omitempty,gt=0"`
+	ChangedAt   *types.CustomTime `json:"changed_at" validate:"required"`
+	Expense     *SyncExpenseData  `json:"expense"`
+	Category    *SyncCategoryData `json:"category"`
+}
+
+// SyncExpenseData is the full state of an expense on the client. The
+// category can be one the client created offline, by category_client_id.
+type SyncExpenseData struct {
+	CategoryId       int               `json:"category_id" validate:"omitempty,gt=0"`
+	CategoryClientId string            `json:"category_client_id" validate:"max=64"`
+	Description      string            `json:"description" validate:"max=255"`
+	Amount           float64           `json:"amount" validate:"required"`
+	CreatedAt        *types.CustomTime `json:"created_at"`
+}
+
+type SyncCategoryData struct {
+	Name string `json:"name" validate:"required,max=100"`
+}
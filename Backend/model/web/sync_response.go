@@ -0,0 +1,59 @@
+package web
+
+import "time"
+
+// SyncExpenseChange is the current state of an expense that changed. Expense
+// is nil when Deleted is set.
+type SyncExpenseChange struct {
+	Id       int              `json:"id"`
+	ClientId string           `json:"client_id,omitempty"`
+	Deleted  bool             `json:"deleted"`
+	Expense  *ExpenseResponse `json:"expense,omitempty"`
+}
+
+type SyncCategoryChange struct {
+	Id       int               `json:"id"`
+	ClientId string            `json:"client_id,omitempty"`
+	Deleted  bool              `json:"deleted"`
+	Category *CategoryResponse `json:"category,omitempty"`
+}
+
+type SyncFeedResponse struct {
+	// Snapshot is set when the request had no since token: the lists then
+	// hold every live expense and category instead of changes.
+	Snapshot   bool                 `json:"snapshot"`
+	Expenses   []SyncExpenseChange  `json:"expenses"`
+	Categories []SyncCategoryChange `json:"categories"`
+	NextSince  string               `json:"next_since"`
+	HasMore    bool                 `json:"has_more"`
+}
+
+// SyncConflictResponse reports a mutation that raced a change made on the
+// server. ServerExpense is the server's state the mutation was weighed
+// against; it is nil when the expense had been deleted.
+type SyncConflictResponse struct {
+	Resolution      string           `json:"resolution"`
+	ServerChangedAt time.Time        `json:"server_changed_at"`
+	ServerDeleted   bool             `json:"server_deleted"`
+	ServerExpense   *ExpenseResponse `json:"server_expense,omitempty"`
+}
+
+type SyncMutationResponse struct {
+	Index    int                   `json:"index"`
+	ClientId string                `json:"client_id,omitempty"`
+	Entity   string                `json:"entity"`
+	Op       string                `json:"op"`
+	Id       int                   `json:"id,omitempty"`
+	Status   string                `json:"status"`
+	Error    string                `json:"error,omitempty"`
+	Conflict *SyncConflictResponse `json:"conflict,omitempty"`
+	Expense  *ExpenseResponse      `json:"expense,omitempty"`
+	Category *CategoryResponse     `json:"category,omitempty"`
+}
+
+type SyncPushResponse struct {
+	Applied   int                    `json:"applied"`
+	Conflicts int                    `json:"conflicts"`
+	Failed    int                    `json:"failed"`
+	Results   []SyncMutationResponse `json:"results"`
+}
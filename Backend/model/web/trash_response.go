@@ -0,0 +1,22 @@
+package web
+
+import "time"
+
+type TrashedExpenseResponse struct {
+	ExpenseResponse
+	DeletedAt time.Time `json:"deleted_at"`
+	PurgeAt   time.Time `json:"purge_at"`
+}
+
+type TrashedCategoryResponse struct {
+	CategoryResponse
+	ExpenseCount int       `json:"expense_count"`
+	DeletedAt    time.Time `json:"deleted_at"`
+	PurgeAt      time.Time `json:"purge_at"`
+}
+
+type TrashResponse struct {
+	RetentionDays int                       `json:"retention_days"`
+	Expenses      []TrashedExpenseResponse  `json:"expenses"`
+	Categories    []TrashedCategoryResponse `json:"categories"`
+}
@@ -0,0 +1,7 @@
+package web
+
+type TwoFactorCodeRequest struct {
+	// Code is a TOTP code; disabling also takes a recovery code.
+	Code   string `json:"code" validate:"required,max=32"`
+	UserId int    `json:"user_id" validate:"required,gt=0"`
+}
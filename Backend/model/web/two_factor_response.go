@@ -0,0 +1,12 @@
+package web
+
+type TwoFactorEnrollResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningUri string `json:"provisioning_uri"`
+}
+
+type TwoFactorConfirmResponse struct {
+	// RecoveryCodes each work once in place of a TOTP code. They are not
+	// shown again.
+	RecoveryCodes []string `json:"recovery_codes"`
+}
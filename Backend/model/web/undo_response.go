@@ -0,0 +1,13 @@
+package web
+
+import "time"
+
+type UndoTokenResponse struct {
+	Token     string    `json:"undo_token"`
+	ExpiresAt time.Time `json:"undo_expires_at"`
+}
+
+type UndoResponse struct {
+	Kind     string `json:"kind"`
+	Reverted int    `json:"reverted"`
+}
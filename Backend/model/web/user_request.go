@@ -15,3 +15,52 @@ type UserVerifyRequest struct {
 	Email string `json:"email" validate:"required,email"`
 	Code  string `json:"code" validate:"required,len=6"`
 }
+
+type UserTwoFactorLoginRequest struct {
+	ChallengeToken string `json:"challenge_token" validate:"required"`
+	// Code is a TOTP code or one of the recovery codes.
+	Code string `json:"code" validate:"required,max=32"`
+}
+
+type UserProfileUpdateRequest struct {
+	Username string `json:"username" validate:"required,min=5,max=30"`
+	UserId   int    `json:"user_id" validate:"required,gt=0"`
+}
+
+type UserPasswordChangeRequest struct {
+	// CurrentPassword may only be left out by accounts created through
+	// single sign-on that have no password yet. They give TwoFactorCode
+	// instead when two-factor authentication is on.
+	CurrentPassword string `json:"current_password"`
+	TwoFactorCode   string `json:"two_factor_code"`
+	NewPassword     string `json:"new_password" validate:"required,passComplex"`
+	UserId          int    `json:"user_id" validate:"required,gt=0"`
+}
+
+type UserEmailChangeRequest struct {
+	Email         string `json:"email" validate:"required,email"`
+	Password      string `json:"password"`
+	TwoFactorCode string `json:"two_factor_code"`
+	UserId        int    `json:"user_id" validate:"required,gt=0"`
+}
+
+type UserEmailVerifyRequest struct {
+	Code   string `json:"code" validate:"required,len=6"`
+	UserId int    `json:"user_id" validate:"required,gt=0"`
+}
+
+type UserDeleteRequest struct {
+	Password      string `json:"password"`
+	TwoFactorCode string `json:"two_factor_code"`
+	UserId        int    `json:"user_id" validate:"required,gt=0"`
+}
+
+// UserPreferencesUpdateRequest changes only the preferences it sets.
+type UserPreferencesUpdateRequest struct {
+	Timezone   *string `json:"timezone" validate:"omitempty,timezone"`
+	Locale     *string `json:"locale" validate:"omitempty,bcp47_language_tag"`
+	Currency   *string `json:"currency" validate:"omitempty,iso4217"`
+	WeekStart  *string `json:"week_start" validate:"omitempty,oneof=saturday sunday monday"`
+	DateFormat *string `json:"date_format" validate:"omitempty,oneof=YYYY-MM-DD DD/MM/YYYY MM/DD/YYYY DD.MM.YYYY DD-MM-YYYY"`
+	UserId     int     `json:"user_id" validate:"required,gt=0"`
+}
@@ -1,5 +1,7 @@
 package web
 
+import "time"
+
 type UserResponse struct {
 	Username string `json:"username"`
 	Email    string `json:"email"`
@@ -9,5 +11,27 @@ type UserLoginResponse struct {
 	Id       int    `json:"id"`
 	Username string `json:"username"`
 	Email    string `json:"email"`
-	Token    string `json:"token"`
+	Token    string `json:"token,omitempty"`
+	// With two-factor authentication on, Token is left out and
+	// ChallengeToken is to be traded for it at /login/2fa.
+	TwoFactorRequired bool   `json:"two_factor_required,omitempty"`
+	ChallengeToken    string `json:"challenge_token,omitempty"`
+}
+
+type UserProfileResponse struct {
+	Id               int    `json:"id"`
+	Username         string `json:"username"`
+	Email            string `json:"email"`
+	HasPassword      bool   `json:"has_password"`
+	TwoFactorEnabled bool   `json:"two_factor_enabled"`
+	// DeletionScheduledAt is set while the account waits to be deleted.
+	DeletionScheduledAt *time.Time `json:"deletion_scheduled_at,omitempty"`
+}
+
+type UserPreferencesResponse struct {
+	Timezone   string `json:"timezone"`
+	Locale     string `json:"locale"`
+	Currency   string `json:"currency"`
+	WeekStart  string `json:"week_start"`
+	DateFormat string `json:"date_format"`
 }
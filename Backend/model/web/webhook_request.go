@@ -0,0 +1,7 @@
+package web
+
+type WebhookCreateRequest struct {
+	Url    string   `json:"url" validate:"required,url,max=2048"`
+	Events []string `json:"events" validate:"required,min=1,dive,oneof=expense.created expense.updated expense.deleted"`
+	UserId int      `json:"user_id" validate:"required,gt=0"`
+}
@@ -0,0 +1,44 @@
+package web
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type WebhookResponse struct {
+	Id     int      `json:"id"`
+	Url    string   `json:"url"`
+	Events []string `json:"events"`
+	// Secret signs the deliveries. It is only returned when the webhook is
+	// created.
+	Secret    string    `json:"secret,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type WebhookDeliveryResponse struct {
+	Id        int64  `json:"id"`
+	WebhookId int    `json:"webhook_id"`
+	EventId   int64  `json:"event_id"`
+	EventType string `json:"event_type"`
+	Status    string `json:"status"`
+	Attempts  int    `json:"attempts"`
+	// NextAttemptAt is only set while the delivery is pending.
+	NextAttemptAt  *time.Time `json:"next_attempt_at,omitempty"`
+	LastStatusCode *int       `json:"last_status_code,omitempty"`
+	LastError      string     `json:"last_error,omitempty"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+type WebhookDeliveryListResponse struct {
+	Deliveries   []WebhookDeliveryResponse `json:"deliveries"`
+	NextBeforeId int64                     `json:"next_before_id,omitempty"`
+}
+
+// WebhookEventPayload is the body POSTed to a webhook.
+type WebhookEventPayload struct {
+	Id        string          `json:"id"`
+	Type      string          `json:"type"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
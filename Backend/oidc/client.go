@@ -0,0 +1,294 @@
+// Package oidc is a small OpenID Connect relying party: discovery, the
+// authorization code flow with PKCE and ID token verification against the
+// provider's JWKS. It only supports RS256 signed ID tokens, which every
+// mainstream provider issues.
+package oidc
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type Config struct {
+	Issuer       string
+	ClientId     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+}
+
+// Claims are the ID token claims the app uses.
+type Claims struct {
+	Subject           string `json:"sub"`
+	Email             string `json:"email"`
+	EmailVerified     bool   `json:"email_verified"`
+	Name              string `json:"name"`
+	PreferredUsername string `json:"preferred_username"`
+	Nonce             string `json:"nonce"`
+	jwt.RegisteredClaims
+}
+
+// VerifiedEmail is the email address when the provider has verified it,
+// and empty otherwise. Only a verified address may be used to link the
+// provider account to an existing user.
+func (claims *Claims) VerifiedEmail() string {
+	if !claims.EmailVerified {
+		return ""
+	}
+	return claims.Email
+}
+
+type discovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JwksURI               string `json:"jwks_uri"`
+}
+
+// Client talks to one provider. Discovery and the signing keys are fetched
+// on first use and cached, so the app starts even while the provider is
+// unreachable.
+type Client struct {
+	config Config
+	http   *http.Client
+
+	mu        sync.Mutex
+	discovery *discovery
+	keys      map[string]*rsa.PublicKey
+	keysAt    time.Time
+}
+
+// keysMinRefresh limits how often an unknown key id makes the client fetch
+// the JWKS again.
+const keysMinRefresh = time.Minute
+
+func NewClient(config Config, httpClient *http.Client) *Client {
+	if len(config.Scopes) == 0 {
+		config.Scopes = []string{"openid", "email", "profile"}
+	}
+	return &Client{config: config, http: httpClient}
+}
+
+// Issuer identifies the provider; with a subject it names an account there.
+func (client *Client) Issuer() string {
+	return client.config.Issuer
+}
+
+// AuthCodeURL is where the user is sent to sign in. challenge is the S256
+// PKCE challenge of the verifier later passed to Exchange.
+func (client *Client) AuthCodeURL(ctx context.Context, state, nonce, challenge string) (string, error) {
+	provider, err := client.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	query := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {client.config.ClientId},
+		"redirect_uri":          {client.config.RedirectURL},
+		"scope":                 {strings.Join(client.config.Scopes, " ")},
+		"state":                 {state},
+		"nonce":                 {nonce},
+		"code_challenge":        {challenge},
+		"code_challenge_method": {"S256"},
+	}
+
+	separator := "?"
+	if strings.Contains(provider.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+	return provider.AuthorizationEndpoint + separator + query.Encode(), nil
+}
+
+// Exchange trades an authorization code for tokens and returns the
+// verified ID token claims. nonce must be the one sent with AuthCodeURL.
+func (client *Client) Exchange(ctx context.Context, code, verifier, nonce string) (*Claims, error) {
+	provider, err := client.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {client.config.RedirectURL},
+		"code_verifier": {verifier},
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, provider.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("Accept", "application/json")
+	request.SetBasicAuth(url.QueryEscape(client.config.ClientId), url.QueryEscape(client.config.ClientSecret))
+
+	response, err := client.http.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("token request failed: %w", err)
+	}
+	defer response.Body.Close()
+
+	var token struct {
+		IdToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.NewDecoder(io.LimitReader(response.Body, 1<<20)).Decode(&token); err != nil {
+		return nil, fmt.Errorf("failed to decode token response (status=%d): %w", response.StatusCode, err)
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("token endpoint answered %d: %s %s", response.StatusCode, token.Error, token.ErrorDescription)
+	}
+	if token.IdToken == "" {
+		return nil, errors.New("token response has no id_token")
+	}
+
+	return client.verify(ctx, provider, token.IdToken, nonce)
+}
+
+func (client *Client) verify(ctx context.Context, provider *discovery, rawToken, nonce string) (*Claims, error) {
+	parser := jwt.NewParser(
+		jwt.WithValidMethods([]string{"RS256"}),
+		jwt.WithIssuer(provider.Issuer),
+		jwt.WithAudience(client.config.ClientId),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(30*time.Second),
+	)
+
+	claims := &Claims{}
+	_, err := parser.ParseWithClaims(rawToken, claims, func(token *jwt.Token) (any, error) {
+		kid, _ := token.Header["kid"].(string)
+		return client.key(ctx, provider, kid)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid id token: %w", err)
+	}
+	if claims.Nonce != nonce {
+		return nil, errors.New("invalid id token: nonce mismatch")
+	}
+	if claims.Subject == "" {
+		return nil, errors.New("invalid id token: no subject")
+	}
+
+	return claims, nil
+}
+
+func (client *Client) discover(ctx context.Context) (*discovery, error) {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+
+	if client.discovery != nil {
+		return client.discovery, nil
+	}
+
+	var provider discovery
+	wellKnown := strings.TrimSuffix(client.config.Issuer, "/") + "/.well-known/openid-configuration"
+	if err := client.getJSON(ctx, wellKnown, &provider); err != nil {
+		return nil, fmt.Errorf("oidc discovery failed: %w", err)
+	}
+	if provider.Issuer != client.config.Issuer {
+		return nil, fmt.Errorf("oidc discovery failed: issuer %q does not match %q", provider.Issuer, client.config.Issuer)
+	}
+	if provider.AuthorizationEndpoint == "" || provider.TokenEndpoint == "" || provider.JwksURI == "" {
+		return nil, errors.New("oidc discovery failed: missing endpoints")
+	}
+
+	client.discovery = &provider
+	return client.discovery, nil
+}
+
+// key returns the signing key with the given id, fetching the JWKS again
+// when the provider has rotated to a key not seen yet.
+func (client *Client) key(ctx context.Context, provider *discovery, kid string) (*rsa.PublicKey, error) {
+	client.mu.Lock()
+	defer client.mu.Unlock()
+
+	if key := client.lookupKey(kid); key != nil {
+		return key, nil
+	}
+	if time.Since(client.keysAt) < keysMinRefresh {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+
+	var set struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			Use string `json:"use"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}
+	if err := client.getJSON(ctx, provider.JwksURI, &set); err != nil {
+		return nil, fmt.Errorf("failed to fetch jwks: %w", err)
+	}
+
+	keys := map[string]*rsa.PublicKey{}
+	for _, jwk := range set.Keys {
+		if jwk.Kty != "RSA" || (jwk.Use != "" && jwk.Use != "sig") {
+			continue
+		}
+		n, errN := base64.RawURLEncoding.DecodeString(jwk.N)
+		e, errE := base64.RawURLEncoding.DecodeString(jwk.E)
+		if errN != nil || errE != nil || len(e) == 0 || len(e) > 4 {
+			continue
+		}
+		keys[jwk.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+	client.keys = keys
+	client.keysAt = time.Now()
+
+	if key := client.lookupKey(kid); key != nil {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+// lookupKey finds kid among the cached keys. A token without a kid is
+// accepted when the provider publishes a single key.
+func (client *Client) lookupKey(kid string) *rsa.PublicKey {
+	if key, ok := client.keys[kid]; ok {
+		return key
+	}
+	if kid == "" && len(client.keys) == 1 {
+		for _, key := range client.keys {
+			return key
+		}
+	}
+	return nil
+}
+
+func (client *Client) getJSON(ctx context.Context, target string, out any) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return err
+	}
+	request.Header.Set("Accept", "application/json")
+
+	response, err := client.http.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s answered %d", target, response.StatusCode)
+	}
+	return json.NewDecoder(io.LimitReader(response.Body, 1<<20)).Decode(out)
+}
@@ -0,0 +1,145 @@
+package oidc_test
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/ryhnfhrza/Expense-Tracker/oidc"
+	"github.com/ryhnfhrza/Expense-Tracker/oidc/oidctest"
+)
+
+const (
+	clientId     = "expense-tracker"
+	clientSecret = "s3cret"
+	redirectURL  = "http://app.test/sso/callback"
+)
+
+var verifiedUser = oidctest.User{
+	Subject:           "user-1",
+	Email:             "jane@example.com",
+	EmailVerified:     true,
+	Name:              "Jane Doe",
+	PreferredUsername: "jane",
+}
+
+func newClient(t *testing.T, user oidctest.User) (*oidctest.Server, *oidc.Client) {
+	t.Helper()
+	server := oidctest.NewServer(clientId, clientSecret, user)
+	t.Cleanup(server.Close)
+
+	client := oidc.NewClient(oidc.Config{
+		Issuer:       server.Issuer,
+		ClientId:     clientId,
+		ClientSecret: clientSecret,
+		RedirectURL:  redirectURL,
+	}, server.Client())
+	return server, client
+}
+
+// signIn follows AuthCodeURL to the provider and returns the code it
+// redirects back with.
+func signIn(t *testing.T, server *oidctest.Server, client *oidc.Client, state, nonce, verifier string) string {
+	t.Helper()
+	authorizationUrl, err := client.AuthCodeURL(context.Background(), state, nonce, oidc.Challenge(verifier))
+	if err != nil {
+		t.Fatalf("AuthCodeURL: %v", err)
+	}
+
+	httpClient := server.Client()
+	httpClient.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	response, err := httpClient.Get(authorizationUrl)
+	if err != nil {
+		t.Fatalf("authorize: %v", err)
+	}
+	response.Body.Close()
+	if response.StatusCode != http.StatusFound {
+		t.Fatalf("authorize answered %d, want %d", response.StatusCode, http.StatusFound)
+	}
+
+	location, err := url.Parse(response.Header.Get("Location"))
+	if err != nil {
+		t.Fatalf("authorize redirect: %v", err)
+	}
+	if !strings.HasPrefix(location.String(), redirectURL) {
+		t.Fatalf("redirected to %q, want %q", location, redirectURL)
+	}
+	if got := location.Query().Get("state"); got != state {
+		t.Fatalf("state = %q, want %q", got, state)
+	}
+	return location.Query().Get("code")
+}
+
+func TestExchange(t *testing.T) {
+	server, client := newClient(t, verifiedUser)
+
+	code := signIn(t, server, client, "state-1", "nonce-1", "verifier-1")
+	claims, err := client.Exchange(context.Background(), code, "verifier-1", "nonce-1")
+	if err != nil {
+		t.Fatalf("Exchange: %v", err)
+	}
+
+	if claims.Subject != verifiedUser.Subject || claims.Name != verifiedUser.Name || claims.PreferredUsername != verifiedUser.PreferredUsername {
+		t.Errorf("claims = %+v, want the user %+v", claims, verifiedUser)
+	}
+	if got := claims.VerifiedEmail(); got != verifiedUser.Email {
+		t.Errorf("VerifiedEmail() = %q, want %q", got, verifiedUser.Email)
+	}
+	if client.Issuer() != server.Issuer {
+		t.Errorf("Issuer() = %q, want %q", client.Issuer(), server.Issuer)
+	}
+}
+
+func TestExchangeRejectsNonceMismatch(t *testing.T) {
+	server, client := newClient(t, verifiedUser)
+
+	code := signIn(t, server, client, "state-1", "nonce-1", "verifier-1")
+	_, err := client.Exchange(context.Background(), code, "verifier-1", "another-nonce")
+	if err == nil || !strings.Contains(err.Error(), "nonce mismatch") {
+		t.Fatalf("Exchange error = %v, want a nonce mismatch", err)
+	}
+}
+
+func TestExchangeRejectsWrongVerifier(t *testing.T) {
+	server, client := newClient(t, verifiedUser)
+
+	code := signIn(t, server, client, "state-1", "nonce-1", "verifier-1")
+	_, err := client.Exchange(context.Background(), code, "another-verifier", "nonce-1")
+	if err == nil || !strings.Contains(err.Error(), "code_verifier does not match") {
+		t.Fatalf("Exchange error = %v, want the verifier refused", err)
+	}
+}
+
+func TestExchangeRejectsWrongAudience(t *testing.T) {
+	server, client := newClient(t, verifiedUser)
+	server.Audience = "another-client"
+
+	code := signIn(t, server, client, "state-1", "nonce-1", "verifier-1")
+	_, err := client.Exchange(context.Background(), code, "verifier-1", "nonce-1")
+	if err == nil || !strings.Contains(err.Error(), "invalid audience") {
+		t.Fatalf("Exchange error = %v, want the audience refused", err)
+	}
+}
+
+func TestUnverifiedEmailIsNotLinkable(t *testing.T) {
+	unverified := verifiedUser
+	unverified.EmailVerified = false
+	server, client := newClient(t, unverified)
+
+	code := signIn(t, server, client, "state-1", "nonce-1", "verifier-1")
+	claims, err := client.Exchange(context.Background(), code, "verifier-1", "nonce-1")
+	if err != nil {
+		t.Fatalf("Exchange: %v", err)
+	}
+
+	if claims.Email != unverified.Email {
+		t.Errorf("Email = %q, want %q", claims.Email, unverified.Email)
+	}
+	if got := claims.VerifiedEmail(); got != "" {
+		t.Errorf("VerifiedEmail() = %q, want none so the account is not linked", got)
+	}
+}
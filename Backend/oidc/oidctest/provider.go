@@ -0,0 +1,256 @@
+// Package oidctest is a mock OpenID Connect provider for tests and local
+// development. It implements discovery, a JWKS, an authorization endpoint
+// that signs in a configurable user without a login page, and a token
+// endpoint that checks client credentials and the PKCE verifier.
+package oidctest
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const keyId = "oidctest"
+
+// User is the identity the provider signs in.
+type User struct {
+	Subject           string
+	Email             string
+	EmailVerified     bool
+	Name              string
+	PreferredUsername string
+}
+
+type authCode struct {
+	user        User
+	clientId    string
+	redirectURI string
+	challenge   string
+	nonce       string
+	expiresAt   time.Time
+}
+
+type Provider struct {
+	Issuer       string
+	ClientId     string
+	ClientSecret string
+	// Audience, when set, replaces the client id as the ID token audience,
+	// to test that relying parties check it.
+	Audience string
+
+	key   *rsa.PrivateKey
+	mu    sync.Mutex
+	user  User
+	codes map[string]authCode
+	mux   *http.ServeMux
+}
+
+// NewProvider returns a provider for issuer that accepts one client. It
+// signs in user until SetUser is called.
+func NewProvider(issuer, clientId, clientSecret string, user User) *Provider {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic(err)
+	}
+
+	provider := &Provider{
+		Issuer:       issuer,
+		ClientId:     clientId,
+		ClientSecret: clientSecret,
+		key:          key,
+		user:         user,
+		codes:        map[string]authCode{},
+		mux:          http.NewServeMux(),
+	}
+	provider.mux.HandleFunc("GET /.well-known/openid-configuration", provider.discovery)
+	provider.mux.HandleFunc("GET /jwks", provider.jwks)
+	provider.mux.HandleFunc("GET /authorize", provider.authorize)
+	provider.mux.HandleFunc("POST /token", provider.token)
+
+	return provider
+}
+
+// SetUser changes who signs in at the next authorization request.
+func (provider *Provider) SetUser(user User) {
+	provider.mu.Lock()
+	defer provider.mu.Unlock()
+	provider.user = user
+}
+
+func (provider *Provider) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	provider.mux.ServeHTTP(writer, request)
+}
+
+// Server is a Provider listening on a local httptest server.
+type Server struct {
+	*Provider
+	*httptest.Server
+}
+
+// NewServer starts a provider whose issuer is the server's URL. Close it
+// when done.
+func NewServer(clientId, clientSecret string, user User) *Server {
+	server := &Server{}
+	server.Server = httptest.NewUnstartedServer(nil)
+	server.Server.Start()
+	server.Provider = NewProvider(server.Server.URL, clientId, clientSecret, user)
+	server.Server.Config.Handler = server.Provider
+
+	return server
+}
+
+func (provider *Provider) discovery(writer http.ResponseWriter, request *http.Request) {
+	writeJSON(writer, http.StatusOK, map[string]any{
+		"issuer":                                provider.Issuer,
+		"authorization_endpoint":                provider.Issuer + "/authorize",
+		"token_endpoint":                        provider.Issuer + "/token",
+		"jwks_uri":                              provider.Issuer + "/jwks",
+		"response_types_supported":              []string{"code"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+		"code_challenge_methods_supported":      []string{"S256"},
+	})
+}
+
+func (provider *Provider) jwks(writer http.ResponseWriter, request *http.Request) {
+	public := provider.key.PublicKey
+	writeJSON(writer, http.StatusOK, map[string]any{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": keyId,
+			"use": "sig",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(public.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes()),
+		}},
+	})
+}
+
+func (provider *Provider) authorize(writer http.ResponseWriter, request *http.Request) {
+	query := request.URL.Query()
+	redirectURI, err := url.Parse(query.Get("redirect_uri"))
+	if err != nil || redirectURI.String() == "" {
+		http.Error(writer, "invalid redirect_uri", http.StatusBadRequest)
+		return
+	}
+	if query.Get("client_id") != provider.ClientId {
+		http.Error(writer, "unknown client_id", http.StatusBadRequest)
+		return
+	}
+	if query.Get("response_type") != "code" || query.Get("code_challenge_method") != "S256" || query.Get("code_challenge") == "" {
+		http.Error(writer, "expected response_type=code with an S256 code_challenge", http.StatusBadRequest)
+		return
+	}
+
+	code := randomString()
+	provider.mu.Lock()
+	provider.codes[code] = authCode{
+		user:        provider.user,
+		clientId:    provider.ClientId,
+		redirectURI: redirectURI.String(),
+		challenge:   query.Get("code_challenge"),
+		nonce:       query.Get("nonce"),
+		expiresAt:   time.Now().Add(time.Minute),
+	}
+	provider.mu.Unlock()
+
+	values := redirectURI.Query()
+	values.Set("code", code)
+	values.Set("state", query.Get("state"))
+	redirectURI.RawQuery = values.Encode()
+
+	http.Redirect(writer, request, redirectURI.String(), http.StatusFound)
+}
+
+func (provider *Provider) token(writer http.ResponseWriter, request *http.Request) {
+	clientId, clientSecret, ok := request.BasicAuth()
+	if ok {
+		clientId, _ = url.QueryUnescape(clientId)
+		clientSecret, _ = url.QueryUnescape(clientSecret)
+	} else {
+		clientId, clientSecret = request.PostFormValue("client_id"), request.PostFormValue("client_secret")
+	}
+	if clientId != provider.ClientId || clientSecret != provider.ClientSecret {
+		writeJSON(writer, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+		return
+	}
+	if request.PostFormValue("grant_type") != "authorization_code" {
+		writeJSON(writer, http.StatusBadRequest, map[string]string{"error": "unsupported_grant_type"})
+		return
+	}
+
+	provider.mu.Lock()
+	code, ok := provider.codes[request.PostFormValue("code")]
+	delete(provider.codes, request.PostFormValue("code"))
+	provider.mu.Unlock()
+
+	sum := sha256.Sum256([]byte(request.PostFormValue("code_verifier")))
+	switch {
+	case !ok || time.Now().After(code.expiresAt) || code.clientId != clientId:
+		writeJSON(writer, http.StatusBadRequest, map[string]string{"error": "invalid_grant", "error_description": "unknown or expired code"})
+		return
+	case request.PostFormValue("redirect_uri") != code.redirectURI:
+		writeJSON(writer, http.StatusBadRequest, map[string]string{"error": "invalid_grant", "error_description": "redirect_uri mismatch"})
+		return
+	case base64.RawURLEncoding.EncodeToString(sum[:]) != code.challenge:
+		writeJSON(writer, http.StatusBadRequest, map[string]string{"error": "invalid_grant", "error_description": "code_verifier does not match"})
+		return
+	}
+
+	audience := clientId
+	if provider.Audience != "" {
+		audience = provider.Audience
+	}
+
+	now := time.Now()
+	idToken := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":                provider.Issuer,
+		"aud":                audience,
+		"sub":                code.user.Subject,
+		"email":              code.user.Email,
+		"email_verified":     code.user.EmailVerified,
+		"name":               code.user.Name,
+		"preferred_username": code.user.PreferredUsername,
+		"nonce":              code.nonce,
+		"iat":                now.Unix(),
+		"exp":                now.Add(time.Hour).Unix(),
+	})
+	idToken.Header["kid"] = keyId
+	signed, err := idToken.SignedString(provider.key)
+	if err != nil {
+		writeJSON(writer, http.StatusInternalServerError, map[string]string{"error": "server_error"})
+		return
+	}
+
+	writeJSON(writer, http.StatusOK, map[string]any{
+		"access_token": randomString(),
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+		"id_token":     signed,
+	})
+}
+
+func randomString() string {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf)
+}
+
+func writeJSON(writer http.ResponseWriter, status int, body any) {
+	writer.Header().Set("Content-Type", "application/json")
+	writer.Header().Set("Cache-Control", "no-store")
+	writer.WriteHeader(status)
+	_ = json.NewEncoder(writer).Encode(body)
+}
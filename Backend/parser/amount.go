@@ -0,0 +1,132 @@
+package parser
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	amountPattern     = regexp.MustCompile(`^(?:rp\.?|idr)?(\d+(?:[.,]\d+)*)([a-z]*)$`)
+	currencyTokens    = map[string]bool{"rp": true, "rp.": true, "idr": true}
+	amountMultipliers = map[string]float64{
+		"k":        1e3,
+		"rb":       1e3,
+		"ribu":     1e3,
+		"thousand": 1e3,
+		"jt":       1e6,
+		"juta":     1e6,
+		"m":        1e6,
+		"mio":      1e6,
+		"million":  1e6,
+	}
+)
+
+type amountCandidate struct {
+	value float64
+	score int
+	from  int
+	to    int
+}
+
+// findAmount scans the tokens for the most likely amount. Numbers written
+// with a currency prefix ("Rp 25.000") or a unit suffix ("25k", "1,5 jt")
+// win over bare numbers; among bare numbers the largest one wins so that
+// "3 buku 45000" resolves to 45000.
+func findAmount(tokens []string, consumed []bool) (amountCandidate, bool) {
+	var (
+		best  amountCandidate
+		found bool
+	)
+
+	for i := 0; i < len(tokens); i++ {
+		if consumed[i] {
+			continue
+		}
+
+		from, score := i, 0
+		tok := tokens[i]
+		if currencyTokens[tok] && i+1 < len(tokens) && !consumed[i+1] {
+			i++
+			tok = tokens[i]
+			score += 2
+		}
+
+		match := amountPattern.FindStringSubmatch(tok)
+		if match == nil {
+			i = from
+			continue
+		}
+		if strings.HasPrefix(tok, "rp") || strings.HasPrefix(tok, "idr") {
+			score += 2
+		}
+
+		to := i
+		unit := match[2]
+		if unit == "" && i+1 < len(tokens) && !consumed[i+1] {
+			if _, ok := amountMultipliers[tokens[i+1]]; ok {
+				unit = tokens[i+1]
+				to = i + 1
+			}
+		}
+
+		multiplier := 1.0
+		if unit != "" {
+			m, ok := amountMultipliers[unit]
+			if !ok {
+				i = from
+				continue
+			}
+			multiplier = m
+			score += 2
+		}
+
+		value, ok := parseNumber(match[1], unit != "")
+		if !ok || value <= 0 {
+			i = from
+			continue
+		}
+
+		candidate := amountCandidate{value: value * multiplier, score: score, from: from, to: to}
+		if !found || candidate.score > best.score || (candidate.score == best.score && candidate.value > best.value) {
+			best, found = candidate, true
+		}
+		i = to
+	}
+
+	return best, found
+}
+
+// parseNumber understands both Indonesian ("25.000,50") and English
+// ("25,000.50") separators. A single separator before a unit suffix is a
+// decimal point ("1.5jt", "2,5rb").
+func parseNumber(raw string, hasUnit bool) (float64, bool) {
+	groups := strings.FieldsFunc(raw, func(r rune) bool { return r == '.' || r == ',' })
+	if len(groups) == 1 {
+		value, err := strconv.ParseFloat(raw, 64)
+		return value, err == nil
+	}
+
+	last := groups[len(groups)-1]
+	thousands := len(groups[0]) <= 3
+	for _, g := range groups[1:] {
+		if len(g) != 3 {
+			thousands = false
+		}
+	}
+
+	var number string
+	switch {
+	case hasUnit && len(groups) == 2:
+		number = groups[0] + "." + last
+	case thousands:
+		number = strings.Join(groups, "")
+	case len(last) <= 2:
+		number = strings.Join(groups[:len(groups)-1], "") + "." + last
+	default:
+		return 0, false
+	}
+
+	value, err := strconv.ParseFloat(number, 64)
+	return value, err == nil
+}
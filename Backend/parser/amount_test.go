@@ -0,0 +1,73 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+// tokenize lowercases the text the way ParseExpenseText does before the
+// matchers see it.
+func tokenize(text string) []string {
+	return strings.Fields(strings.ToLower(text))
+}
+
+func TestFindAmount(t *testing.T) {
+	tests := []struct {
+		text     string
+		want     float64
+		from, to int
+	}{
+		{"25000", 25000, 0, 0},
+		{"25k", 25000, 0, 0},
+		{"25 k", 25000, 0, 1},
+		{"25rb", 25000, 0, 0},
+		{"25 ribu", 25000, 0, 1},
+		{"2,5rb", 2500, 0, 0},
+		{"1.5jt", 1500000, 0, 0},
+		{"1,5 jt", 1500000, 0, 1},
+		{"2m", 2000000, 0, 0},
+		{"3 mio", 3000000, 0, 1},
+		{"Rp 25.000", 25000, 0, 1},
+		{"Rp25.000", 25000, 0, 0},
+		{"Rp. 12.500", 12500, 0, 1},
+		{"IDR 1,250,000", 1250000, 0, 1},
+		{"25.000,50", 25000.5, 0, 0},
+		{"25,000.50", 25000.5, 0, 0},
+		{"12.5", 12.5, 0, 0},
+		{"3 buku 45000", 45000, 2, 2},
+		{"Rp 5000 for 3 x 20000", 5000, 0, 1},
+		{"2 porsi 10rb 15000", 10000, 2, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			tokens := tokenize(tt.text)
+			got, ok := findAmount(tokens, make([]bool, len(tokens)))
+			if !ok {
+				t.Fatalf("findAmount(%q) found nothing, want %v", tt.text, tt.want)
+			}
+			if got.value != tt.want || got.from != tt.from || got.to != tt.to {
+				t.Errorf("findAmount(%q) = %v at [%d,%d], want %v at [%d,%d]", tt.text, got.value, got.from, got.to, tt.want, tt.from, tt.to)
+			}
+		})
+	}
+}
+
+func TestFindAmountNone(t *testing.T) {
+	for _, text := range []string{"makan siang", "0", "25kg", "rp", "1.2345"} {
+		tokens := tokenize(text)
+		if got, ok := findAmount(tokens, make([]bool, len(tokens))); ok {
+			t.Errorf("findAmount(%q) = %v, want nothing", text, got.value)
+		}
+	}
+}
+
+func TestFindAmountSkipsConsumedTokens(t *testing.T) {
+	tokens := tokenize("2024-01-05 kopi 20k")
+	consumed := []bool{true, false, false}
+
+	got, ok := findAmount(tokens, consumed)
+	if !ok || got.value != 20000 {
+		t.Fatalf("findAmount = (%v, %t), want 20000", got.value, ok)
+	}
+}
@@ -0,0 +1,122 @@
+package parser
+
+import (
+	"strings"
+
+	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
+)
+
+// categoryConcepts maps common category names to words that usually show up
+// in descriptions of that kind of spending, in Indonesian and English.
+var categoryConcepts = []struct {
+	names    []string
+	keywords []string
+}{
+	{
+		names:    []string{"food", "makan", "makanan", "kuliner", "jajan", "minum", "minuman", "drink", "snack"},
+		keywords: []string{"makan", "nasi", "ayam", "bakso", "mie", "soto", "sate", "kopi", "coffee", "teh", "tea", "lunch", "dinner", "breakfast", "sarapan", "siang", "malam", "jajan", "snack", "gofood", "grabfood", "shopeefood", "warteg", "resto", "restaurant", "cafe", "burger", "pizza"},
+	},
+	{
+		names:    []string{"transport", "transportasi", "travel", "perjalanan", "kendaraan"},
+		keywords: []string{"grab", "gojek", "gocar", "goride", "ojek", "ojol", "taxi", "taksi", "bus", "busway", "transjakarta", "krl", "mrt", "lrt", "kereta", "train", "bensin", "pertalite", "pertamax", "fuel", "parkir", "parking", "tol", "toll", "uber"},
+	},
+	{
+		names:    []string{"shopping", "belanja"},
+		keywords: []string{"beli", "belanja", "shopee", "tokopedia", "lazada", "baju", "sepatu", "celana", "shopping", "indomaret", "alfamart", "supermarket"},
+	},
+	{
+		names:    []string{"bill", "bills", "tagihan", "utilities", "utility"},
+		keywords: []string{"listrik", "pln", "token", "air", "pdam", "internet", "wifi", "indihome", "pulsa", "kuota", "tagihan", "bill", "electricity", "water"},
+	},
+	{
+		names:    []string{"entertainment", "hiburan", "fun"},
+		keywords: []string{"nonton", "bioskop", "movie", "cinema", "netflix", "spotify", "game", "steam", "konser", "concert", "karaoke"},
+	},
+	{
+		names:    []string{"health", "kesehatan", "medical"},
+		keywords: []string{"obat", "apotek", "dokter", "doctor", "klinik", "clinic", "rumah", "sakit", "hospital", "vitamin", "medicine", "pharmacy"},
+	},
+	{
+		names:    []string{"rent", "sewa", "kos", "kost", "housing"},
+		keywords: []string{"sewa", "kos", "kost", "kontrakan", "rent", "apartemen"},
+	},
+}
+
+type categoryMatch struct {
+	category domain.Category
+	keyword  string
+	score    int
+}
+
+// matchCategory scores every category against the tokens. A category name
+// written in the text scores highest, then a single word of the name, then a
+// keyword of a known concept the category name belongs to. No match returns
+// false instead of guessing.
+func matchCategory(tokens []string, categories []domain.Category) (categoryMatch, bool) {
+	var (
+		best  categoryMatch
+		found bool
+	)
+
+	text := " " + strings.Join(tokens, " ") + " "
+	words := map[string]bool{}
+	for _, tok := range tokens {
+		words[strings.Trim(tok, ".,;:!?")] = true
+	}
+
+	for _, category := range categories {
+		name := strings.ToLower(strings.TrimSpace(category.Name))
+		if name == "" {
+			continue
+		}
+
+		match := categoryMatch{category: category}
+		if strings.Contains(text, " "+name+" ") {
+			match.score, match.keyword = 3, name
+		}
+
+		nameWords := strings.Fields(name)
+		if match.score == 0 {
+			for _, w := range nameWords {
+				if len(w) >= 3 && words[w] {
+					match.score, match.keyword = 2, w
+					break
+				}
+			}
+		}
+
+		if match.score == 0 {
+			for _, concept := range categoryConcepts {
+				if !anyIn(concept.names, nameWords) {
+					continue
+				}
+				for _, keyword := range concept.keywords {
+					if words[keyword] {
+						match.score, match.keyword = 1, keyword
+						break
+					}
+				}
+				if match.score > 0 {
+					break
+				}
+			}
+		}
+
+		if match.score > best.score {
+			best, found = match, true
+		}
+	}
+
+	return best, found
+}
+
+func anyIn(candidates, words []string) bool {
+	for _, c := range candidates {
+		for _, w := range words {
+			if c == w {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,187 @@
+package parser
+
+import (
+	"strconv"
+	"time"
+)
+
+var (
+	relativeDays = []struct {
+		phrase []string
+		offset int
+	}{
+		{[]string{"kemarin", "lusa"}, -2},
+		{[]string{"hari", "ini"}, 0},
+		{[]string{"today"}, 0},
+		{[]string{"tadi"}, 0},
+		{[]string{"kemarin"}, -1},
+		{[]string{"kmrn"}, -1},
+		{[]string{"yesterday"}, -1},
+		{[]string{"besok"}, 1},
+		{[]string{"tomorrow"}, 1},
+		{[]string{"lusa"}, 2},
+		{[]string{"minggu", "lalu"}, -7},
+		{[]string{"minggu", "kemarin"}, -7},
+		{[]string{"last", "week"}, -7},
+	}
+
+	weekdays = map[string]time.Weekday{
+		"sunday":    time.Sunday,
+		"monday":    time.Monday,
+		"tuesday":   time.Tuesday,
+		"wednesday": time.Wednesday,
+		"thursday":  time.Thursday,
+		"friday":    time.Friday,
+		"saturday":  time.Saturday,
+		"minggu":    time.Sunday,
+		"ahad":      time.Sunday,
+		"senin":     time.Monday,
+		"selasa":    time.Tuesday,
+		"rabu":      time.Wednesday,
+		"kamis":     time.Thursday,
+		"jumat":     time.Friday,
+		"jum'at":    time.Friday,
+		"sabtu":     time.Saturday,
+	}
+
+	agoWords = map[string]bool{"ago": true, "lalu": true}
+
+	dateLayouts  = []string{"2006-01-02", "2/1/2006", "2-1-2006"}
+	shortLayouts = []string{"2/1"}
+)
+
+type dateMatch struct {
+	date time.Time
+	from int
+	to   int
+}
+
+// findDate looks for the first date phrase in the tokens. Supported forms are
+// relative words ("kemarin", "yesterday"), "<n> hari lalu" / "<n> days ago",
+// weekdays ("last friday", "jumat lalu", "senin") and explicit dates
+// (2024-12-03, 03/12/2024, 03/12).
+func findDate(tokens []string, now time.Time) (dateMatch, bool) {
+	for i := range tokens {
+		if m, ok := matchRelativeDay(tokens, i, now); ok {
+			return m, true
+		}
+		if m, ok := matchDaysAgo(tokens, i, now); ok {
+			return m, true
+		}
+		if m, ok := matchWeekday(tokens, i, now); ok {
+			return m, true
+		}
+		if m, ok := matchExplicitDate(tokens, i, now); ok {
+			return m, true
+		}
+	}
+
+	return dateMatch{}, false
+}
+
+func matchRelativeDay(tokens []string, i int, now time.Time) (dateMatch, bool) {
+	for _, rel := range relativeDays {
+		if hasPhrase(tokens, i, rel.phrase) {
+			return dateMatch{date: now.AddDate(0, 0, rel.offset), from: i, to: i + len(rel.phrase) - 1}, true
+		}
+	}
+	return dateMatch{}, false
+}
+
+func matchDaysAgo(tokens []string, i int, now time.Time) (dateMatch, bool) {
+	n, err := strconv.Atoi(tokens[i])
+	if err != nil || n <= 0 || n > 366 || i+2 >= len(tokens) {
+		return dateMatch{}, false
+	}
+
+	unit := tokens[i+1]
+	if unit != "hari" && unit != "day" && unit != "days" {
+		return dateMatch{}, false
+	}
+
+	to := i + 2
+	if tokens[to] == "yang" && to+1 < len(tokens) {
+		to++
+	}
+	if !agoWords[tokens[to]] {
+		return dateMatch{}, false
+	}
+
+	return dateMatch{date: now.AddDate(0, 0, -n), from: i, to: to}, true
+}
+
+func matchWeekday(tokens []string, i int, now time.Time) (dateMatch, bool) {
+	from, idx := i, i
+	strict := false
+
+	switch tokens[idx] {
+	case "last":
+		strict = true
+		idx++
+	case "hari", "on":
+		idx++
+	}
+	if idx >= len(tokens) {
+		return dateMatch{}, false
+	}
+
+	day, ok := weekdays[tokens[idx]]
+	if !ok {
+		return dateMatch{}, false
+	}
+	if from == idx && tokens[idx] == "minggu" && idx+1 < len(tokens) && (tokens[idx+1] == "lalu" || tokens[idx+1] == "kemarin") {
+		// "minggu lalu" means last week, handled by matchRelativeDay.
+		return dateMatch{}, false
+	}
+
+	to := idx
+	if idx+1 < len(tokens) && (tokens[idx+1] == "lalu" || tokens[idx+1] == "kemarin") {
+		strict = true
+		to++
+	}
+
+	back := (int(now.Weekday()) - int(day) + 7) % 7
+	if back == 0 && strict {
+		back = 7
+	}
+
+	return dateMatch{date: now.AddDate(0, 0, -back), from: from, to: to}, true
+}
+
+func matchExplicitDate(tokens []string, i int, now time.Time) (dateMatch, bool) {
+	for _, layout := range dateLayouts {
+		if t, err := time.ParseInLocation(layout, tokens[i], now.Location()); err == nil {
+			return dateMatch{date: withClock(t, now), from: i, to: i}, true
+		}
+	}
+
+	for _, layout := range shortLayouts {
+		if t, err := time.ParseInLocation(layout, tokens[i], now.Location()); err == nil {
+			t = time.Date(now.Year(), t.Month(), t.Day(), 0, 0, 0, 0, now.Location())
+			if t.After(now) {
+				t = t.AddDate(-1, 0, 0)
+			}
+			return dateMatch{date: withClock(t, now), from: i, to: i}, true
+		}
+	}
+
+	return dateMatch{}, false
+}
+
+func hasPhrase(tokens []string, i int, phrase []string) bool {
+	if i+len(phrase) > len(tokens) {
+		return false
+	}
+	for j, word := range phrase {
+		if tokens[i+j] != word {
+			return false
+		}
+	}
+	return true
+}
+
+// withClock keeps the calendar day of date and the time of day of now so an
+// explicit date still sorts naturally against expenses entered the same day.
+func withClock(date, now time.Time) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), now.Hour(), now.Minute(), now.Second(), 0, now.Location())
+}
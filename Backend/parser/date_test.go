@@ -0,0 +1,102 @@
+package parser
+
+import (
+	"testing"
+	"time"
+)
+
+var wib = time.FixedZone("WIB", 7*60*60)
+
+// parserNow is a Friday evening in Jakarta.
+var parserNow = time.Date(2024, time.March, 15, 22, 30, 0, 0, wib)
+
+// onDay is the given day at parserNow's time of day, which every match keeps.
+func onDay(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 22, 30, 0, 0, wib)
+}
+
+func TestFindDate(t *testing.T) {
+	tests := []struct {
+		text     string
+		want     time.Time
+		from, to int
+	}{
+		{"hari ini", onDay(2024, time.March, 15), 0, 1},
+		{"today", onDay(2024, time.March, 15), 0, 0},
+		{"kemarin", onDay(2024, time.March, 14), 0, 0},
+		{"kmrn", onDay(2024, time.March, 14), 0, 0},
+		{"yesterday", onDay(2024, time.March, 14), 0, 0},
+		{"kemarin lusa", onDay(2024, time.March, 13), 0, 1},
+		{"besok", onDay(2024, time.March, 16), 0, 0},
+		{"minggu lalu", onDay(2024, time.March, 8), 0, 1},
+		{"last week", onDay(2024, time.March, 8), 0, 1},
+		{"3 hari lalu", onDay(2024, time.March, 12), 0, 2},
+		{"5 hari yang lalu", onDay(2024, time.March, 10), 0, 3},
+		{"2 days ago", onDay(2024, time.March, 13), 0, 2},
+		{"jumat", onDay(2024, time.March, 15), 0, 0},
+		{"jumat lalu", onDay(2024, time.March, 8), 0, 1},
+		{"last friday", onDay(2024, time.March, 8), 0, 1},
+		{"senin", onDay(2024, time.March, 11), 0, 0},
+		{"hari senin", onDay(2024, time.March, 11), 0, 1},
+		{"on monday", onDay(2024, time.March, 11), 0, 1},
+		{"minggu", onDay(2024, time.March, 10), 0, 0},
+		{"2024-01-02", onDay(2024, time.January, 2), 0, 0},
+		{"02/01/2024", onDay(2024, time.January, 2), 0, 0},
+		{"2-1-2024", onDay(2024, time.January, 2), 0, 0},
+		{"10/3", onDay(2024, time.March, 10), 0, 0},
+		{"20/3", onDay(2023, time.March, 20), 0, 0},
+		{"kopi 20k kemarin", onDay(2024, time.March, 14), 2, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			got, ok := findDate(tokenize(tt.text), parserNow)
+			if !ok {
+				t.Fatalf("findDate(%q) found nothing, want %v", tt.text, tt.want)
+			}
+			if !got.date.Equal(tt.want) || got.from != tt.from || got.to != tt.to {
+				t.Errorf("findDate(%q) = %v at [%d,%d], want %v at [%d,%d]", tt.text, got.date, got.from, got.to, tt.want, tt.from, tt.to)
+			}
+		})
+	}
+}
+
+func TestFindDateNone(t *testing.T) {
+	for _, text := range []string{"makan siang", "500 hari lalu", "3 hari", "32/13"} {
+		if got, ok := findDate(tokenize(text), parserNow); ok {
+			t.Errorf("findDate(%q) = %v, want nothing", text, got.date)
+		}
+	}
+}
+
+// TestFindDateUsesTheUserTimeZone checks that days are counted on the user's
+// calendar: shortly after midnight in Jakarta it is still the previous day
+// in UTC.
+func TestFindDateUsesTheUserTimeZone(t *testing.T) {
+	now := time.Date(2024, time.March, 15, 1, 0, 0, 0, wib)
+
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"kemarin", "2024-03-14"},
+		{"hari ini", "2024-03-15"},
+		{"10/3", "2024-03-10"},
+		{"2024-03-15", "2024-03-15"},
+	}
+
+	for _, tt := range tests {
+		got, ok := findDate(tokenize(tt.text), now)
+		if !ok {
+			t.Fatalf("findDate(%q) found nothing", tt.text)
+		}
+		if day := got.date.In(wib).Format("2006-01-02"); day != tt.want {
+			t.Errorf("findDate(%q) = %s in WIB, want %s", tt.text, day, tt.want)
+		}
+	}
+
+	got, _ := findDate(tokenize("kemarin"), now.UTC())
+	if day := got.date.Format("2006-01-02"); day != "2024-03-13" {
+		t.Errorf("findDate(kemarin) from UTC = %s, want 2024-03-13", day)
+	}
+}
@@ -0,0 +1,64 @@
+// Package parser turns free-form quick-add text such as "makan siang 25rb
+// kemarin" or "Grab to office Rp 32.500 last friday" into expense fields.
+package parser
+
+import (
+	"strings"
+	"time"
+
+	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
+)
+
+// ParseExpenseText extracts the amount, date and category from text. now
+// should already be in the user's time zone; relative dates are resolved
+// against it. Fields that cannot be recognised are left empty so the caller
+// can decide whether to ask the user.
+func ParseExpenseText(text string, now time.Time, categories []domain.Category) domain.ParsedExpense {
+	original := strings.Fields(text)
+	tokens := make([]string, len(original))
+	for i, tok := range original {
+		tokens[i] = strings.TrimRight(strings.ToLower(tok), ",;!?")
+		tokens[i] = strings.TrimSuffix(tokens[i], ".")
+	}
+	consumed := make([]bool, len(tokens))
+
+	parsed := domain.ParsedExpense{CreatedAt: now}
+
+	if m, ok := findDate(tokens, now); ok {
+		parsed.CreatedAt = m.date
+		parsed.DateText = strings.Join(original[m.from:m.to+1], " ")
+		markConsumed(consumed, m.from, m.to)
+	}
+
+	if m, ok := findAmount(tokens, consumed); ok {
+		amount := m.value
+		parsed.Amount = &amount
+		parsed.AmountText = strings.Join(original[m.from:m.to+1], " ")
+		markConsumed(consumed, m.from, m.to)
+	}
+
+	if m, ok := matchCategory(tokens, categories); ok {
+		parsed.CategoryId = m.category.Id
+		parsed.CategoryName = m.category.Name
+		parsed.CategoryKeyword = m.keyword
+	}
+
+	var description []string
+	for i, tok := range original {
+		if !consumed[i] {
+			description = append(description, tok)
+		}
+	}
+	parsed.Description = strings.Trim(strings.Join(description, " "), " ,;:-")
+	if parsed.Description == "" {
+		parsed.Description = strings.TrimSpace(text)
+	}
+
+	return parsed
+}
+
+func markConsumed(consumed []bool, from, to int) {
+	for i := from; i <= to; i++ {
+		consumed[i] = true
+	}
+}
@@ -0,0 +1,83 @@
+package parser
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
+)
+
+var userCategories = []domain.Category{
+	{Id: 1, Name: "Food"},
+	{Id: 2, Name: "Transport"},
+	{Id: 3, Name: "Kos"},
+	{Id: 4, Name: "Health Care"},
+	{Id: 5, Name: "Pet Supplies"},
+}
+
+func TestParseExpenseText(t *testing.T) {
+	tests := []struct {
+		text            string
+		amount          float64
+		amountText      string
+		date            time.Time
+		dateText        string
+		categoryId      int
+		categoryKeyword string
+		description     string
+	}{
+		{"makan siang 25rb kemarin", 25000, "25rb", onDay(2024, time.March, 14), "kemarin", 1, "makan", "makan siang"},
+		{"Grab to office Rp 32.500 last friday", 32500, "Rp 32.500", onDay(2024, time.March, 8), "last friday", 2, "grab", "Grab to office"},
+		{"Food court 50k", 50000, "50k", parserNow, "", 1, "food", "Food court"},
+		{"bayar kos 1,5 jt", 1500000, "1,5 jt", parserNow, "", 3, "kos", "bayar kos"},
+		{"health care checkup 200k 3 hari lalu", 200000, "200k", onDay(2024, time.March, 12), "3 hari lalu", 4, "health care", "health care checkup"},
+		{"obat flu 30k", 30000, "30k", parserNow, "", 4, "obat", "obat flu"},
+		{"makanan kucing 80k supplies", 80000, "80k", parserNow, "", 5, "supplies", "makanan kucing supplies"},
+		{"kopi 20k, kemarin.", 20000, "20k,", onDay(2024, time.March, 14), "kemarin.", 1, "kopi", "kopi"},
+		{"hadiah ulang tahun 150rb", 150000, "150rb", parserNow, "", 0, "", "hadiah ulang tahun"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			got := ParseExpenseText(tt.text, parserNow, userCategories)
+
+			if got.Amount == nil || *got.Amount != tt.amount || got.AmountText != tt.amountText {
+				t.Errorf("amount = %v %q, want %v %q", got.Amount, got.AmountText, tt.amount, tt.amountText)
+			}
+			if !got.CreatedAt.Equal(tt.date) || got.DateText != tt.dateText {
+				t.Errorf("date = %v %q, want %v %q", got.CreatedAt, got.DateText, tt.date, tt.dateText)
+			}
+			if got.CategoryId != tt.categoryId || got.CategoryKeyword != tt.categoryKeyword {
+				t.Errorf("category = %d (%q), want %d (%q)", got.CategoryId, got.CategoryKeyword, tt.categoryId, tt.categoryKeyword)
+			}
+			if got.Description != tt.description {
+				t.Errorf("description = %q, want %q", got.Description, tt.description)
+			}
+		})
+	}
+}
+
+func TestParseExpenseTextWithoutAmount(t *testing.T) {
+	got := ParseExpenseText("makan siang kemarin", parserNow, userCategories)
+
+	if got.Amount != nil {
+		t.Errorf("amount = %v, want none so the caller can ask", *got.Amount)
+	}
+	if got.CategoryName != "Food" || got.Description != "makan siang" {
+		t.Errorf("got %q in %q, want \"makan siang\" in Food", got.Description, got.CategoryName)
+	}
+}
+
+func TestParseExpenseTextKeepsTextAsDescription(t *testing.T) {
+	got := ParseExpenseText(" 25000 ", parserNow, nil)
+
+	if got.Amount == nil || *got.Amount != 25000 {
+		t.Fatalf("amount = %v, want 25000", got.Amount)
+	}
+	if got.Description != "25000" {
+		t.Errorf("description = %q, want the original text", got.Description)
+	}
+	if got.CategoryId != 0 {
+		t.Errorf("category = %d, want none without categories", got.CategoryId)
+	}
+}
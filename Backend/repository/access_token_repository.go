@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
+)
+
+type AccessTokenRepository interface {
+	SaveAccessToken(ctx context.Context, tx *sql.Tx, token *domain.AccessToken) error
+	DeleteAccessToken(ctx context.Context, tx *sql.Tx, tokenId, userId int) error
+	FindAllAccessToken(ctx context.Context, tx *sql.Tx, userId int) ([]domain.AccessToken, error)
+	FindAccessTokenByHash(ctx context.Context, tx *sql.Tx, hash string) (*domain.AccessToken, error)
+	UpdateLastUsed(ctx context.Context, tx *sql.Tx, tokenId int, lastUsedAt time.Time) error
+}
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
+)
+
+type accessTokenRepositoryImpl struct{}
+
+func NewAccessTokenRepository() AccessTokenRepository {
+	return &accessTokenRepositoryImpl{}
+}
+
+const accessTokenColumns = "id,user_id,name,token_prefix,token_hash,scopes,last_used_at,expires_at,created_at"
+
+func (repository *accessTokenRepositoryImpl) SaveAccessToken(ctx context.Context, tx *sql.Tx, token *domain.AccessToken) error {
+	query := "insert into personal_access_tokens(user_id,name,token_prefix,token_hash,scopes,expires_at,created_at) values(?,?,?,?,?,?,?)"
+	result, err := tx.ExecContext(ctx, query, token.UserId, token.Name, token.Prefix, token.Hash, strings.Join(token.Scopes, ","), token.ExpiresAt, token.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to insert access token (name=%s, userId=%d): %w", token.Name, token.UserId, err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert id for access token (name=%s, userId=%d): %w", token.Name, token.UserId, err)
+	}
+
+	token.Id = int(id)
+
+	return nil
+}
+
+func (repository *accessTokenRepositoryImpl) DeleteAccessToken(ctx context.Context, tx *sql.Tx, tokenId, userId int) error {
+	query := "delete from personal_access_tokens where id = ? and user_id = ?"
+	result, err := tx.ExecContext(ctx, query, tokenId, userId)
+	if err != nil {
+		return fmt.Errorf("failed to delete access token (id=%d, userId=%d): %w", tokenId, userId, err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected for access token (id=%d, userId=%d): %w", tokenId, userId, err)
+	}
+	if affected == 0 {
+		return fmt.Errorf("access token not found (id=%d, userId=%d): %w", tokenId, userId, sql.ErrNoRows)
+	}
+
+	return nil
+}
+
+func (repository *accessTokenRepositoryImpl) FindAllAccessToken(ctx context.Context, tx *sql.Tx, userId int) ([]domain.AccessToken, error) {
+	query := "select " + accessTokenColumns + " from personal_access_tokens where user_id = ? order by id"
+	rows, err := tx.QueryContext(ctx, query, userId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query access tokens for userId=%d: %w", userId, err)
+	}
+	defer rows.Close()
+
+	var tokens []domain.AccessToken
+	for rows.Next() {
+		token, err := scanAccessToken(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan access token for userId=%d: %w", userId, err)
+		}
+		tokens = append(tokens, *token)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating access tokens for userId=%d: %w", userId, err)
+	}
+
+	return tokens, nil
+}
+
+func (repository *accessTokenRepositoryImpl) FindAccessTokenByHash(ctx context.Context, tx *sql.Tx, hash string) (*domain.AccessToken, error) {
+	query := "select " + accessTokenColumns + " from personal_access_tokens where token_hash = ?"
+	token, err := scanAccessToken(tx.QueryRowContext(ctx, query, hash))
+	if err != nil {
+		return nil, fmt.Errorf("access token not found: %w", err)
+	}
+
+	return token, nil
+}
+
+func (repository *accessTokenRepositoryImpl) UpdateLastUsed(ctx context.Context, tx *sql.Tx, tokenId int, lastUsedAt time.Time) error {
+	query := "update personal_access_tokens set last_used_at = ? where id = ?"
+	if _, err := tx.ExecContext(ctx, query, lastUsedAt, tokenId); err != nil {
+		return fmt.Errorf("failed to update last used for access token (id=%d): %w", tokenId, err)
+	}
+
+	return nil
+}
+
+func scanAccessToken(row rowScanner) (*domain.AccessToken, error) {
+	var token domain.AccessToken
+	var scopes string
+	var lastUsedAt, expiresAt sql.NullTime
+
+	err := row.Scan(&token.Id, &token.UserId, &token.Name, &token.Prefix, &token.Hash, &scopes, &lastUsedAt, &expiresAt, &token.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if scopes != "" {
+		token.Scopes = strings.Split(scopes, ",")
+	}
+	if lastUsedAt.Valid {
+		token.LastUsedAt = &lastUsedAt.Time
+	}
+	if expiresAt.Valid {
+		token.ExpiresAt = &expiresAt.Time
+	}
+
+	return &token, nil
+}
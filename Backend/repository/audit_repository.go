@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
+)
+
+// AuditRepository is append-only: entries are never updated or deleted.
+type AuditRepository interface {
+	SaveAudit(ctx context.Context, tx *sql.Tx, entry *domain.AuditEntry) error
+	FindAllAudit(ctx context.Context, tx *sql.Tx, userId int, filter domain.AuditFilter) ([]domain.AuditEntry, error)
+}
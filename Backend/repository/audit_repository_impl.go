@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
+)
+
+type auditRepositoryImpl struct{}
+
+func NewAuditRepository() AuditRepository {
+	return &auditRepositoryImpl{}
+}
+
+// SaveAudit stamps expense and category entries with the user's next sync
+// sequence number. Taking it locks the user's row until the transaction
+// ends, so the numbers become visible in the order they were handed out and
+// the sync feed never skips an entry that commits late.
+func (repository *auditRepositoryImpl) SaveAudit(ctx context.Context, tx *sql.Tx, entry *domain.AuditEntry) error {
+	var syncSeq sql.NullInt64
+	if entry.EntityType == domain.AuditEntityExpense || entry.EntityType == domain.AuditEntityCategory {
+		_, err := tx.ExecContext(ctx, "update users set sync_seq = sync_seq + 1 where id = ?", entry.UserId)
+		if err != nil {
+			return fmt.Errorf("failed to advance sync sequence (userId=%d): %w", entry.UserId, err)
+		}
+		err = tx.QueryRowContext(ctx, "select sync_seq from users where id = ?", entry.UserId).Scan(&syncSeq)
+		if err != nil {
+			return fmt.Errorf("failed to read sync sequence (userId=%d): %w", entry.UserId, err)
+		}
+	}
+
+	query := "insert into audit_log(user_id,actor_id,entity_type,entity_id,action,before_data,after_data,request_id,created_at,sync_seq) values(?,?,?,?,?,?,?,?,?,?)"
+	result, err := tx.ExecContext(ctx, query, entry.UserId, entry.ActorId, entry.EntityType, entry.EntityId, entry.Action, nullJSON(entry.Before), nullJSON(entry.After), entry.RequestId, entry.CreatedAt, syncSeq)
+	if err != nil {
+		return fmt.Errorf("failed to insert audit entry (%s %s id=%d, userId=%d): %w", entry.Action, entry.EntityType, entry.EntityId, entry.UserId, err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert id for audit entry (userId=%d): %w", entry.UserId, err)
+	}
+
+	entry.Id = id
+
+	return nil
+}
+
+// FindAllAudit returns the user's entries newest first. BeforeId pages
+// backwards through the log.
+func (repository *auditRepositoryImpl) FindAllAudit(ctx context.Context, tx *sql.Tx, userId int, filter domain.AuditFilter) ([]domain.AuditEntry, error) {
+	var (
+		sb   strings.Builder
+		args = []any{userId}
+	)
+
+	sb.WriteString("select id,user_id,actor_id,entity_type,entity_id,action,before_data,after_data,request_id,created_at from audit_log where user_id = ?")
+	if filter.EntityType != "" {
+		sb.WriteString(" and entity_type = ?")
+		args = append(args, filter.EntityType)
+	}
+	if filter.EntityId > 0 {
+		sb.WriteString(" and entity_id = ?")
+		args = append(args, filter.EntityId)
+	}
+	if filter.BeforeId > 0 {
+		sb.WriteString(" and id < ?")
+		args = append(args, filter.BeforeId)
+	}
+	sb.WriteString(" order by id desc limit ?")
+	args = append(args, filter.Limit)
+
+	rows, err := tx.QueryContext(ctx, sb.String(), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query audit log for userId=%d: %w", userId, err)
+	}
+	defer rows.Close()
+
+	entries := []domain.AuditEntry{}
+	for rows.Next() {
+		var (
+			entry         domain.AuditEntry
+			actorId       sql.NullInt64
+			before, after []byte
+		)
+		err := rows.Scan(&entry.Id, &entry.UserId, &actorId, &entry.EntityType, &entry.EntityId, &entry.Action, &before, &after, &entry.RequestId, &entry.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan audit row for userId=%d: %w", userId, err)
+		}
+		if actorId.Valid {
+			id := int(actorId.Int64)
+			entry.ActorId = &id
+		}
+		entry.Before, entry.After = before, after
+		entries = append(entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error for userId=%d: %w", userId, err)
+	}
+
+	return entries, nil
+}
+
+func nullJSON(data []byte) any {
+	if len(data) == 0 {
+		return nil
+	}
+	return string(data)
+}
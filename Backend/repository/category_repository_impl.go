@@ -4,12 +4,17 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
 	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
 )
 
 type CategoryRepositoryImpl struct{}
 
+// categoryNotTrashed keeps rows that point to a category in the trash out of
+// queries on tables without their own deleted_at, such as rules.
+const categoryNotTrashed = "category_id not in (select id from categories where deleted_at is not null)"
+
 func NewCategoryRepository() CategoryRepository {
 	return &CategoryRepositoryImpl{}
 }
@@ -31,19 +36,29 @@ func (categoryRepository *CategoryRepositoryImpl) SaveCategory(ctx context.Conte
 	return nil
 }
 
+// DeleteCategory moves the category and its expenses to the trash. They get
+// the same deleted_at so restoring the category brings back exactly the
+// expenses deleted with it.
 func (categoryRepository *CategoryRepositoryImpl) DeleteCategory(ctx context.Context, tx *sql.Tx, categoryId, userId int) error {
-	query := "delete from categories where id = ? and user_id = ?"
-	_, err := tx.ExecContext(ctx, query, categoryId, userId)
+	deletedAt := time.Now().UTC().Truncate(time.Second)
 
+	query := "update categories set deleted_at = ? where id = ? and user_id = ? and deleted_at is null"
+	_, err := tx.ExecContext(ctx, query, deletedAt, categoryId, userId)
 	if err != nil {
 		return fmt.Errorf("failed to delete category (id=%d, userId=%d): %w", categoryId, userId, err)
 	}
 
+	query = "update expenses set deleted_at = ? where category_id = ? and user_id = ? and deleted_at is null"
+	_, err = tx.ExecContext(ctx, query, deletedAt, categoryId, userId)
+	if err != nil {
+		return fmt.Errorf("failed to delete expenses of category (id=%d, userId=%d): %w", categoryId, userId, err)
+	}
+
 	return nil
 }
 
 func (categoryRepository *CategoryRepositoryImpl) FindCategoryById(ctx context.Context, tx *sql.Tx, categoryId, userId int) (*domain.Category, error) {
-	query := "select id,name from categories where id = ? and user_id = ?"
+	query := "select id,name from categories where id = ? and user_id = ? and deleted_at is null"
 	row := tx.QueryRowContext(ctx, query, categoryId, userId)
 
 	category := domain.Category{}
@@ -59,7 +74,7 @@ func (categoryRepository *CategoryRepositoryImpl) FindCategoryById(ctx context.C
 }
 
 func (categoryRepository *CategoryRepositoryImpl) FindAllCategory(ctx context.Context, tx *sql.Tx, userId int) ([]domain.Category, error) {
-	query := "select id,name from categories where user_id = ?"
+	query := "select id,name from categories where user_id = ? and deleted_at is null"
 	rows, err := tx.QueryContext(ctx, query, userId)
 
 	if err != nil {
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
+)
+
+type CategoryRuleRepository interface {
+	SaveRule(ctx context.Context, tx *sql.Tx, rule *domain.CategoryRule) error
+	UpdateRule(ctx context.Context, tx *sql.Tx, rule *domain.CategoryRule) error
+	DeleteRule(ctx context.Context, tx *sql.Tx, ruleId, userId int) error
+	FindRuleById(ctx context.Context, tx *sql.Tx, ruleId, userId int) (*domain.CategoryRule, error)
+	FindAllRule(ctx context.Context, tx *sql.Tx, userId int) ([]domain.CategoryRule, error)
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
+)
+
+type categoryRuleRepositoryImpl struct{}
+
+func NewCategoryRuleRepository() CategoryRuleRepository {
+	return &categoryRuleRepositoryImpl{}
+}
+
+const categoryRuleColumns = "id,user_id,category_id,name,description_contains,description_pattern,min_amount,max_amount,priority,created_at"
+
+func (repository *categoryRuleRepositoryImpl) SaveRule(ctx context.Context, tx *sql.Tx, rule *domain.CategoryRule) error {
+	query := "insert into category_rules(user_id,category_id,name,description_contains,description_pattern,min_amount,max_amount,priority,created_at) values(?,?,?,?,?,?,?,?,?)"
+	result, err := tx.ExecContext(ctx, query, rule.UserId, rule.CategoryId, rule.Name, rule.DescriptionContains, rule.DescriptionPattern, rule.MinAmount, rule.MaxAmount, rule.Priority, rule.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to insert category rule (name=%s, userId=%d): %w", rule.Name, rule.UserId, err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert id for category rule (name=%s, userId=%d): %w", rule.Name, rule.UserId, err)
+	}
+
+	rule.Id = int(id)
+
+	return nil
+}
+
+func (repository *categoryRuleRepositoryImpl) UpdateRule(ctx context.Context, tx *sql.Tx, rule *domain.CategoryRule) error {
+	query := "update category_rules set category_id = ?, name = ?, description_contains = ?, description_pattern = ?, min_amount = ?, max_amount = ?, priority = ? where id = ? and user_id = ?"
+	_, err := tx.ExecContext(ctx, query, rule.CategoryId, rule.Name, rule.DescriptionContains, rule.DescriptionPattern, rule.MinAmount, rule.MaxAmount, rule.Priority, rule.Id, rule.UserId)
+	if err != nil {
+		return fmt.Errorf("failed to update category rule (id=%d, userId=%d): %w", rule.Id, rule.UserId, err)
+	}
+
+	return nil
+}
+
+func (repository *categoryRuleRepositoryImpl) DeleteRule(ctx context.Context, tx *sql.Tx, ruleId, userId int) error {
+	query := "delete from category_rules where id = ? and user_id = ?"
+	_, err := tx.ExecContext(ctx, query, ruleId, userId)
+	if err != nil {
+		return fmt.Errorf("failed to delete category rule (id=%d, userId=%d): %w", ruleId, userId, err)
+	}
+
+	return nil
+}
+
+func (repository *categoryRuleRepositoryImpl) FindRuleById(ctx context.Context, tx *sql.Tx, ruleId, userId int) (*domain.CategoryRule, error) {
+	query := "select " + categoryRuleColumns + " from category_rules where id = ? and user_id = ? and " + categoryNotTrashed
+	rule, err := scanCategoryRule(tx.QueryRowContext(ctx, query, ruleId, userId))
+	if err != nil {
+		return nil, fmt.Errorf("category rule not found (id=%d, userId=%d): %w", ruleId, userId, err)
+	}
+
+	return rule, nil
+}
+
+// FindAllRule returns the user's rules in evaluation order: lowest priority
+// number first, older rules first within the same priority.
+func (repository *categoryRuleRepositoryImpl) FindAllRule(ctx context.Context, tx *sql.Tx, userId int) ([]domain.CategoryRule, error) {
+	query := "select " + categoryRuleColumns + " from category_rules where user_id = ? and " + categoryNotTrashed + " order by priority asc, id asc"
+	rows, err := tx.QueryContext(ctx, query, userId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query category rules for userId=%d: %w", userId, err)
+	}
+	defer rows.Close()
+
+	rules := []domain.CategoryRule{}
+	for rows.Next() {
+		rule, err := scanCategoryRule(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan category rule row for userId=%d: %w", userId, err)
+		}
+		rules = append(rules, *rule)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error for userId=%d: %w", userId, err)
+	}
+
+	return rules, nil
+}
+
+func scanCategoryRule(row rowScanner) (*domain.CategoryRule, error) {
+	var (
+		rule      domain.CategoryRule
+		minAmount sql.NullFloat64
+		maxAmount sql.NullFloat64
+	)
+
+	err := row.Scan(
+		&rule.Id,
+		&rule.UserId,
+		&rule.CategoryId,
+		&rule.Name,
+		&rule.DescriptionContains,
+		&rule.DescriptionPattern,
+		&minAmount,
+		&maxAmount,
+		&rule.Priority,
+		&rule.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if minAmount.Valid {
+		rule.MinAmount = &minAmount.Float64
+	}
+	if maxAmount.Valid {
+		rule.MaxAmount = &maxAmount.Float64
+	}
+
+	return &rule, nil
+}
@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
+)
+
+type DataExportRepository interface {
+	SaveExport(ctx context.Context, tx *sql.Tx, export *domain.DataExport) error
+	UpdateExport(ctx context.Context, tx *sql.Tx, export *domain.DataExport) error
+	FindExportById(ctx context.Context, tx *sql.Tx, exportId int64, userId int) (*domain.DataExport, error)
+	// FindActiveExport returns the user's export that is pending or running.
+	FindActiveExport(ctx context.Context, tx *sql.Tx, userId int) (*domain.DataExport, error)
+	FindAllExport(ctx context.Context, tx *sql.Tx, userId int, limit int) ([]domain.DataExport, error)
+	// ClaimExport locks the oldest pending export, or a running one started
+	// before staleBefore whose worker gave up, skipping rows another worker
+	// holds.
+	ClaimExport(ctx context.Context, tx *sql.Tx, staleBefore time.Time) (*domain.DataExport, error)
+	SaveExportArchive(ctx context.Context, tx *sql.Tx, exportId int64, archive []byte) error
+	FindExportArchive(ctx context.Context, tx *sql.Tx, exportId int64) ([]byte, error)
+	// ExpireExports deletes the archives of ready exports that expired at
+	// now and returns how many there were.
+	ExpireExports(ctx context.Context, tx *sql.Tx, now time.Time) (int, error)
+}
@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
+)
+
+type dataExportRepositoryImpl struct{}
+
+func NewDataExportRepository() DataExportRepository {
+	return &dataExportRepositoryImpl{}
+}
+
+const dataExportColumns = "id,user_id,status,size_bytes,error,requested_at,started_at,completed_at,expires_at"
+
+func (repository *dataExportRepositoryImpl) SaveExport(ctx context.Context, tx *sql.Tx, export *domain.DataExport) error {
+	query := "insert into data_exports(user_id,status,requested_at) values(?,?,?)"
+	result, err := tx.ExecContext(ctx, query, export.UserId, export.Status, export.RequestedAt)
+	if err != nil {
+		return fmt.Errorf("failed to insert data export (userId=%d): %w", export.UserId, err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert id for data export (userId=%d): %w", export.UserId, err)
+	}
+
+	export.Id = id
+
+	return nil
+}
+
+func (repository *dataExportRepositoryImpl) UpdateExport(ctx context.Context, tx *sql.Tx, export *domain.DataExport) error {
+	query := "update data_exports set status = ?, size_bytes = ?, error = ?, started_at = ?, completed_at = ?, expires_at = ? where id = ?"
+	_, err := tx.ExecContext(ctx, query, export.Status, export.SizeBytes, export.Error, export.StartedAt, export.CompletedAt, export.ExpiresAt, export.Id)
+	if err != nil {
+		return fmt.Errorf("failed to update data export (id=%d): %w", export.Id, err)
+	}
+
+	return nil
+}
+
+func (repository *dataExportRepositoryImpl) FindExportById(ctx context.Context, tx *sql.Tx, exportId int64, userId int) (*domain.DataExport, error) {
+	query := "select " + dataExportColumns + " from data_exports where id = ? and user_id = ?"
+	export, err := scanDataExport(tx.QueryRowContext(ctx, query, exportId, userId))
+	if err != nil {
+		return nil, fmt.Errorf("data export not found (id=%d, userId=%d): %w", exportId, userId, err)
+	}
+
+	return export, nil
+}
+
+func (repository *dataExportRepositoryImpl) FindActiveExport(ctx context.Context, tx *sql.Tx, userId int) (*domain.DataExport, error) {
+	query := "select " + dataExportColumns + " from data_exports where user_id = ? and status in (?,?) order by id desc limit 1"
+	export, err := scanDataExport(tx.QueryRowContext(ctx, query, userId, domain.DataExportPending, domain.DataExportRunning))
+	if err != nil {
+		return nil, fmt.Errorf("no active data export (userId=%d): %w", userId, err)
+	}
+
+	return export, nil
+}
+
+func (repository *dataExportRepositoryImpl) FindAllExport(ctx context.Context, tx *sql.Tx, userId int, limit int) ([]domain.DataExport, error) {
+	query := "select " + dataExportColumns + " from data_exports where user_id = ? order by id desc limit ?"
+	rows, err := tx.QueryContext(ctx, query, userId, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query data exports for userId=%d: %w", userId, err)
+	}
+	defer rows.Close()
+
+	exports := []domain.DataExport{}
+	for rows.Next() {
+		export, err := scanDataExport(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan data export for userId=%d: %w", userId, err)
+		}
+		exports = append(exports, *export)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating data exports for userId=%d: %w", userId, err)
+	}
+
+	return exports, nil
+}
+
+func (repository *dataExportRepositoryImpl) ClaimExport(ctx context.Context, tx *sql.Tx, staleBefore time.Time) (*domain.DataExport, error) {
+	query := "select " + dataExportColumns + ` from data_exports
+		where status = ? or (status = ? and started_at < ?)
+		order by id limit 1 for update skip locked`
+	export, err := scanDataExport(tx.QueryRowContext(ctx, query, domain.DataExportPending, domain.DataExportRunning, staleBefore))
+	if err != nil {
+		return nil, fmt.Errorf("no data export to claim: %w", err)
+	}
+
+	return export, nil
+}
+
+func (repository *dataExportRepositoryImpl) SaveExportArchive(ctx context.Context, tx *sql.Tx, exportId int64, archive []byte) error {
+	_, err := tx.ExecContext(ctx, "update data_exports set archive = ? where id = ?", archive, exportId)
+	if err != nil {
+		return fmt.Errorf("failed to save data export archive (id=%d): %w", exportId, err)
+	}
+
+	return nil
+}
+
+func (repository *dataExportRepositoryImpl) FindExportArchive(ctx context.Context, tx *sql.Tx, exportId int64) ([]byte, error) {
+	var archive []byte
+	err := tx.QueryRowContext(ctx, "select archive from data_exports where id = ? and archive is not null", exportId).Scan(&archive)
+	if err != nil {
+		return nil, fmt.Errorf("data export archive not found (id=%d): %w", exportId, err)
+	}
+
+	return archive, nil
+}
+
+func (repository *dataExportRepositoryImpl) ExpireExports(ctx context.Context, tx *sql.Tx, now time.Time) (int, error) {
+	query := "update data_exports set status = ?, archive = null where status = ? and expires_at <= ?"
+	result, err := tx.ExecContext(ctx, query, domain.DataExportExpired, domain.DataExportReady, now)
+	if err != nil {
+		return 0, fmt.Errorf("failed to expire data exports: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to check rows affected: %w", err)
+	}
+
+	return int(rows), nil
+}
+
+func scanDataExport(row rowScanner) (*domain.DataExport, error) {
+	var export domain.DataExport
+	var startedAt, completedAt, expiresAt sql.NullTime
+
+	err := row.Scan(&export.Id, &export.UserId, &export.Status, &export.SizeBytes, &export.Error, &export.RequestedAt, &startedAt, &completedAt, &expiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if startedAt.Valid {
+		export.StartedAt = &startedAt.Time
+	}
+	if completedAt.Valid {
+		export.CompletedAt = &completedAt.Time
+	}
+	if expiresAt.Valid {
+		export.ExpiresAt = &expiresAt.Time
+	}
+
+	return &export, nil
+}
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
+)
+
+type ExpenseImportRepository interface {
+	SaveImport(ctx context.Context, tx *sql.Tx, userId, expenseId int, externalId, source string) error
+	DeleteImport(ctx context.Context, tx *sql.Tx, userId, expenseId int) error
+	FindImportedIds(ctx context.Context, tx *sql.Tx, userId int, externalIds []string) (map[string]bool, error)
+	FindManualExpenses(ctx context.Context, tx *sql.Tx, userId int, from, to time.Time) ([]domain.Expense, error)
+}
@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
+)
+
+type expenseImportRepositoryImpl struct{}
+
+func NewExpenseImportRepository() ExpenseImportRepository {
+	return &expenseImportRepositoryImpl{}
+}
+
+func (repository *expenseImportRepositoryImpl) SaveImport(ctx context.Context, tx *sql.Tx, userId, expenseId int, externalId, source string) error {
+	query := "insert into expense_imports(user_id,expense_id,external_id,source,imported_at) values(?,?,?,?,?)"
+	_, err := tx.ExecContext(ctx, query, userId, expenseId, externalId, source, time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("failed to insert expense import (externalId=%s, userId=%d): %w", externalId, userId, err)
+	}
+
+	return nil
+}
+
+// DeleteImport forgets that an expense was imported, so importing the same
+// statement again brings the transaction back.
+func (repository *expenseImportRepositoryImpl) DeleteImport(ctx context.Context, tx *sql.Tx, userId, expenseId int) error {
+	query := "delete from expense_imports where user_id = ? and expense_id = ?"
+	_, err := tx.ExecContext(ctx, query, userId, expenseId)
+	if err != nil {
+		return fmt.Errorf("failed to delete expense import (expenseId=%d, userId=%d): %w", expenseId, userId, err)
+	}
+
+	return nil
+}
+
+// FindImportedIds reports which of the external ids were imported before.
+// The record outlives the expense, so an imported expense the user deleted
+// is not brought back by importing the same statement again.
+func (repository *expenseImportRepositoryImpl) FindImportedIds(ctx context.Context, tx *sql.Tx, userId int, externalIds []string) (map[string]bool, error) {
+	imported := map[string]bool{}
+	if len(externalIds) == 0 {
+		return imported, nil
+	}
+
+	args := []any{userId}
+	for _, id := range externalIds {
+		args = append(args, id)
+	}
+
+	query := "select external_id from expense_imports where user_id = ? and external_id in (" + placeholders(len(externalIds)) + ")"
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query expense imports for userId=%d: %w", userId, err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var externalId string
+		if err := rows.Scan(&externalId); err != nil {
+			return nil, fmt.Errorf("failed to scan expense import row for userId=%d: %w", userId, err)
+		}
+		imported[externalId] = true
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error for userId=%d: %w", userId, err)
+	}
+
+	return imported, nil
+}
+
+// FindManualExpenses returns the expenses between from and to that did not
+// come from an import, the candidates for duplicates of imported ones.
+func (repository *expenseImportRepositoryImpl) FindManualExpenses(ctx context.Context, tx *sql.Tx, userId int, from, to time.Time) ([]domain.Expense, error) {
+	query := `select e.id,e.category_id,e.description,e.amount,e.created_at from expenses e
+		left join expense_imports i on i.expense_id = e.id
+		where e.user_id = ? and e.deleted_at is null and e.created_at between ? and ? and i.id is null`
+	rows, err := tx.QueryContext(ctx, query, userId, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query manual expenses for userId=%d: %w", userId, err)
+	}
+	defer rows.Close()
+
+	var expenses []domain.Expense
+	for rows.Next() {
+		var (
+			expense     domain.Expense
+			description sql.NullString
+		)
+		if err := rows.Scan(&expense.Id, &expense.CategoryId, &description, &expense.Amount, &expense.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan expense row for userId=%d: %w", userId, err)
+		}
+		expense.Description = description.String
+		expense.UserId = userId
+		expenses = append(expenses, expense)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error for userId=%d: %w", userId, err)
+	}
+
+	return expenses, nil
+}
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
 )
@@ -28,12 +29,17 @@ func (repository *expenseRepositoryImpl) CreateExpense(ctx context.Context, tx *
 	}
 
 	expense.Id = int(idExpense)
+	expense.Version = 1
 
 	return nil
 }
+
+// UpdateExpense only writes when the row is still at expense.Version and
+// bumps the version. A stale or missing row yields an error wrapping
+// sql.ErrNoRows.
 func (repository *expenseRepositoryImpl) UpdateExpense(ctx context.Context, tx *sql.Tx, expense *domain.Expense) (*domain.Expense, error) {
-	query := "update expenses set description = ?, amount = ?,created_at = ?,category_id =? where id =? and user_id = ?"
-	result, err := tx.ExecContext(ctx, query, expense.Description, expense.Amount, expense.CreatedAt, expense.CategoryId, expense.Id, expense.UserId)
+	query := "update expenses set description = ?, amount = ?,created_at = ?,category_id =?, version = version + 1 where id =? and user_id = ? and version = ? and deleted_at is null"
+	result, err := tx.ExecContext(ctx, query, expense.Description, expense.Amount, expense.CreatedAt, expense.CategoryId, expense.Id, expense.UserId, expense.Version)
 	if err != nil {
 		return nil, fmt.Errorf("failed to update expense (id=%d, userId=%d): %w", expense.Id, expense.UserId, err)
 	}
@@ -43,22 +49,34 @@ func (repository *expenseRepositoryImpl) UpdateExpense(ctx context.Context, tx *
 		return nil, fmt.Errorf("failed to check rows affected: %w", err)
 	}
 	if rows == 0 {
-		return nil, fmt.Errorf("no expense updated (id=%d, userId=%d)", expense.Id, expense.UserId)
+		return nil, fmt.Errorf("no expense updated (id=%d, userId=%d, version=%d): %w", expense.Id, expense.UserId, expense.Version, sql.ErrNoRows)
 	}
+	expense.Version++
 
 	return expense, nil
 }
+
+// DeleteExpanse moves the expense to the trash. An expense that is missing
+// or already trashed yields an error wrapping sql.ErrNoRows.
 func (repository *expenseRepositoryImpl) DeleteExpanse(ctx context.Context, tx *sql.Tx, expenseId, userId int) error {
-	query := "delete from expenses where id = ? and user_id =?"
-	_, err := tx.ExecContext(ctx, query, expenseId, userId)
+	query := "update expenses set deleted_at = ? where id = ? and user_id = ? and deleted_at is null"
+	result, err := tx.ExecContext(ctx, query, time.Now().UTC(), expenseId, userId)
 	if err != nil {
 		return fmt.Errorf("failed to delete expense (id=%d, userId=%d): %w", expenseId, userId, err)
 	}
 
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("no expense deleted (id=%d, userId=%d): %w", expenseId, userId, sql.ErrNoRows)
+	}
+
 	return nil
 }
 func (repository *expenseRepositoryImpl) FindExpanseById(ctx context.Context, tx *sql.Tx, expenseId, userId int) (*domain.Expense, error) {
-	query := "select id,category_id,description,amount,created_at from expenses where id = ? and user_id = ?"
+	query := "select id,category_id,description,amount,created_at,version from expenses where id = ? and user_id = ? and deleted_at is null"
 	row := tx.QueryRowContext(ctx, query, expenseId, userId)
 
 	expense := domain.Expense{}
@@ -69,6 +87,7 @@ func (repository *expenseRepositoryImpl) FindExpanseById(ctx context.Context, tx
 		&expense.Description,
 		&expense.Amount,
 		&expense.CreatedAt,
+		&expense.Version,
 	)
 
 	if err != nil {
@@ -93,15 +112,25 @@ func (repository *expenseRepositoryImpl) FindAllExpanse(ctx context.Context, tx
             category_id,
             description,
             amount,
-            created_at
+            created_at,
+            version
         FROM expenses
-        WHERE user_id = ?
+        WHERE user_id = ? AND deleted_at IS NULL
     `)
 	args = append(args, userId)
 
-	if filter.CategoryId != nil {
-		sb.WriteString(" AND category_id = ?")
-		args = append(args, *filter.CategoryId)
+	if len(filter.CategoryIds) > 0 {
+		sb.WriteString(" AND category_id IN (" + placeholders(len(filter.CategoryIds)) + ")")
+		for _, id := range filter.CategoryIds {
+			args = append(args, id)
+		}
+	}
+
+	if len(filter.ExcludeCategoryIds) > 0 {
+		sb.WriteString(" AND category_id NOT IN (" + placeholders(len(filter.ExcludeCategoryIds)) + ")")
+		for _, id := range filter.ExcludeCategoryIds {
+			args = append(args, id)
+		}
 	}
 
 	if filter.MinAmount != nil {
@@ -124,9 +153,16 @@ func (repository *expenseRepositoryImpl) FindAllExpanse(ctx context.Context, tx
 		args = append(args, filter.CreatedAfter.UTC().Format("2006-01-02 15:04:05"))
 	}
 	if filter.Description != nil {
-        sb.WriteString(" AND description LIKE ?")
-        args = append(args, "%"+*filter.Description+"%")
-    }
+		sb.WriteString(" AND description LIKE ?")
+		args = append(args, "%"+escapeLike(*filter.Description)+"%")
+	}
+	if filter.Expression != nil {
+		clause, err := compileFilterNode(*filter.Expression, userId, &args)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compile filter expression for userId=%d: %w", userId, err)
+		}
+		sb.WriteString(" AND " + clause)
+	}
 	if filter.SortBy != "" {
 		dir := strings.ToUpper(filter.Order)
 		if dir != "ASC" && dir != "DESC" {
@@ -159,6 +195,7 @@ func (repository *expenseRepositoryImpl) FindAllExpanse(ctx context.Context, tx
 			&expense.Description,
 			&expense.Amount,
 			&expense.CreatedAt,
+			&expense.Version,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan expense row for userId=%d: %w", userId, err)
@@ -172,3 +209,115 @@ func (repository *expenseRepositoryImpl) FindAllExpanse(ctx context.Context, tx
 
 	return expenses, nil
 }
+
+// escapeLike escapes the LIKE wildcards so user input is matched literally.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?,", n), ",")
+}
+
+// compileFilterNode turns a parsed `q` expression into a SQL condition. Only
+// known fields and operators are emitted; every value goes through args.
+func compileFilterNode(node domain.FilterNode, userId int, args *[]interface{}) (string, error) {
+	switch node.Kind {
+	case domain.FilterAnd, domain.FilterOr:
+		joiner := " AND "
+		if node.Kind == domain.FilterOr {
+			joiner = " OR "
+		}
+		parts := make([]string, 0, len(node.Children))
+		for _, child := range node.Children {
+			part, err := compileFilterNode(child, userId, args)
+			if err != nil {
+				return "", err
+			}
+			parts = append(parts, part)
+		}
+		return "(" + strings.Join(parts, joiner) + ")", nil
+
+	case domain.FilterNot:
+		if len(node.Children) != 1 {
+			return "", fmt.Errorf("NOT expects exactly one operand")
+		}
+		part, err := compileFilterNode(node.Children[0], userId, args)
+		if err != nil {
+			return "", err
+		}
+		return "(NOT " + part + ")", nil
+
+	case domain.FilterCompare:
+		return compileFilterComparison(node, userId, args)
+	}
+
+	return "", fmt.Errorf("unknown filter node kind %d", node.Kind)
+}
+
+func compileFilterComparison(node domain.FilterNode, userId int, args *[]interface{}) (string, error) {
+	sqlOperators := map[string]string{
+		">": ">", ">=": ">=", "<": "<", "<=": "<=", "=": "=", "!=": "<>",
+	}
+
+	switch node.Field {
+	case "amount", "category_id":
+		op, ok := sqlOperators[node.Operator]
+		if !ok {
+			return "", fmt.Errorf("operator %q not supported for %s", node.Operator, node.Field)
+		}
+		*args = append(*args, node.Value)
+		return node.Field + " " + op + " ?", nil
+
+	case "category":
+		*args = append(*args, userId, node.Value)
+		subquery := "category_id IN (SELECT id FROM categories WHERE user_id = ? AND name = ? AND deleted_at IS NULL)"
+		switch node.Operator {
+		case ":", "=":
+			return subquery, nil
+		case "!=":
+			return "NOT " + subquery, nil
+		}
+
+	case "description":
+		switch node.Operator {
+		case ":":
+			value, _ := node.Value.(string)
+			*args = append(*args, "%"+escapeLike(value)+"%")
+			return "description LIKE ?", nil
+		case "=":
+			*args = append(*args, node.Value)
+			return "description = ?", nil
+		case "!=":
+			*args = append(*args, node.Value)
+			return "COALESCE(description, '') <> ?", nil
+		}
+
+	case "date":
+		day, ok := node.Value.(time.Time)
+		if !ok {
+			return "", fmt.Errorf("date value must be a time")
+		}
+		start := day.UTC().Format("2006-01-02 15:04:05")
+		end := day.UTC().Add(24 * time.Hour).Format("2006-01-02 15:04:05")
+		switch node.Operator {
+		case "=":
+			*args = append(*args, start, end)
+			return "(created_at >= ? AND created_at < ?)", nil
+		case ">":
+			*args = append(*args, end)
+			return "created_at >= ?", nil
+		case ">=":
+			*args = append(*args, start)
+			return "created_at >= ?", nil
+		case "<":
+			*args = append(*args, start)
+			return "created_at < ?", nil
+		case "<=":
+			*args = append(*args, end)
+			return "created_at < ?", nil
+		}
+	}
+
+	return "", fmt.Errorf("unsupported filter %s%s", node.Field, node.Operator)
+}
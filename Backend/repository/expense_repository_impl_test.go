@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ryhnfhrza/Expense-Tracker/helper"
+	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
+)
+
+const categorySubquery = "category_id IN (SELECT id FROM categories WHERE user_id = ? AND name = ? AND deleted_at IS NULL)"
+
+func TestCompileFilterNode(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		tz       string
+		wantSQL  string
+		wantArgs []interface{}
+	}{
+		{"amount", "amount>100", "", "amount > ?", []interface{}{100.0}},
+		{"not-equal becomes <>", "amount!=5", "", "amount <> ?", []interface{}{5.0}},
+		{"category_id", "category_id=3", "", "category_id = ?", []interface{}{3}},
+		{"category is scoped to the user", "category:food", "", categorySubquery, []interface{}{7, "food"}},
+		{"category exclusion", "category!=food", "", "NOT " + categorySubquery, []interface{}{7, "food"}},
+		{"description contains escapes wildcards", `description:"50%_off"`, "", "description LIKE ?", []interface{}{`%50\%\_off%`}},
+		{"description equals", "description=rent", "", "description = ?", []interface{}{"rent"}},
+		{"description not-equal keeps NULLs", "description!=rent", "", "COALESCE(description, '') <> ?", []interface{}{"rent"}},
+		{"date equals covers the day", "date=2024-03-15", "", "(created_at >= ? AND created_at < ?)", []interface{}{"2024-03-15 00:00:00", "2024-03-16 00:00:00"}},
+		{"date after starts the next day", "date>2024-03-15", "", "created_at >= ?", []interface{}{"2024-03-16 00:00:00"}},
+		{"date from includes the day", "date>=2024-03-15", "", "created_at >= ?", []interface{}{"2024-03-15 00:00:00"}},
+		{"date before excludes the day", "date<2024-03-15", "", "created_at < ?", []interface{}{"2024-03-15 00:00:00"}},
+		{"date until includes the day", "date<=2024-03-15", "", "created_at < ?", []interface{}{"2024-03-16 00:00:00"}},
+		{"date day in the user's time zone", "date=2024-03-15", "Asia/Jakarta", "(created_at >= ? AND created_at < ?)", []interface{}{"2024-03-14 17:00:00", "2024-03-15 17:00:00"}},
+		{"date until in the user's time zone", "date<=2024-03-15", "Asia/Jakarta", "created_at < ?", []interface{}{"2024-03-15 17:00:00"}},
+		{
+			"groups keep their precedence",
+			"amount>100 AND (category:food OR NOT description:tip)",
+			"",
+			"(amount > ? AND (" + categorySubquery + " OR (NOT description LIKE ?)))",
+			[]interface{}{100.0, 7, "food", "%tip%"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			node, err := helper.ParseFilterExpression(tt.input, tt.tz)
+			if err != nil {
+				t.Fatalf("ParseFilterExpression(%q): %v", tt.input, err)
+			}
+
+			var args []interface{}
+			sql, err := compileFilterNode(*node, 7, &args)
+			if err != nil {
+				t.Fatalf("compileFilterNode(%q): %v", tt.input, err)
+			}
+			if sql != tt.wantSQL {
+				t.Errorf("compileFilterNode(%q) = %q, want %q", tt.input, sql, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("compileFilterNode(%q) args = %#v, want %#v", tt.input, args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+// TestCompileFilterComparisonRejects covers nodes the parser never builds, so
+// a parser bug cannot reach the query with an unchecked field or operator.
+func TestCompileFilterComparisonRejects(t *testing.T) {
+	tests := []struct {
+		name string
+		node domain.FilterNode
+	}{
+		{"unknown field", domain.FilterNode{Kind: domain.FilterCompare, Field: "user_id", Operator: "=", Value: 1}},
+		{"contains on amount", domain.FilterNode{Kind: domain.FilterCompare, Field: "amount", Operator: ":", Value: 1.0}},
+		{"greater-than on category", domain.FilterNode{Kind: domain.FilterCompare, Field: "category", Operator: ">", Value: "food"}},
+		{"greater-than on description", domain.FilterNode{Kind: domain.FilterCompare, Field: "description", Operator: ">", Value: "a"}},
+		{"not-equal on date", domain.FilterNode{Kind: domain.FilterCompare, Field: "date", Operator: "!=", Value: time.Now()}},
+		{"date that is not a time", domain.FilterNode{Kind: domain.FilterCompare, Field: "date", Operator: "=", Value: "2024-03-15"}},
+		{"raw SQL as operator", domain.FilterNode{Kind: domain.FilterCompare, Field: "amount", Operator: "= 1 OR 1 =", Value: 1.0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var args []interface{}
+			if sql, err := compileFilterComparison(tt.node, 7, &args); err == nil {
+				t.Fatalf("compileFilterComparison(%+v) = %q, want an error", tt.node, sql)
+			}
+		})
+	}
+}
+
+func TestCompileFilterNodeRejectsMalformedNot(t *testing.T) {
+	node := domain.FilterNode{Kind: domain.FilterNot}
+
+	var args []interface{}
+	_, err := compileFilterNode(node, 7, &args)
+	if err == nil || !strings.Contains(err.Error(), "NOT expects exactly one operand") {
+		t.Fatalf("compileFilterNode error = %v, want the missing operand refused", err)
+	}
+}
@@ -0,0 +1,12 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
+)
+
+type ExpenseSearchRepository interface {
+	SearchExpense(ctx context.Context, tx *sql.Tx, userId int, query domain.ExpenseSearchQuery) ([]domain.ExpenseSearchResult, int, error)
+}
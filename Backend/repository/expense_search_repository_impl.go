@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
+)
+
+type expenseSearchRepositoryImpl struct{}
+
+func NewExpenseSearchRepository() ExpenseSearchRepository {
+	return &expenseSearchRepositoryImpl{}
+}
+
+func (repository *expenseSearchRepositoryImpl) SearchExpense(ctx context.Context, tx *sql.Tx, userId int, query domain.ExpenseSearchQuery) ([]domain.ExpenseSearchResult, int, error) {
+	against := toBooleanModeQuery(query.Terms)
+
+	where := `
+        FROM expenses e
+        JOIN categories c ON c.id = e.category_id
+        WHERE e.user_id = ? AND e.deleted_at IS NULL
+          AND (MATCH(e.description) AGAINST (? IN BOOLEAN MODE)
+            OR MATCH(c.name) AGAINST (? IN BOOLEAN MODE))
+    `
+
+	var total int
+	err := tx.QueryRowContext(ctx, "SELECT COUNT(*)"+where, userId, against, against).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count search results for userId=%d: %w", userId, err)
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+        SELECT
+            e.id,
+            e.category_id,
+            c.name,
+            e.description,
+            e.amount,
+            e.created_at,
+            MATCH(e.description) AGAINST (? IN BOOLEAN MODE) + MATCH(c.name) AGAINST (? IN BOOLEAN MODE) AS score
+    `+where+`
+        ORDER BY score DESC, e.created_at DESC
+        LIMIT ? OFFSET ?
+    `, against, against, userId, against, against, query.Limit, query.Offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to search expenses for userId=%d: %w", userId, err)
+	}
+	defer rows.Close()
+
+	results := []domain.ExpenseSearchResult{}
+	for rows.Next() {
+		var (
+			result      domain.ExpenseSearchResult
+			description sql.NullString
+		)
+		err := rows.Scan(
+			&result.Expense.Id,
+			&result.Expense.CategoryId,
+			&result.CategoryName,
+			&description,
+			&result.Expense.Amount,
+			&result.Expense.CreatedAt,
+			&result.Score,
+		)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan search result for userId=%d: %w", userId, err)
+		}
+		result.Expense.Description = description.String
+		result.Expense.UserId = userId
+		results = append(results, result)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("rows iteration error for userId=%d: %w", userId, err)
+	}
+
+	return results, total, nil
+}
+
+// toBooleanModeQuery compiles parsed terms into a MATCH ... IN BOOLEAN MODE
+// expression. Terms are optional so results are ranked by how many of them
+// match instead of requiring all of them in the same column.
+func toBooleanModeQuery(terms []domain.SearchTerm) string {
+	parts := make([]string, 0, len(terms))
+	for _, term := range terms {
+		switch {
+		case term.Phrase:
+			parts = append(parts, `"`+term.Text+`"`)
+		case term.Prefix:
+			parts = append(parts, term.Text+"*")
+		default:
+			parts = append(parts, term.Text)
+		}
+	}
+	return strings.Join(parts, " ")
+}
@@ -3,10 +3,12 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
 )
 
 type ExpenseSummaryRepository interface {
 	GetSummaryDetails(ctx context.Context, tx *sql.Tx, userId int, filter domain.SummaryFilter) ([]domain.SummaryDetail, error)
+	GetSummaryRecords(ctx context.Context, tx *sql.Tx, userId int, from, to time.Time) ([]domain.SummaryRecord, error)
 }
@@ -25,7 +25,7 @@ func (repository *expenseSummeryImpl) GetSummaryDetails(ctx context.Context, tx
             e.created_at
         FROM expenses e
         JOIN categories c ON c.id = e.category_id
-        WHERE e.user_id = ?
+        WHERE e.user_id = ? AND e.deleted_at IS NULL
     `
 
 	args := []interface{}{userId}
@@ -34,21 +34,17 @@ func (repository *expenseSummeryImpl) GetSummaryDetails(ctx context.Context, tx
 		query += " AND e.category_id = ?"
 		args = append(args, *filter.CategoryId)
 	}
-	if filter.Day != nil {
-		query += " AND DAY(e.created_at) = ?"
-		args = append(args, *filter.Day)
+	if filter.From != nil {
+		query += " AND e.created_at >= ?"
+		args = append(args, filter.From.UTC())
 	}
-	if filter.Month != nil {
-		query += " AND MONTH(e.created_at) = ?"
-		args = append(args, *filter.Month)
-	}
-	if filter.Year != nil {
-		query += " AND YEAR(e.created_at) = ?"
-		args = append(args, *filter.Year)
+	if filter.To != nil {
+		query += " AND e.created_at < ?"
+		args = append(args, filter.To.UTC())
 	}
 	if filter.Description != nil {
 		query += " AND e.description LIKE ?"
-		args = append(args, "%"+*filter.Description+"%")
+		args = append(args, "%"+escapeLike(*filter.Description)+"%")
 	}
 
 	query += " ORDER BY e.category_id, e.created_at ASC"
@@ -84,7 +80,7 @@ func (repository *expenseSummeryImpl) GetSummaryDetails(ctx context.Context, tx
 
 		record := domain.ExpenseRecord{
 			Amount:      amount,
-			Date:        createdAt.Format("2006-01-02"),
+			CreatedAt:   createdAt,
 			Description: "",
 		}
 		if description.Valid {
@@ -102,3 +98,48 @@ func (repository *expenseSummeryImpl) GetSummaryDetails(ctx context.Context, tx
 
 	return result, nil
 }
+
+// GetSummaryRecords returns the same per-category rows as GetSummaryDetails
+// for created_at in [from, to), unaggregated, so callers can bucket them by
+// the user's local calendar instead of UTC.
+func (repository *expenseSummeryImpl) GetSummaryRecords(ctx context.Context, tx *sql.Tx, userId int, from, to time.Time) ([]domain.SummaryRecord, error) {
+	query := `
+        SELECT
+            e.category_id,
+            c.name AS category_name,
+            e.description,
+            e.amount,
+            e.created_at
+        FROM expenses e
+        JOIN categories c ON c.id = e.category_id
+        WHERE e.user_id = ? AND e.deleted_at IS NULL AND e.created_at >= ? AND e.created_at < ?
+        ORDER BY e.created_at ASC
+    `
+
+	rows, err := tx.QueryContext(ctx, query, userId, from.UTC(), to.UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	records := []domain.SummaryRecord{}
+	for rows.Next() {
+		var (
+			record      domain.SummaryRecord
+			description sql.NullString
+		)
+
+		if err := rows.Scan(&record.CategoryId, &record.CategoryName, &description, &record.Amount, &record.CreatedAt); err != nil {
+			return nil, err
+		}
+		record.Description = description.String
+
+		records = append(records, record)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
+)
+
+type IdempotencyRedisRepository interface {
+	// ReserveIdempotency stores record under key unless the key is taken,
+	// and reports whether it did.
+	ReserveIdempotency(ctx context.Context, key string, record domain.IdempotencyRecord, ttl time.Duration) (bool, error)
+	SaveIdempotency(ctx context.Context, key string, record domain.IdempotencyRecord, ttl time.Duration) error
+	GetIdempotency(ctx context.Context, key string) (*domain.IdempotencyRecord, error)
+	DeleteIdempotency(ctx context.Context, key string) error
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
+)
+
+type idempotencyRedisImpl struct {
+	Client *redis.Client
+}
+
+func NewIdempotencyRedisRepository(redisClient *redis.Client) IdempotencyRedisRepository {
+	return &idempotencyRedisImpl{
+		Client: redisClient,
+	}
+}
+
+func (idempotencyRedisRepository *idempotencyRedisImpl) ReserveIdempotency(ctx context.Context, key string, record domain.IdempotencyRecord, ttl time.Duration) (bool, error) {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal idempotency record: %v", err)
+	}
+
+	reserved, err := idempotencyRedisRepository.Client.SetNX(ctx, fmt.Sprintf("idempotency:%s", key), data, ttl).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to reserve idempotency key in redis: %v", err)
+	}
+
+	return reserved, nil
+}
+
+func (idempotencyRedisRepository *idempotencyRedisImpl) SaveIdempotency(ctx context.Context, key string, record domain.IdempotencyRecord, ttl time.Duration) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal idempotency record: %v", err)
+	}
+
+	if err := idempotencyRedisRepository.Client.Set(ctx, fmt.Sprintf("idempotency:%s", key), data, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to save idempotency record to redis: %v", err)
+	}
+
+	return nil
+}
+
+// GetIdempotency returns nil without an error when the key is unknown or
+// expired.
+func (idempotencyRedisRepository *idempotencyRedisImpl) GetIdempotency(ctx context.Context, key string) (*domain.IdempotencyRecord, error) {
+	val, err := idempotencyRedisRepository.Client.Get(ctx, fmt.Sprintf("idempotency:%s", key)).Result()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get idempotency record from redis: %v", err)
+	}
+
+	var record domain.IdempotencyRecord
+	if err := json.Unmarshal([]byte(val), &record); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal idempotency record: %v", err)
+	}
+
+	return &record, nil
+}
+
+func (idempotencyRedisRepository *idempotencyRedisImpl) DeleteIdempotency(ctx context.Context, key string) error {
+	if err := idempotencyRedisRepository.Client.Del(ctx, fmt.Sprintf("idempotency:%s", key)).Err(); err != nil {
+		return fmt.Errorf("failed to delete idempotency record: %v", err)
+	}
+	return nil
+}
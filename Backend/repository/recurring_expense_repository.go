@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
+)
+
+type RecurringExpenseRepository interface {
+	SaveRecurring(ctx context.Context, tx *sql.Tx, recurring *domain.RecurringExpense) error
+	DeleteRecurring(ctx context.Context, tx *sql.Tx, recurringId, userId int) error
+	UpdateNextRun(ctx context.Context, tx *sql.Tx, recurringId int, nextRunAt time.Time) error
+	FindRecurringById(ctx context.Context, tx *sql.Tx, recurringId, userId int) (*domain.RecurringExpense, error)
+	FindAllRecurring(ctx context.Context, tx *sql.Tx, userId int) ([]domain.RecurringExpense, error)
+	FindDueRecurring(ctx context.Context, tx *sql.Tx, now time.Time, limit int) ([]domain.RecurringExpense, error)
+}
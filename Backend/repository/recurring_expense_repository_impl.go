@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
+)
+
+type recurringExpenseRepositoryImpl struct{}
+
+func NewRecurringExpenseRepository() RecurringExpenseRepository {
+	return &recurringExpenseRepositoryImpl{}
+}
+
+const recurringExpenseColumns = "id,user_id,category_id,description,amount,cadence,next_run_at,anchor_day,source_key,created_at"
+
+func (repository *recurringExpenseRepositoryImpl) SaveRecurring(ctx context.Context, tx *sql.Tx, recurring *domain.RecurringExpense) error {
+	query := "insert into recurring_expenses(user_id,category_id,description,amount,cadence,next_run_at,anchor_day,source_key,created_at) values(?,?,?,?,?,?,?,?,?)"
+	result, err := tx.ExecContext(ctx, query, recurring.UserId, recurring.CategoryId, recurring.Description, recurring.Amount, recurring.Cadence, recurring.NextRunAt, recurring.AnchorDay, recurring.SourceKey, recurring.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to insert recurring expense (sourceKey=%s, userId=%d): %w", recurring.SourceKey, recurring.UserId, err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert id for recurring expense (userId=%d): %w", recurring.UserId, err)
+	}
+
+	recurring.Id = int(id)
+
+	return nil
+}
+
+func (repository *recurringExpenseRepositoryImpl) DeleteRecurring(ctx context.Context, tx *sql.Tx, recurringId, userId int) error {
+	query := "delete from recurring_expenses where id = ? and user_id = ?"
+	_, err := tx.ExecContext(ctx, query, recurringId, userId)
+	if err != nil {
+		return fmt.Errorf("failed to delete recurring expense (id=%d, userId=%d): %w", recurringId, userId, err)
+	}
+
+	return nil
+}
+
+func (repository *recurringExpenseRepositoryImpl) UpdateNextRun(ctx context.Context, tx *sql.Tx, recurringId int, nextRunAt time.Time) error {
+	query := "update recurring_expenses set next_run_at = ? where id = ?"
+	_, err := tx.ExecContext(ctx, query, nextRunAt, recurringId)
+	if err != nil {
+		return fmt.Errorf("failed to update next run of recurring expense (id=%d): %w", recurringId, err)
+	}
+
+	return nil
+}
+
+func (repository *recurringExpenseRepositoryImpl) FindRecurringById(ctx context.Context, tx *sql.Tx, recurringId, userId int) (*domain.RecurringExpense, error) {
+	query := "select " + recurringExpenseColumns + " from recurring_expenses where id = ? and user_id = ? and " + categoryNotTrashed
+	recurring, err := scanRecurringExpense(tx.QueryRowContext(ctx, query, recurringId, userId))
+	if err != nil {
+		return nil, fmt.Errorf("recurring expense not found (id=%d, userId=%d): %w", recurringId, userId, err)
+	}
+
+	return recurring, nil
+}
+
+func (repository *recurringExpenseRepositoryImpl) FindAllRecurring(ctx context.Context, tx *sql.Tx, userId int) ([]domain.RecurringExpense, error) {
+	query := "select " + recurringExpenseColumns + " from recurring_expenses where user_id = ? and " + categoryNotTrashed + " order by next_run_at asc, id asc"
+	return queryRecurringExpenses(ctx, tx, query, userId)
+}
+
+// FindDueRecurring locks up to limit rules due at now across all users. Rows
+// locked by another instance running the job are skipped.
+func (repository *recurringExpenseRepositoryImpl) FindDueRecurring(ctx context.Context, tx *sql.Tx, now time.Time, limit int) ([]domain.RecurringExpense, error) {
+	query := "select " + recurringExpenseColumns + " from recurring_expenses where next_run_at <= ? and " + categoryNotTrashed + " order by next_run_at asc limit ? for update skip locked"
+	return queryRecurringExpenses(ctx, tx, query, now.UTC(), limit)
+}
+
+func queryRecurringExpenses(ctx context.Context, tx *sql.Tx, query string, args ...any) ([]domain.RecurringExpense, error) {
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query recurring expenses: %w", err)
+	}
+	defer rows.Close()
+
+	recurring := []domain.RecurringExpense{}
+	for rows.Next() {
+		r, err := scanRecurringExpense(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan recurring expense row: %w", err)
+		}
+		recurring = append(recurring, *r)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	return recurring, nil
+}
+
+func scanRecurringExpense(row rowScanner) (*domain.RecurringExpense, error) {
+	var recurring domain.RecurringExpense
+	err := row.Scan(
+		&recurring.Id,
+		&recurring.UserId,
+		&recurring.CategoryId,
+		&recurring.Description,
+		&recurring.Amount,
+		&recurring.Cadence,
+		&recurring.NextRunAt,
+		&recurring.AnchorDay,
+		&recurring.SourceKey,
+		&recurring.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &recurring, nil
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
+)
+
+type SavedViewRepository interface {
+	SaveView(ctx context.Context, tx *sql.Tx, view *domain.SavedView) error
+	UpdateView(ctx context.Context, tx *sql.Tx, view *domain.SavedView) error
+	DeleteView(ctx context.Context, tx *sql.Tx, viewId, userId int) error
+	FindViewById(ctx context.Context, tx *sql.Tx, viewId, userId int) (*domain.SavedView, error)
+	FindViewByName(ctx context.Context, tx *sql.Tx, name string, userId int) (*domain.SavedView, error)
+	FindDefaultView(ctx context.Context, tx *sql.Tx, kind string, userId int) (*domain.SavedView, error)
+	FindAllView(ctx context.Context, tx *sql.Tx, userId int) ([]domain.SavedView, error)
+	ClearDefaultView(ctx context.Context, tx *sql.Tx, kind string, userId int) error
+}
@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
+)
+
+type savedViewRepositoryImpl struct{}
+
+func NewSavedViewRepository() SavedViewRepository {
+	return &savedViewRepositoryImpl{}
+}
+
+const savedViewColumns = "id,user_id,name,kind,filter,sort_by,sort_order,is_default,created_at,updated_at"
+
+func (repository *savedViewRepositoryImpl) SaveView(ctx context.Context, tx *sql.Tx, view *domain.SavedView) error {
+	filter, err := json.Marshal(view.Filter)
+	if err != nil {
+		return fmt.Errorf("failed to marshal saved view filter (name=%s, userId=%d): %w", view.Name, view.UserId, err)
+	}
+
+	query := "insert into saved_views(user_id,name,kind,filter,sort_by,sort_order,is_default,created_at,updated_at) values(?,?,?,?,?,?,?,?,?)"
+	result, err := tx.ExecContext(ctx, query, view.UserId, view.Name, view.Kind, filter, view.SortBy, view.Order, view.IsDefault, view.CreatedAt, view.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to insert saved view (name=%s, userId=%d): %w", view.Name, view.UserId, err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert id for saved view (name=%s, userId=%d): %w", view.Name, view.UserId, err)
+	}
+
+	view.Id = int(id)
+
+	return nil
+}
+
+func (repository *savedViewRepositoryImpl) UpdateView(ctx context.Context, tx *sql.Tx, view *domain.SavedView) error {
+	filter, err := json.Marshal(view.Filter)
+	if err != nil {
+		return fmt.Errorf("failed to marshal saved view filter (id=%d, userId=%d): %w", view.Id, view.UserId, err)
+	}
+
+	query := "update saved_views set name = ?, kind = ?, filter = ?, sort_by = ?, sort_order = ?, is_default = ?, updated_at = ? where id = ? and user_id = ?"
+	_, err = tx.ExecContext(ctx, query, view.Name, view.Kind, filter, view.SortBy, view.Order, view.IsDefault, view.UpdatedAt, view.Id, view.UserId)
+	if err != nil {
+		return fmt.Errorf("failed to update saved view (id=%d, userId=%d): %w", view.Id, view.UserId, err)
+	}
+
+	return nil
+}
+
+func (repository *savedViewRepositoryImpl) DeleteView(ctx context.Context, tx *sql.Tx, viewId, userId int) error {
+	query := "delete from saved_views where id = ? and user_id = ?"
+	_, err := tx.ExecContext(ctx, query, viewId, userId)
+	if err != nil {
+		return fmt.Errorf("failed to delete saved view (id=%d, userId=%d): %w", viewId, userId, err)
+	}
+
+	return nil
+}
+
+func (repository *savedViewRepositoryImpl) FindViewById(ctx context.Context, tx *sql.Tx, viewId, userId int) (*domain.SavedView, error) {
+	query := "select " + savedViewColumns + " from saved_views where id = ? and user_id = ?"
+	view, err := scanSavedView(tx.QueryRowContext(ctx, query, viewId, userId))
+	if err != nil {
+		return nil, fmt.Errorf("saved view not found (id=%d, userId=%d): %w", viewId, userId, err)
+	}
+
+	return view, nil
+}
+
+func (repository *savedViewRepositoryImpl) FindViewByName(ctx context.Context, tx *sql.Tx, name string, userId int) (*domain.SavedView, error) {
+	query := "select " + savedViewColumns + " from saved_views where name = ? and user_id = ?"
+	view, err := scanSavedView(tx.QueryRowContext(ctx, query, name, userId))
+	if err != nil {
+		return nil, fmt.Errorf("saved view not found (name=%s, userId=%d): %w", name, userId, err)
+	}
+
+	return view, nil
+}
+
+func (repository *savedViewRepositoryImpl) FindDefaultView(ctx context.Context, tx *sql.Tx, kind string, userId int) (*domain.SavedView, error) {
+	query := "select " + savedViewColumns + " from saved_views where kind = ? and user_id = ? and is_default = 1"
+	view, err := scanSavedView(tx.QueryRowContext(ctx, query, kind, userId))
+	if err != nil {
+		return nil, fmt.Errorf("default saved view not found (kind=%s, userId=%d): %w", kind, userId, err)
+	}
+
+	return view, nil
+}
+
+func (repository *savedViewRepositoryImpl) FindAllView(ctx context.Context, tx *sql.Tx, userId int) ([]domain.SavedView, error) {
+	query := "select " + savedViewColumns + " from saved_views where user_id = ? order by is_default desc, name asc"
+	rows, err := tx.QueryContext(ctx, query, userId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query saved views for userId=%d: %w", userId, err)
+	}
+	defer rows.Close()
+
+	views := []domain.SavedView{}
+	for rows.Next() {
+		view, err := scanSavedView(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan saved view row for userId=%d: %w", userId, err)
+		}
+		views = append(views, *view)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error for userId=%d: %w", userId, err)
+	}
+
+	return views, nil
+}
+
+func (repository *savedViewRepositoryImpl) ClearDefaultView(ctx context.Context, tx *sql.Tx, kind string, userId int) error {
+	query := "update saved_views set is_default = 0 where kind = ? and user_id = ?"
+	_, err := tx.ExecContext(ctx, query, kind, userId)
+	if err != nil {
+		return fmt.Errorf("failed to clear default saved view (kind=%s, userId=%d): %w", kind, userId, err)
+	}
+
+	return nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanSavedView(row rowScanner) (*domain.SavedView, error) {
+	var (
+		view   domain.SavedView
+		filter []byte
+	)
+
+	err := row.Scan(
+		&view.Id,
+		&view.UserId,
+		&view.Name,
+		&view.Kind,
+		&filter,
+		&view.SortBy,
+		&view.Order,
+		&view.IsDefault,
+		&view.CreatedAt,
+		&view.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	view.Filter = map[string]string{}
+	if err := json.Unmarshal(filter, &view.Filter); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal saved view filter: %w", err)
+	}
+
+	return &view, nil
+}
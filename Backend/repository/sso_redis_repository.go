@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
+)
+
+type SsoRedisRepository interface {
+	SaveSsoState(ctx context.Context, state string, ttl time.Duration, ssoState domain.SsoState) error
+	TakeSsoState(ctx context.Context, state string) (*domain.SsoState, error)
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
+)
+
+type ssoRedisImpl struct {
+	Client *redis.Client
+}
+
+func NewSsoRedisRepository(redisClient *redis.Client) SsoRedisRepository {
+	return &ssoRedisImpl{
+		Client: redisClient,
+	}
+}
+
+func (ssoRedisRepository *ssoRedisImpl) SaveSsoState(ctx context.Context, state string, ttl time.Duration, ssoState domain.SsoState) error {
+	key := fmt.Sprintf("sso_state:%s", state)
+
+	data, err := json.Marshal(ssoState)
+	if err != nil {
+		return fmt.Errorf("failed to marshal sso state: %v", err)
+	}
+
+	if err := ssoRedisRepository.Client.Set(ctx, key, data, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to save sso state to redis: %v", err)
+	}
+
+	return nil
+}
+
+// TakeSsoState returns the state and deletes it, so each one finishes at
+// most one sign-in. It returns nil without an error when the state is
+// unknown or expired.
+func (ssoRedisRepository *ssoRedisImpl) TakeSsoState(ctx context.Context, state string) (*domain.SsoState, error) {
+	key := fmt.Sprintf("sso_state:%s", state)
+	val, err := ssoRedisRepository.Client.GetDel(ctx, key).Result()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sso state from redis: %v", err)
+	}
+
+	var ssoState domain.SsoState
+	if err := json.Unmarshal([]byte(val), &ssoState); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal sso state: %v", err)
+	}
+
+	return &ssoState, nil
+}
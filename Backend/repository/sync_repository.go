@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
+)
+
+type SyncRepository interface {
+	// FindSyncCursor returns the user's latest sync sequence number, or 0
+	// when nothing was synced yet.
+	FindSyncCursor(ctx context.Context, tx *sql.Tx, userId int) (int64, error)
+	// FindSyncChanges returns expense and category entries with a sync
+	// sequence number after since, oldest first.
+	FindSyncChanges(ctx context.Context, tx *sql.Tx, userId int, since int64, limit int) ([]domain.SyncChange, error)
+	// FindSyncExpenses returns the given expenses, trashed ones included,
+	// or every live expense when ids is nil. Purged expenses are missing.
+	FindSyncExpenses(ctx context.Context, tx *sql.Tx, userId int, ids []int) ([]domain.SyncedExpense, error)
+	FindSyncCategories(ctx context.Context, tx *sql.Tx, userId int, ids []int) ([]domain.SyncedCategory, error)
+	FindExpenseByClientId(ctx context.Context, tx *sql.Tx, userId int, clientId string) (*domain.SyncedExpense, error)
+	FindCategoryByClientId(ctx context.Context, tx *sql.Tx, userId int, clientId string) (*domain.SyncedCategory, error)
+	SaveExpenseClientId(ctx context.Context, tx *sql.Tx, userId, expenseId int, clientId string) error
+	SaveCategoryClientId(ctx context.Context, tx *sql.Tx, userId, categoryId int, clientId string) error
+}
@@ -0,0 +1,209 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
+)
+
+type syncRepositoryImpl struct{}
+
+func NewSyncRepository() SyncRepository {
+	return &syncRepositoryImpl{}
+}
+
+const syncedExpenseQuery = `select e.id,e.category_id,coalesce(c.name,''),e.description,e.amount,e.created_at,e.version,coalesce(e.client_id,''),e.deleted_at
+	from expenses e left join categories c on c.id = e.category_id
+	where e.user_id = ?`
+
+const syncedCategoryQuery = `select c.id,c.name,coalesce(c.client_id,''),c.deleted_at
+	from categories c
+	where c.user_id = ?`
+
+func (repository *syncRepositoryImpl) FindSyncCursor(ctx context.Context, tx *sql.Tx, userId int) (int64, error) {
+	var cursor int64
+	err := tx.QueryRowContext(ctx, "select sync_seq from users where id = ?", userId).Scan(&cursor)
+	if err != nil {
+		return 0, fmt.Errorf("failed to query sync cursor for userId=%d: %w", userId, err)
+	}
+
+	return cursor, nil
+}
+
+func (repository *syncRepositoryImpl) FindSyncChanges(ctx context.Context, tx *sql.Tx, userId int, since int64, limit int) ([]domain.SyncChange, error) {
+	query := `select sync_seq,entity_type,entity_id from audit_log
+		where user_id = ? and sync_seq > ?
+		order by sync_seq limit ?`
+	rows, err := tx.QueryContext(ctx, query, userId, since, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query sync changes for userId=%d: %w", userId, err)
+	}
+	defer rows.Close()
+
+	changes := []domain.SyncChange{}
+	for rows.Next() {
+		var change domain.SyncChange
+		if err := rows.Scan(&change.Seq, &change.EntityType, &change.EntityId); err != nil {
+			return nil, fmt.Errorf("failed to scan sync change row for userId=%d: %w", userId, err)
+		}
+		changes = append(changes, change)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error for userId=%d: %w", userId, err)
+	}
+
+	return changes, nil
+}
+
+func (repository *syncRepositoryImpl) FindSyncExpenses(ctx context.Context, tx *sql.Tx, userId int, ids []int) ([]domain.SyncedExpense, error) {
+	query := syncedExpenseQuery + " and e.deleted_at is null"
+	args := []any{userId}
+	if ids != nil {
+		if len(ids) == 0 {
+			return []domain.SyncedExpense{}, nil
+		}
+		query = syncedExpenseQuery + " and e.id in (" + placeholders(len(ids)) + ")"
+		for _, id := range ids {
+			args = append(args, id)
+		}
+	}
+
+	rows, err := tx.QueryContext(ctx, query+" order by e.id", args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query synced expenses for userId=%d: %w", userId, err)
+	}
+	defer rows.Close()
+
+	expenses := []domain.SyncedExpense{}
+	for rows.Next() {
+		expense, err := scanSyncedExpense(rows, userId)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan synced expense row for userId=%d: %w", userId, err)
+		}
+		expenses = append(expenses, *expense)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error for userId=%d: %w", userId, err)
+	}
+
+	return expenses, nil
+}
+
+func (repository *syncRepositoryImpl) FindSyncCategories(ctx context.Context, tx *sql.Tx, userId int, ids []int) ([]domain.SyncedCategory, error) {
+	query := syncedCategoryQuery + " and c.deleted_at is null"
+	args := []any{userId}
+	if ids != nil {
+		if len(ids) == 0 {
+			return []domain.SyncedCategory{}, nil
+		}
+		query = syncedCategoryQuery + " and c.id in (" + placeholders(len(ids)) + ")"
+		for _, id := range ids {
+			args = append(args, id)
+		}
+	}
+
+	rows, err := tx.QueryContext(ctx, query+" order by c.id", args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query synced categories for userId=%d: %w", userId, err)
+	}
+	defer rows.Close()
+
+	categories := []domain.SyncedCategory{}
+	for rows.Next() {
+		category, err := scanSyncedCategory(rows, userId)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan synced category row for userId=%d: %w", userId, err)
+		}
+		categories = append(categories, *category)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error for userId=%d: %w", userId, err)
+	}
+
+	return categories, nil
+}
+
+func (repository *syncRepositoryImpl) FindExpenseByClientId(ctx context.Context, tx *sql.Tx, userId int, clientId string) (*domain.SyncedExpense, error) {
+	expense, err := scanSyncedExpense(tx.QueryRowContext(ctx, syncedExpenseQuery+" and e.client_id = ?", userId, clientId), userId)
+	if err != nil {
+		return nil, fmt.Errorf("expense not found (clientId=%s, userId=%d): %w", clientId, userId, err)
+	}
+
+	return expense, nil
+}
+
+func (repository *syncRepositoryImpl) FindCategoryByClientId(ctx context.Context, tx *sql.Tx, userId int, clientId string) (*domain.SyncedCategory, error) {
+	category, err := scanSyncedCategory(tx.QueryRowContext(ctx, syncedCategoryQuery+" and c.client_id = ?", userId, clientId), userId)
+	if err != nil {
+		return nil, fmt.Errorf("category not found (clientId=%s, userId=%d): %w", clientId, userId, err)
+	}
+
+	return category, nil
+}
+
+func (repository *syncRepositoryImpl) SaveExpenseClientId(ctx context.Context, tx *sql.Tx, userId, expenseId int, clientId string) error {
+	query := "update expenses set client_id = ? where id = ? and user_id = ?"
+	if _, err := tx.ExecContext(ctx, query, clientId, expenseId, userId); err != nil {
+		return fmt.Errorf("failed to set client id of expense (id=%d, userId=%d): %w", expenseId, userId, err)
+	}
+	return nil
+}
+
+func (repository *syncRepositoryImpl) SaveCategoryClientId(ctx context.Context, tx *sql.Tx, userId, categoryId int, clientId string) error {
+	query := "update categories set client_id = ? where id = ? and user_id = ?"
+	if _, err := tx.ExecContext(ctx, query, clientId, categoryId, userId); err != nil {
+		return fmt.Errorf("failed to set client id of category (id=%d, userId=%d): %w", categoryId, userId, err)
+	}
+	return nil
+}
+
+func scanSyncedExpense(row rowScanner, userId int) (*domain.SyncedExpense, error) {
+	var (
+		synced      domain.SyncedExpense
+		description sql.NullString
+		deletedAt   sql.NullTime
+	)
+	err := row.Scan(
+		&synced.Expense.Id,
+		&synced.Expense.CategoryId,
+		&synced.CategoryName,
+		&description,
+		&synced.Expense.Amount,
+		&synced.Expense.CreatedAt,
+		&synced.Expense.Version,
+		&synced.ClientId,
+		&deletedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	synced.Expense.Description = description.String
+	synced.Expense.UserId = userId
+	if deletedAt.Valid {
+		synced.DeletedAt = &deletedAt.Time
+	}
+
+	return &synced, nil
+}
+
+func scanSyncedCategory(row rowScanner, userId int) (*domain.SyncedCategory, error) {
+	var (
+		synced    domain.SyncedCategory
+		deletedAt sql.NullTime
+	)
+	err := row.Scan(&synced.Category.Id, &synced.Category.Name, &synced.ClientId, &deletedAt)
+	if err != nil {
+		return nil, err
+	}
+	synced.Category.UserId = userId
+	if deletedAt.Valid {
+		synced.DeletedAt = &deletedAt.Time
+	}
+
+	return &synced, nil
+}
@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
+)
+
+type TrashRepository interface {
+	FindTrashedExpenses(ctx context.Context, tx *sql.Tx, userId int) ([]domain.TrashedExpense, error)
+	FindTrashedExpenseById(ctx context.Context, tx *sql.Tx, expenseId, userId int) (*domain.TrashedExpense, error)
+	FindTrashedCategories(ctx context.Context, tx *sql.Tx, userId int) ([]domain.TrashedCategory, error)
+	FindTrashedCategoryById(ctx context.Context, tx *sql.Tx, categoryId, userId int) (*domain.TrashedCategory, error)
+	RestoreExpense(ctx context.Context, tx *sql.Tx, expenseId, userId int) error
+	RestoreCategory(ctx context.Context, tx *sql.Tx, categoryId, userId int, deletedAt time.Time) error
+	PurgeExpense(ctx context.Context, tx *sql.Tx, expenseId, userId int) error
+	PurgeCategory(ctx context.Context, tx *sql.Tx, categoryId, userId int) error
+	FindExpiredTrash(ctx context.Context, tx *sql.Tx, deletedBefore time.Time, limit int) ([]domain.TrashItem, error)
+}
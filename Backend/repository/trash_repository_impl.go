@@ -0,0 +1,212 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
+)
+
+type trashRepositoryImpl struct{}
+
+func NewTrashRepository() TrashRepository {
+	return &trashRepositoryImpl{}
+}
+
+const trashedExpenseQuery = `select e.id,e.category_id,c.name,e.description,e.amount,e.created_at,e.deleted_at
+	from expenses e join categories c on c.id = e.category_id
+	where e.user_id = ? and e.deleted_at is not null`
+
+const trashedCategoryQuery = `select c.id,c.name,c.deleted_at,
+	(select count(*) from expenses e where e.category_id = c.id and e.deleted_at = c.deleted_at)
+	from categories c
+	where c.user_id = ? and c.deleted_at is not null`
+
+func (repository *trashRepositoryImpl) FindTrashedExpenses(ctx context.Context, tx *sql.Tx, userId int) ([]domain.TrashedExpense, error) {
+	rows, err := tx.QueryContext(ctx, trashedExpenseQuery+" order by e.deleted_at desc, e.id desc", userId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query trashed expenses for userId=%d: %w", userId, err)
+	}
+	defer rows.Close()
+
+	expenses := []domain.TrashedExpense{}
+	for rows.Next() {
+		expense, err := scanTrashedExpense(rows, userId)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan trashed expense row for userId=%d: %w", userId, err)
+		}
+		expenses = append(expenses, *expense)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error for userId=%d: %w", userId, err)
+	}
+
+	return expenses, nil
+}
+
+func (repository *trashRepositoryImpl) FindTrashedExpenseById(ctx context.Context, tx *sql.Tx, expenseId, userId int) (*domain.TrashedExpense, error) {
+	expense, err := scanTrashedExpense(tx.QueryRowContext(ctx, trashedExpenseQuery+" and e.id = ?", userId, expenseId), userId)
+	if err != nil {
+		return nil, fmt.Errorf("trashed expense not found (id=%d, userId=%d): %w", expenseId, userId, err)
+	}
+
+	return expense, nil
+}
+
+func (repository *trashRepositoryImpl) FindTrashedCategories(ctx context.Context, tx *sql.Tx, userId int) ([]domain.TrashedCategory, error) {
+	rows, err := tx.QueryContext(ctx, trashedCategoryQuery+" order by c.deleted_at desc, c.id desc", userId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query trashed categories for userId=%d: %w", userId, err)
+	}
+	defer rows.Close()
+
+	categories := []domain.TrashedCategory{}
+	for rows.Next() {
+		category, err := scanTrashedCategory(rows, userId)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan trashed category row for userId=%d: %w", userId, err)
+		}
+		categories = append(categories, *category)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error for userId=%d: %w", userId, err)
+	}
+
+	return categories, nil
+}
+
+func (repository *trashRepositoryImpl) FindTrashedCategoryById(ctx context.Context, tx *sql.Tx, categoryId, userId int) (*domain.TrashedCategory, error) {
+	category, err := scanTrashedCategory(tx.QueryRowContext(ctx, trashedCategoryQuery+" and c.id = ?", userId, categoryId), userId)
+	if err != nil {
+		return nil, fmt.Errorf("trashed category not found (id=%d, userId=%d): %w", categoryId, userId, err)
+	}
+
+	return category, nil
+}
+
+func (repository *trashRepositoryImpl) RestoreExpense(ctx context.Context, tx *sql.Tx, expenseId, userId int) error {
+	query := "update expenses set deleted_at = null where id = ? and user_id = ?"
+	_, err := tx.ExecContext(ctx, query, expenseId, userId)
+	if err != nil {
+		return fmt.Errorf("failed to restore expense (id=%d, userId=%d): %w", expenseId, userId, err)
+	}
+
+	return nil
+}
+
+// RestoreCategory restores the category and the expenses deleted together
+// with it, recognised by their identical deleted_at.
+func (repository *trashRepositoryImpl) RestoreCategory(ctx context.Context, tx *sql.Tx, categoryId, userId int, deletedAt time.Time) error {
+	query := "update categories set deleted_at = null where id = ? and user_id = ?"
+	_, err := tx.ExecContext(ctx, query, categoryId, userId)
+	if err != nil {
+		return fmt.Errorf("failed to restore category (id=%d, userId=%d): %w", categoryId, userId, err)
+	}
+
+	query = "update expenses set deleted_at = null where category_id = ? and user_id = ? and deleted_at = ?"
+	_, err = tx.ExecContext(ctx, query, categoryId, userId, deletedAt)
+	if err != nil {
+		return fmt.Errorf("failed to restore expenses of category (id=%d, userId=%d): %w", categoryId, userId, err)
+	}
+
+	return nil
+}
+
+func (repository *trashRepositoryImpl) PurgeExpense(ctx context.Context, tx *sql.Tx, expenseId, userId int) error {
+	query := "delete from expenses where id = ? and user_id = ? and deleted_at is not null"
+	_, err := tx.ExecContext(ctx, query, expenseId, userId)
+	if err != nil {
+		return fmt.Errorf("failed to purge expense (id=%d, userId=%d): %w", expenseId, userId, err)
+	}
+
+	return nil
+}
+
+// PurgeCategory deletes the category for good along with every expense still
+// pointing to it. Those are all in the trash, since deleting a category
+// trashes its expenses and a trashed category cannot be assigned.
+func (repository *trashRepositoryImpl) PurgeCategory(ctx context.Context, tx *sql.Tx, categoryId, userId int) error {
+	query := "delete from expenses where category_id = ? and user_id = ? and deleted_at is not null"
+	_, err := tx.ExecContext(ctx, query, categoryId, userId)
+	if err != nil {
+		return fmt.Errorf("failed to purge expenses of category (id=%d, userId=%d): %w", categoryId, userId, err)
+	}
+
+	query = "delete from categories where id = ? and user_id = ? and deleted_at is not null"
+	_, err = tx.ExecContext(ctx, query, categoryId, userId)
+	if err != nil {
+		return fmt.Errorf("failed to purge category (id=%d, userId=%d): %w", categoryId, userId, err)
+	}
+
+	return nil
+}
+
+// FindExpiredTrash returns up to limit expenses and categories of any user
+// deleted before deletedBefore, expenses first.
+func (repository *trashRepositoryImpl) FindExpiredTrash(ctx context.Context, tx *sql.Tx, deletedBefore time.Time, limit int) ([]domain.TrashItem, error) {
+	query := `(select user_id,'expense',id from expenses where deleted_at < ? order by deleted_at limit ?)
+		union all
+		(select user_id,'category',id from categories where deleted_at < ? order by deleted_at limit ?)`
+	rows, err := tx.QueryContext(ctx, query, deletedBefore.UTC(), limit, deletedBefore.UTC(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query expired trash: %w", err)
+	}
+	defer rows.Close()
+
+	items := []domain.TrashItem{}
+	for rows.Next() {
+		var item domain.TrashItem
+		if err := rows.Scan(&item.UserId, &item.EntityType, &item.EntityId); err != nil {
+			return nil, fmt.Errorf("failed to scan expired trash row: %w", err)
+		}
+		items = append(items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error for expired trash: %w", err)
+	}
+
+	if len(items) > limit {
+		items = items[:limit]
+	}
+
+	return items, nil
+}
+
+func scanTrashedExpense(row rowScanner, userId int) (*domain.TrashedExpense, error) {
+	var (
+		trashed     domain.TrashedExpense
+		description sql.NullString
+	)
+	err := row.Scan(
+		&trashed.Expense.Id,
+		&trashed.Expense.CategoryId,
+		&trashed.CategoryName,
+		&description,
+		&trashed.Expense.Amount,
+		&trashed.Expense.CreatedAt,
+		&trashed.DeletedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	trashed.Expense.Description = description.String
+	trashed.Expense.UserId = userId
+
+	return &trashed, nil
+}
+
+func scanTrashedCategory(row rowScanner, userId int) (*domain.TrashedCategory, error) {
+	var trashed domain.TrashedCategory
+	err := row.Scan(&trashed.Category.Id, &trashed.Category.Name, &trashed.DeletedAt, &trashed.ExpenseCount)
+	if err != nil {
+		return nil, err
+	}
+	trashed.Category.UserId = userId
+
+	return &trashed, nil
+}
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
+)
+
+type TwoFactorRedisRepository interface {
+	SaveChallenge(ctx context.Context, token string, ttl time.Duration, challenge domain.TwoFactorChallenge) error
+	GetChallenge(ctx context.Context, token string) (*domain.TwoFactorChallenge, error)
+	DeleteChallenge(ctx context.Context, token string) error
+	IncrementChallengeAttempt(ctx context.Context, token string, ttl time.Duration) (int, error)
+	GetFailures(ctx context.Context, userId int) (int, error)
+	IncrementFailure(ctx context.Context, userId int, ttl time.Duration) (int, error)
+	ClearFailures(ctx context.Context, userId int) error
+}
@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
+)
+
+type twoFactorRedisImpl struct {
+	Client *redis.Client
+}
+
+func NewTwoFactorRedisRepository(redisClient *redis.Client) TwoFactorRedisRepository {
+	return &twoFactorRedisImpl{
+		Client: redisClient,
+	}
+}
+
+func (twoFactorRedisRepository *twoFactorRedisImpl) SaveChallenge(ctx context.Context, token string, ttl time.Duration, challenge domain.TwoFactorChallenge) error {
+	key := fmt.Sprintf("2fa_challenge:%s", token)
+
+	data, err := json.Marshal(challenge)
+	if err != nil {
+		return fmt.Errorf("failed to marshal two factor challenge: %v", err)
+	}
+
+	if err := twoFactorRedisRepository.Client.Set(ctx, key, data, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to save two factor challenge to redis: %v", err)
+	}
+
+	return nil
+}
+
+// GetChallenge returns nil without an error when the token is unknown or
+// expired.
+func (twoFactorRedisRepository *twoFactorRedisImpl) GetChallenge(ctx context.Context, token string) (*domain.TwoFactorChallenge, error) {
+	key := fmt.Sprintf("2fa_challenge:%s", token)
+	val, err := twoFactorRedisRepository.Client.Get(ctx, key).Result()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get two factor challenge from redis: %v", err)
+	}
+
+	var challenge domain.TwoFactorChallenge
+	if err := json.Unmarshal([]byte(val), &challenge); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal two factor challenge: %v", err)
+	}
+
+	return &challenge, nil
+}
+
+func (twoFactorRedisRepository *twoFactorRedisImpl) DeleteChallenge(ctx context.Context, token string) error {
+	key := fmt.Sprintf("2fa_challenge:%s", token)
+	attemptsKey := fmt.Sprintf("2fa_challenge:attempts:%s", token)
+	if err := twoFactorRedisRepository.Client.Del(ctx, key, attemptsKey).Err(); err != nil {
+		return fmt.Errorf("failed to delete two factor challenge: %v", err)
+	}
+	return nil
+}
+
+// IncrementChallengeAttempt counts a wrong code for the challenge and
+// returns how many there have been.
+func (twoFactorRedisRepository *twoFactorRedisImpl) IncrementChallengeAttempt(ctx context.Context, token string, ttl time.Duration) (int, error) {
+	key := fmt.Sprintf("2fa_challenge:attempts:%s", token)
+
+	attempts, err := twoFactorRedisRepository.Client.Incr(ctx, key).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to increment two factor attempts: %v", err)
+	}
+
+	if err := twoFactorRedisRepository.Client.Expire(ctx, key, ttl).Err(); err != nil {
+		return 0, fmt.Errorf("failed to set expiry: %v", err)
+	}
+	return int(attempts), nil
+}
+
+// GetFailures returns how many wrong codes the user sent recently, across
+// all challenges.
+func (twoFactorRedisRepository *twoFactorRedisImpl) GetFailures(ctx context.Context, userId int) (int, error) {
+	key := fmt.Sprintf("2fa_failures:%d", userId)
+	failures, err := twoFactorRedisRepository.Client.Get(ctx, key).Int()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to get two factor failures: %v", err)
+	}
+	return failures, nil
+}
+
+// IncrementFailure counts a wrong code for the user and returns how many
+// there have been. The count expires ttl after the last one.
+func (twoFactorRedisRepository *twoFactorRedisImpl) IncrementFailure(ctx context.Context, userId int, ttl time.Duration) (int, error) {
+	key := fmt.Sprintf("2fa_failures:%d", userId)
+
+	failures, err := twoFactorRedisRepository.Client.Incr(ctx, key).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to increment two factor failures: %v", err)
+	}
+
+	if err := twoFactorRedisRepository.Client.Expire(ctx, key, ttl).Err(); err != nil {
+		return 0, fmt.Errorf("failed to set expiry: %v", err)
+	}
+	return int(failures), nil
+}
+
+func (twoFactorRedisRepository *twoFactorRedisImpl) ClearFailures(ctx context.Context, userId int) error {
+	key := fmt.Sprintf("2fa_failures:%d", userId)
+	if err := twoFactorRedisRepository.Client.Del(ctx, key).Err(); err != nil {
+		return fmt.Errorf("failed to clear two factor failures: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
+)
+
+type TwoFactorRepository interface {
+	SaveTwoFactor(ctx context.Context, tx *sql.Tx, twoFactor *domain.TwoFactor) error
+	UpdateTwoFactor(ctx context.Context, tx *sql.Tx, twoFactor *domain.TwoFactor) error
+	DeleteTwoFactor(ctx context.Context, tx *sql.Tx, userId int) error
+	FindTwoFactor(ctx context.Context, tx *sql.Tx, userId int) (*domain.TwoFactor, error)
+	SaveRecoveryCodes(ctx context.Context, tx *sql.Tx, userId int, hashes []string) error
+	DeleteRecoveryCodes(ctx context.Context, tx *sql.Tx, userId int) error
+	FindUnusedRecoveryCodes(ctx context.Context, tx *sql.Tx, userId int) ([]domain.RecoveryCode, error)
+	MarkRecoveryCodeUsed(ctx context.Context, tx *sql.Tx, codeId int, usedAt time.Time) error
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
+)
+
+type twoFactorRepositoryImpl struct{}
+
+func NewTwoFactorRepository() TwoFactorRepository {
+	return &twoFactorRepositoryImpl{}
+}
+
+// SaveTwoFactor stores a new, unconfirmed setup, replacing any earlier one.
+func (repository *twoFactorRepositoryImpl) SaveTwoFactor(ctx context.Context, tx *sql.Tx, twoFactor *domain.TwoFactor) error {
+	query := `insert into user_two_factor(user_id,secret,confirmed_at,last_used_step,created_at) values(?,?,?,?,?)
+		on duplicate key update secret = values(secret), confirmed_at = values(confirmed_at), last_used_step = values(last_used_step), created_at = values(created_at)`
+	_, err := tx.ExecContext(ctx, query, twoFactor.UserId, twoFactor.Secret, twoFactor.ConfirmedAt, twoFactor.LastUsedStep, twoFactor.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save two factor (userId=%d): %w", twoFactor.UserId, err)
+	}
+
+	return nil
+}
+
+func (repository *twoFactorRepositoryImpl) UpdateTwoFactor(ctx context.Context, tx *sql.Tx, twoFactor *domain.TwoFactor) error {
+	query := "update user_two_factor set confirmed_at = ?, last_used_step = ? where user_id = ?"
+	_, err := tx.ExecContext(ctx, query, twoFactor.ConfirmedAt, twoFactor.LastUsedStep, twoFactor.UserId)
+	if err != nil {
+		return fmt.Errorf("failed to update two factor (userId=%d): %w", twoFactor.UserId, err)
+	}
+
+	return nil
+}
+
+func (repository *twoFactorRepositoryImpl) DeleteTwoFactor(ctx context.Context, tx *sql.Tx, userId int) error {
+	if _, err := tx.ExecContext(ctx, "delete from user_two_factor where user_id = ?", userId); err != nil {
+		return fmt.Errorf("failed to delete two factor (userId=%d): %w", userId, err)
+	}
+
+	return nil
+}
+
+// FindTwoFactor locks the row, so concurrent logins cannot both accept the
+// same code before last_used_step moves on.
+func (repository *twoFactorRepositoryImpl) FindTwoFactor(ctx context.Context, tx *sql.Tx, userId int) (*domain.TwoFactor, error) {
+	query := "select user_id,secret,confirmed_at,last_used_step,created_at from user_two_factor where user_id = ? for update"
+
+	twoFactor := &domain.TwoFactor{}
+	var confirmedAt sql.NullTime
+	err := tx.QueryRowContext(ctx, query, userId).Scan(&twoFactor.UserId, &twoFactor.Secret, &confirmedAt, &twoFactor.LastUsedStep, &twoFactor.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("two factor not found (userId=%d): %w", userId, err)
+	}
+	if confirmedAt.Valid {
+		twoFactor.ConfirmedAt = &confirmedAt.Time
+	}
+
+	return twoFactor, nil
+}
+
+func (repository *twoFactorRepositoryImpl) SaveRecoveryCodes(ctx context.Context, tx *sql.Tx, userId int, hashes []string) error {
+	if len(hashes) == 0 {
+		return nil
+	}
+
+	args := make([]any, 0, len(hashes)*2)
+	for _, hash := range hashes {
+		args = append(args, userId, hash)
+	}
+
+	query := "insert into user_recovery_codes(user_id,code_hash) values" + strings.TrimSuffix(strings.Repeat("(?,?),", len(hashes)), ",")
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("failed to insert recovery codes (userId=%d): %w", userId, err)
+	}
+
+	return nil
+}
+
+func (repository *twoFactorRepositoryImpl) DeleteRecoveryCodes(ctx context.Context, tx *sql.Tx, userId int) error {
+	if _, err := tx.ExecContext(ctx, "delete from user_recovery_codes where user_id = ?", userId); err != nil {
+		return fmt.Errorf("failed to delete recovery codes (userId=%d): %w", userId, err)
+	}
+
+	return nil
+}
+
+func (repository *twoFactorRepositoryImpl) FindUnusedRecoveryCodes(ctx context.Context, tx *sql.Tx, userId int) ([]domain.RecoveryCode, error) {
+	query := "select id,user_id,code_hash from user_recovery_codes where user_id = ? and used_at is null order by id"
+	rows, err := tx.QueryContext(ctx, query, userId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query recovery codes for userId=%d: %w", userId, err)
+	}
+	defer rows.Close()
+
+	var codes []domain.RecoveryCode
+	for rows.Next() {
+		var code domain.RecoveryCode
+		if err := rows.Scan(&code.Id, &code.UserId, &code.Hash); err != nil {
+			return nil, fmt.Errorf("failed to scan recovery code for userId=%d: %w", userId, err)
+		}
+		codes = append(codes, code)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating recovery codes for userId=%d: %w", userId, err)
+	}
+
+	return codes, nil
+}
+
+func (repository *twoFactorRepositoryImpl) MarkRecoveryCodeUsed(ctx context.Context, tx *sql.Tx, codeId int, usedAt time.Time) error {
+	if _, err := tx.ExecContext(ctx, "update user_recovery_codes set used_at = ? where id = ?", usedAt, codeId); err != nil {
+		return fmt.Errorf("failed to mark recovery code used (id=%d): %w", codeId, err)
+	}
+
+	return nil
+}
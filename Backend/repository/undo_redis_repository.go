@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
+)
+
+type UndoRedisRepository interface {
+	SaveUndo(ctx context.Context, operation domain.UndoOperation, ttl time.Duration) error
+	ClaimUndo(ctx context.Context, token string) (*domain.UndoOperation, time.Duration, error)
+	RestoreUndo(ctx context.Context, operation domain.UndoOperation, ttl time.Duration) error
+}
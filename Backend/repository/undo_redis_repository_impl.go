@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
+)
+
+type undoRedisImpl struct {
+	Client *redis.Client
+}
+
+func NewUndoRedisRepository(redisClient *redis.Client) UndoRedisRepository {
+	return &undoRedisImpl{
+		Client: redisClient,
+	}
+}
+
+func (undoRedisRepository *undoRedisImpl) SaveUndo(ctx context.Context, operation domain.UndoOperation, ttl time.Duration) error {
+	key := fmt.Sprintf("undo:%s", operation.Token)
+
+	data, err := json.Marshal(operation)
+	if err != nil {
+		return fmt.Errorf("failed to marshal undo operation: %v", err)
+	}
+
+	if err := undoRedisRepository.Client.Set(ctx, key, data, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to save undo operation to redis: %v", err)
+	}
+
+	return nil
+}
+
+// ClaimUndo removes the operation from redis and returns it together with
+// the time it had left, so only one caller can use a token. It returns nil
+// without an error when the token is unknown or expired.
+func (undoRedisRepository *undoRedisImpl) ClaimUndo(ctx context.Context, token string) (*domain.UndoOperation, time.Duration, error) {
+	key := fmt.Sprintf("undo:%s", token)
+
+	var ttl *redis.DurationCmd
+	var val *redis.StringCmd
+	_, err := undoRedisRepository.Client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		ttl = pipe.PTTL(ctx, key)
+		val = pipe.GetDel(ctx, key)
+		return nil
+	})
+	if err == redis.Nil {
+		return nil, 0, nil
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to claim undo operation from redis: %v", err)
+	}
+
+	var operation domain.UndoOperation
+	if err := json.Unmarshal([]byte(val.Val()), &operation); err != nil {
+		return nil, 0, fmt.Errorf("failed to unmarshal undo operation: %v", err)
+	}
+
+	return &operation, ttl.Val(), nil
+}
+
+// RestoreUndo puts a claimed operation back for the time it had left.
+func (undoRedisRepository *undoRedisImpl) RestoreUndo(ctx context.Context, operation domain.UndoOperation, ttl time.Duration) error {
+	if ttl <= 0 {
+		return nil
+	}
+	return undoRedisRepository.SaveUndo(ctx, operation, ttl)
+}
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
+)
+
+type UserIdentityRepository interface {
+	SaveIdentity(ctx context.Context, tx *sql.Tx, identity *domain.UserIdentity) error
+	FindIdentity(ctx context.Context, tx *sql.Tx, issuer, subject string) (*domain.UserIdentity, error)
+	FindAllIdentity(ctx context.Context, tx *sql.Tx, userId int) ([]domain.UserIdentity, error)
+}
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
+)
+
+type userIdentityRepositoryImpl struct{}
+
+func NewUserIdentityRepository() UserIdentityRepository {
+	return &userIdentityRepositoryImpl{}
+}
+
+func (repository *userIdentityRepositoryImpl) SaveIdentity(ctx context.Context, tx *sql.Tx, identity *domain.UserIdentity) error {
+	query := "insert into user_identities(user_id,issuer,subject,email,created_at) values(?,?,?,?,?)"
+	result, err := tx.ExecContext(ctx, query, identity.UserId, identity.Issuer, identity.Subject, identity.Email, identity.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to insert user identity (issuer=%s, userId=%d): %w", identity.Issuer, identity.UserId, err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert id for user identity (issuer=%s, userId=%d): %w", identity.Issuer, identity.UserId, err)
+	}
+
+	identity.Id = int(id)
+
+	return nil
+}
+
+func (repository *userIdentityRepositoryImpl) FindIdentity(ctx context.Context, tx *sql.Tx, issuer, subject string) (*domain.UserIdentity, error) {
+	query := "select id,user_id,issuer,subject,email,created_at from user_identities where issuer = ? and subject = ?"
+
+	identity := &domain.UserIdentity{}
+	err := tx.QueryRowContext(ctx, query, issuer, subject).Scan(&identity.Id, &identity.UserId, &identity.Issuer, &identity.Subject, &identity.Email, &identity.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("user identity not found (issuer=%s, subject=%s): %w", issuer, subject, err)
+	}
+
+	return identity, nil
+}
+
+func (repository *userIdentityRepositoryImpl) FindAllIdentity(ctx context.Context, tx *sql.Tx, userId int) ([]domain.UserIdentity, error) {
+	query := "select id,user_id,issuer,subject,email,created_at from user_identities where user_id = ? order by id"
+	rows, err := tx.QueryContext(ctx, query, userId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query user identities for userId=%d: %w", userId, err)
+	}
+	defer rows.Close()
+
+	identities := []domain.UserIdentity{}
+	for rows.Next() {
+		var identity domain.UserIdentity
+		if err := rows.Scan(&identity.Id, &identity.UserId, &identity.Issuer, &identity.Subject, &identity.Email, &identity.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan user identity for userId=%d: %w", userId, err)
+		}
+		identities = append(identities, identity)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating user identities for userId=%d: %w", userId, err)
+	}
+
+	return identities, nil
+}
@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
+)
+
+type UserPreferencesRepository interface {
+	// FindPreferences returns sql.ErrNoRows, wrapped, for a user who never
+	// saved any.
+	FindPreferences(ctx context.Context, tx *sql.Tx, userId int) (*domain.UserPreferences, error)
+	SavePreferences(ctx context.Context, tx *sql.Tx, preferences *domain.UserPreferences) error
+}
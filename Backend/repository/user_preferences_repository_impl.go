@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
+)
+
+type userPreferencesRepositoryImpl struct{}
+
+func NewUserPreferencesRepository() UserPreferencesRepository {
+	return &userPreferencesRepositoryImpl{}
+}
+
+func (repository *userPreferencesRepositoryImpl) FindPreferences(ctx context.Context, tx *sql.Tx, userId int) (*domain.UserPreferences, error) {
+	query := "select user_id,timezone,locale,currency,week_start,date_format,updated_at from user_preferences where user_id = ?"
+
+	preferences := &domain.UserPreferences{}
+	err := tx.QueryRowContext(ctx, query, userId).Scan(&preferences.UserId, &preferences.Timezone, &preferences.Locale, &preferences.Currency, &preferences.WeekStart, &preferences.DateFormat, &preferences.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("user preferences not found (userId=%d): %w", userId, err)
+	}
+
+	return preferences, nil
+}
+
+func (repository *userPreferencesRepositoryImpl) SavePreferences(ctx context.Context, tx *sql.Tx, preferences *domain.UserPreferences) error {
+	query := `insert into user_preferences(user_id,timezone,locale,currency,week_start,date_format,updated_at) values(?,?,?,?,?,?,?)
+		on duplicate key update timezone = values(timezone), locale = values(locale), currency = values(currency),
+		week_start = values(week_start), date_format = values(date_format), updated_at = values(updated_at)`
+	_, err := tx.ExecContext(ctx, query, preferences.UserId, preferences.Timezone, preferences.Locale, preferences.Currency, preferences.WeekStart, preferences.DateFormat, preferences.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save user preferences (userId=%d): %w", preferences.UserId, err)
+	}
+
+	return nil
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
 )
@@ -11,4 +12,9 @@ type UserRepository interface {
 	SaveToDb(ctx context.Context, tx *sql.Tx, user *domain.User) error
 	FindByUsername(ctx context.Context, tx *sql.Tx, username string) (*domain.User, error)
 	FindByEmail(ctx context.Context, tx *sql.Tx, email string) (*domain.User, error)
+	FindById(ctx context.Context, tx *sql.Tx, userId int) (*domain.User, error)
+	UpdateUser(ctx context.Context, tx *sql.Tx, user *domain.User) error
+	FindUsersDueForDeletion(ctx context.Context, tx *sql.Tx, now time.Time, limit int) ([]int, error)
+	// DeleteUser deletes the user and everything they own.
+	DeleteUser(ctx context.Context, tx *sql.Tx, userId int) error
 }
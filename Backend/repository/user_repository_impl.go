@@ -3,6 +3,8 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"fmt"
+	"time"
 
 	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
 )
@@ -13,49 +15,115 @@ func NewUserRepository() UserRepository {
 	return &userRepositoryImpl{}
 }
 
+const userColumns = "id,username,email,password_hash,deletion_scheduled_at"
+
 func (userRepository *userRepositoryImpl) SaveToDb(ctx context.Context, tx *sql.Tx, user *domain.User) error {
 	query := "insert into users(username,email,password_hash) values(?,?,?)"
-	_, err := tx.ExecContext(ctx, query, user.Username, user.Email, user.PasswordHash)
+	result, err := tx.ExecContext(ctx, query, user.Username, user.Email, user.PasswordHash)
+
+	if err != nil {
+		return err
+	}
 
+	id, err := result.LastInsertId()
 	if err != nil {
 		return err
 	}
+	user.Id = int(id)
 
 	return nil
 }
 func (userRepository *userRepositoryImpl) FindByUsername(ctx context.Context, tx *sql.Tx, username string) (*domain.User, error) {
-	query := "select id,username,email,password_hash from users where username = ?"
-	row := tx.QueryRowContext(ctx, query, username)
+	query := "select " + userColumns + " from users where username = ?"
+	return scanUser(tx.QueryRowContext(ctx, query, username))
+}
+func (userRepository *userRepositoryImpl) FindByEmail(ctx context.Context, tx *sql.Tx, email string) (*domain.User, error) {
+	query := "select " + userColumns + " from users where email = ?"
+	return scanUser(tx.QueryRowContext(ctx, query, email))
+}
+func (userRepository *userRepositoryImpl) FindById(ctx context.Context, tx *sql.Tx, userId int) (*domain.User, error) {
+	query := "select " + userColumns + " from users where id = ?"
+	return scanUser(tx.QueryRowContext(ctx, query, userId))
+}
+func (userRepository *userRepositoryImpl) UpdateUser(ctx context.Context, tx *sql.Tx, user *domain.User) error {
+	query := "update users set username = ?, email = ?, password_hash = ?, deletion_scheduled_at = ? where id = ?"
+	_, err := tx.ExecContext(ctx, query, user.Username, user.Email, user.PasswordHash, user.DeletionScheduledAt, user.Id)
+	if err != nil {
+		return fmt.Errorf("failed to update user (id=%d): %w", user.Id, err)
+	}
 
-	user := &domain.User{}
+	return nil
+}
+func (userRepository *userRepositoryImpl) FindUsersDueForDeletion(ctx context.Context, tx *sql.Tx, now time.Time, limit int) ([]int, error) {
+	query := "select id from users where deletion_scheduled_at <= ? order by deletion_scheduled_at limit ? for update skip locked"
+	rows, err := tx.QueryContext(ctx, query, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query users due for deletion: %w", err)
+	}
+	defer rows.Close()
 
-	err := row.Scan(
-		&user.Id,
-		&user.Username,
-		&user.Email,
-		&user.PasswordHash,
-	)
+	var userIds []int
+	for rows.Next() {
+		var userId int
+		if err := rows.Scan(&userId); err != nil {
+			return nil, fmt.Errorf("failed to scan user due for deletion: %w", err)
+		}
+		userIds = append(userIds, userId)
+	}
 
-	if err != nil {
-		return nil, err
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating users due for deletion: %w", err)
 	}
-	return user, nil
+
+	return userIds, nil
 }
-func (userRepository *userRepositoryImpl) FindByEmail(ctx context.Context, tx *sql.Tx, email string) (*domain.User, error) {
-	query := "select id,username,email,password_hash from users where email = ?"
-	row := tx.QueryRowContext(ctx, query, email)
 
+// userDataDeletes removes everything a user owns, children before the rows
+// they reference. Identities, two-factor rows and preferences go with the
+// user through ON DELETE CASCADE.
+var userDataDeletes = []string{
+	"delete d from webhook_deliveries d join webhooks w on w.id = d.webhook_id where w.user_id = ?",
+	"delete from webhooks where user_id = ?",
+	"delete from webhook_events where user_id = ?",
+	"delete from personal_access_tokens where user_id = ?",
+	"delete from data_exports where user_id = ?",
+	"delete from expense_imports where user_id = ?",
+	"delete from recurring_expenses where user_id = ?",
+	"delete from category_rules where user_id = ?",
+	"delete from saved_views where user_id = ?",
+	"delete from audit_log where user_id = ?",
+	"delete from expenses where user_id = ?",
+	"delete from categories where user_id = ?",
+	"delete from users where id = ?",
+}
+
+func (userRepository *userRepositoryImpl) DeleteUser(ctx context.Context, tx *sql.Tx, userId int) error {
+	for _, query := range userDataDeletes {
+		if _, err := tx.ExecContext(ctx, query, userId); err != nil {
+			return fmt.Errorf("failed to delete user data (userId=%d, query=%q): %w", userId, query, err)
+		}
+	}
+
+	return nil
+}
+
+func scanUser(row rowScanner) (*domain.User, error) {
 	user := &domain.User{}
+	var deletionScheduledAt sql.NullTime
 
 	err := row.Scan(
 		&user.Id,
 		&user.Username,
 		&user.Email,
 		&user.PasswordHash,
+		&deletionScheduledAt,
 	)
 
 	if err != nil {
 		return nil, err
 	}
+	if deletionScheduledAt.Valid {
+		user.DeletionScheduledAt = &deletionScheduledAt.Time
+	}
 	return user, nil
 }
@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
+)
+
+type WebhookRepository interface {
+	SaveWebhook(ctx context.Context, tx *sql.Tx, webhook *domain.Webhook) error
+	DeleteWebhook(ctx context.Context, tx *sql.Tx, webhookId, userId int) error
+	FindWebhookById(ctx context.Context, tx *sql.Tx, webhookId, userId int) (*domain.Webhook, error)
+	FindAllWebhook(ctx context.Context, tx *sql.Tx, userId int) ([]domain.Webhook, error)
+	SaveWebhookEvent(ctx context.Context, tx *sql.Tx, event *domain.WebhookEvent) error
+	// FindUndispatchedEvents locks the oldest outbox events that have not
+	// been fanned out to deliveries yet, skipping rows another worker holds.
+	FindUndispatchedEvents(ctx context.Context, tx *sql.Tx, limit int) ([]domain.WebhookEvent, error)
+	MarkEventDispatched(ctx context.Context, tx *sql.Tx, eventId int64, dispatchedAt time.Time) error
+	DeleteWebhookEvent(ctx context.Context, tx *sql.Tx, eventId int64) error
+	SaveDelivery(ctx context.Context, tx *sql.Tx, delivery *domain.WebhookDelivery) error
+	// FindDueDeliveries locks pending deliveries due at now, with what is
+	// needed to send them, skipping rows another worker holds.
+	FindDueDeliveries(ctx context.Context, tx *sql.Tx, now time.Time, limit int) ([]domain.WebhookDelivery, error)
+	UpdateDelivery(ctx context.Context, tx *sql.Tx, delivery *domain.WebhookDelivery) error
+	FindDeliveryById(ctx context.Context, tx *sql.Tx, deliveryId int64, userId int) (*domain.WebhookDelivery, error)
+	// FindAllDeliveries returns the user's deliveries newest first. BeforeId
+	// pages backwards.
+	FindAllDeliveries(ctx context.Context, tx *sql.Tx, userId int, filter domain.WebhookDeliveryFilter) ([]domain.WebhookDelivery, error)
+}
@@ -0,0 +1,298 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
+)
+
+type webhookRepositoryImpl struct{}
+
+func NewWebhookRepository() WebhookRepository {
+	return &webhookRepositoryImpl{}
+}
+
+const webhookDeliveryQuery = `select d.id,d.webhook_id,d.event_id,e.event_type,d.status,d.attempts,d.next_attempt_at,d.last_status_code,d.last_error,d.delivered_at,d.created_at
+	from webhook_deliveries d
+	join webhooks w on w.id = d.webhook_id
+	join webhook_events e on e.id = d.event_id
+	where w.user_id = ?`
+
+func (repository *webhookRepositoryImpl) SaveWebhook(ctx context.Context, tx *sql.Tx, webhook *domain.Webhook) error {
+	query := "insert into webhooks(user_id,url,secret,events,created_at) values(?,?,?,?,?)"
+	result, err := tx.ExecContext(ctx, query, webhook.UserId, webhook.Url, webhook.Secret, strings.Join(webhook.Events, ","), webhook.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to insert webhook (userId=%d): %w", webhook.UserId, err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert id for webhook (userId=%d): %w", webhook.UserId, err)
+	}
+
+	webhook.Id = int(id)
+
+	return nil
+}
+
+func (repository *webhookRepositoryImpl) DeleteWebhook(ctx context.Context, tx *sql.Tx, webhookId, userId int) error {
+	result, err := tx.ExecContext(ctx, "delete from webhooks where id = ? and user_id = ?", webhookId, userId)
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook (id=%d, userId=%d): %w", webhookId, userId, err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("no webhook deleted (id=%d, userId=%d): %w", webhookId, userId, sql.ErrNoRows)
+	}
+
+	return nil
+}
+
+func (repository *webhookRepositoryImpl) FindWebhookById(ctx context.Context, tx *sql.Tx, webhookId, userId int) (*domain.Webhook, error) {
+	query := "select id,user_id,url,secret,events,created_at from webhooks where id = ? and user_id = ?"
+	webhook, err := scanWebhook(tx.QueryRowContext(ctx, query, webhookId, userId))
+	if err != nil {
+		return nil, fmt.Errorf("webhook not found (id=%d, userId=%d): %w", webhookId, userId, err)
+	}
+
+	return webhook, nil
+}
+
+func (repository *webhookRepositoryImpl) FindAllWebhook(ctx context.Context, tx *sql.Tx, userId int) ([]domain.Webhook, error) {
+	query := "select id,user_id,url,secret,events,created_at from webhooks where user_id = ? order by id"
+	rows, err := tx.QueryContext(ctx, query, userId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query webhooks for userId=%d: %w", userId, err)
+	}
+	defer rows.Close()
+
+	webhooks := []domain.Webhook{}
+	for rows.Next() {
+		webhook, err := scanWebhook(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan webhook row for userId=%d: %w", userId, err)
+		}
+		webhooks = append(webhooks, *webhook)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error for userId=%d: %w", userId, err)
+	}
+
+	return webhooks, nil
+}
+
+func (repository *webhookRepositoryImpl) SaveWebhookEvent(ctx context.Context, tx *sql.Tx, event *domain.WebhookEvent) error {
+	query := "insert into webhook_events(user_id,event_type,payload,created_at) values(?,?,?,?)"
+	result, err := tx.ExecContext(ctx, query, event.UserId, event.Type, string(event.Payload), event.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to insert webhook event (%s, userId=%d): %w", event.Type, event.UserId, err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert id for webhook event (userId=%d): %w", event.UserId, err)
+	}
+
+	event.Id = id
+
+	return nil
+}
+
+func (repository *webhookRepositoryImpl) FindUndispatchedEvents(ctx context.Context, tx *sql.Tx, limit int) ([]domain.WebhookEvent, error) {
+	query := `select id,user_id,event_type,payload,created_at from webhook_events
+		where dispatched_at is null order by id limit ? for update skip locked`
+	rows, err := tx.QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query undispatched webhook events: %w", err)
+	}
+	defer rows.Close()
+
+	events := []domain.WebhookEvent{}
+	for rows.Next() {
+		var (
+			event   domain.WebhookEvent
+			payload []byte
+		)
+		if err := rows.Scan(&event.Id, &event.UserId, &event.Type, &payload, &event.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan webhook event row: %w", err)
+		}
+		event.Payload = payload
+		events = append(events, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	return events, nil
+}
+
+func (repository *webhookRepositoryImpl) MarkEventDispatched(ctx context.Context, tx *sql.Tx, eventId int64, dispatchedAt time.Time) error {
+	if _, err := tx.ExecContext(ctx, "update webhook_events set dispatched_at = ? where id = ?", dispatchedAt, eventId); err != nil {
+		return fmt.Errorf("failed to mark webhook event dispatched (id=%d): %w", eventId, err)
+	}
+	return nil
+}
+
+func (repository *webhookRepositoryImpl) DeleteWebhookEvent(ctx context.Context, tx *sql.Tx, eventId int64) error {
+	if _, err := tx.ExecContext(ctx, "delete from webhook_events where id = ?", eventId); err != nil {
+		return fmt.Errorf("failed to delete webhook event (id=%d): %w", eventId, err)
+	}
+	return nil
+}
+
+func (repository *webhookRepositoryImpl) SaveDelivery(ctx context.Context, tx *sql.Tx, delivery *domain.WebhookDelivery) error {
+	query := "insert into webhook_deliveries(webhook_id,event_id,status,attempts,next_attempt_at,created_at) values(?,?,?,?,?,?)"
+	result, err := tx.ExecContext(ctx, query, delivery.WebhookId, delivery.EventId, delivery.Status, delivery.Attempts, delivery.NextAttemptAt, delivery.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to insert webhook delivery (webhookId=%d, eventId=%d): %w", delivery.WebhookId, delivery.EventId, err)
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get last insert id for webhook delivery: %w", err)
+	}
+
+	delivery.Id = id
+
+	return nil
+}
+
+func (repository *webhookRepositoryImpl) FindDueDeliveries(ctx context.Context, tx *sql.Tx, now time.Time, limit int) ([]domain.WebhookDelivery, error) {
+	query := `select d.id,d.webhook_id,d.event_id,e.event_type,d.status,d.attempts,d.next_attempt_at,d.last_status_code,d.last_error,d.delivered_at,d.created_at,
+		w.url,w.secret,e.payload,e.created_at
+		from webhook_deliveries d
+		join webhooks w on w.id = d.webhook_id
+		join webhook_events e on e.id = d.event_id
+		where d.status = ? and d.next_attempt_at <= ?
+		order by d.next_attempt_at limit ?
+		for update of d skip locked`
+	rows, err := tx.QueryContext(ctx, query, domain.WebhookDeliveryPending, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query due webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := []domain.WebhookDelivery{}
+	for rows.Next() {
+		var (
+			payload  []byte
+			delivery domain.WebhookDelivery
+		)
+		err := rows.Scan(deliveryFields(&delivery, &delivery.Url, &delivery.Secret, &payload, &delivery.EventCreatedAt)...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan webhook delivery row: %w", err)
+		}
+		delivery.Payload = payload
+		deliveries = append(deliveries, delivery)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+func (repository *webhookRepositoryImpl) UpdateDelivery(ctx context.Context, tx *sql.Tx, delivery *domain.WebhookDelivery) error {
+	query := "update webhook_deliveries set status = ?, attempts = ?, next_attempt_at = ?, last_status_code = ?, last_error = ?, delivered_at = ? where id = ?"
+	_, err := tx.ExecContext(ctx, query, delivery.Status, delivery.Attempts, delivery.NextAttemptAt, delivery.LastStatusCode, delivery.LastError, delivery.DeliveredAt, delivery.Id)
+	if err != nil {
+		return fmt.Errorf("failed to update webhook delivery (id=%d): %w", delivery.Id, err)
+	}
+	return nil
+}
+
+func (repository *webhookRepositoryImpl) FindDeliveryById(ctx context.Context, tx *sql.Tx, deliveryId int64, userId int) (*domain.WebhookDelivery, error) {
+	var delivery domain.WebhookDelivery
+	err := tx.QueryRowContext(ctx, webhookDeliveryQuery+" and d.id = ?", userId, deliveryId).Scan(deliveryFields(&delivery)...)
+	if err != nil {
+		return nil, fmt.Errorf("webhook delivery not found (id=%d, userId=%d): %w", deliveryId, userId, err)
+	}
+
+	return &delivery, nil
+}
+
+func (repository *webhookRepositoryImpl) FindAllDeliveries(ctx context.Context, tx *sql.Tx, userId int, filter domain.WebhookDeliveryFilter) ([]domain.WebhookDelivery, error) {
+	var (
+		sb   strings.Builder
+		args = []any{userId}
+	)
+
+	sb.WriteString(webhookDeliveryQuery)
+	if filter.Status != "" {
+		sb.WriteString(" and d.status = ?")
+		args = append(args, filter.Status)
+	}
+	if filter.WebhookId > 0 {
+		sb.WriteString(" and d.webhook_id = ?")
+		args = append(args, filter.WebhookId)
+	}
+	if filter.BeforeId > 0 {
+		sb.WriteString(" and d.id < ?")
+		args = append(args, filter.BeforeId)
+	}
+	sb.WriteString(" order by d.id desc limit ?")
+	args = append(args, filter.Limit)
+
+	rows, err := tx.QueryContext(ctx, sb.String(), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query webhook deliveries for userId=%d: %w", userId, err)
+	}
+	defer rows.Close()
+
+	deliveries := []domain.WebhookDelivery{}
+	for rows.Next() {
+		var delivery domain.WebhookDelivery
+		if err := rows.Scan(deliveryFields(&delivery)...); err != nil {
+			return nil, fmt.Errorf("failed to scan webhook delivery row for userId=%d: %w", userId, err)
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error for userId=%d: %w", userId, err)
+	}
+
+	return deliveries, nil
+}
+
+func scanWebhook(row rowScanner) (*domain.Webhook, error) {
+	var (
+		webhook domain.Webhook
+		events  string
+	)
+	if err := row.Scan(&webhook.Id, &webhook.UserId, &webhook.Url, &webhook.Secret, &events, &webhook.CreatedAt); err != nil {
+		return nil, err
+	}
+	webhook.Events = strings.Split(events, ",")
+
+	return &webhook, nil
+}
+
+// deliveryFields lists the scan targets for the columns of
+// webhookDeliveryQuery, followed by extra.
+func deliveryFields(delivery *domain.WebhookDelivery, extra ...any) []any {
+	return append([]any{
+		&delivery.Id,
+		&delivery.WebhookId,
+		&delivery.EventId,
+		&delivery.EventType,
+		&delivery.Status,
+		&delivery.Attempts,
+		&delivery.NextAttemptAt,
+		&delivery.LastStatusCode,
+		&delivery.LastError,
+		&delivery.DeliveredAt,
+		&delivery.CreatedAt,
+	}, extra...)
+}
@@ -0,0 +1,18 @@
+package service
+
+import (
+	"context"
+
+	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
+	"github.com/ryhnfhrza/Expense-Tracker/model/web"
+)
+
+type AccessTokenService interface {
+	CreateAccessToken(ctx context.Context, request web.AccessTokenCreateRequest) web.AccessTokenResponse
+	DeleteAccessToken(ctx context.Context, tokenId, userId int)
+	FindAllAccessToken(ctx context.Context, userId int) []web.AccessTokenResponse
+	// Authenticate returns the token named by rawToken and records that it
+	// was used. It panics with an UnauthorizedError for an unknown or
+	// expired token.
+	Authenticate(ctx context.Context, rawToken string) domain.AccessToken
+}
@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/ryhnfhrza/Expense-Tracker/exception"
+	"github.com/ryhnfhrza/Expense-Tracker/helper"
+	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
+	"github.com/ryhnfhrza/Expense-Tracker/model/web"
+	"github.com/ryhnfhrza/Expense-Tracker/repository"
+)
+
+const (
+	// accessTokenShownPrefix is how much of a token is kept in the clear:
+	// "pat_" and the first eight random characters.
+	accessTokenShownPrefix = 12
+
+	// last_used_at is only written when it is older than this, so a busy
+	// script does not turn every request into a write.
+	accessTokenLastUsedInterval = time.Minute
+)
+
+type accessTokenServiceImpl struct {
+	DB                    *sql.DB
+	Validate              *validator.Validate
+	AccessTokenRepository repository.AccessTokenRepository
+}
+
+func NewAccessTokenService(db *sql.DB, validate *validator.Validate, accessTokenRepository repository.AccessTokenRepository) AccessTokenService {
+	return &accessTokenServiceImpl{
+		DB:                    db,
+		Validate:              validate,
+		AccessTokenRepository: accessTokenRepository,
+	}
+}
+
+// CreateAccessToken issues a token with the requested scopes. The token
+// itself is only part of this response; afterwards just its hash is kept.
+func (service *accessTokenServiceImpl) CreateAccessToken(ctx context.Context, request web.AccessTokenCreateRequest) web.AccessTokenResponse {
+	err := service.Validate.Struct(request)
+	helper.PanicIfError(err)
+
+	var scopes []string
+	for _, scope := range request.Scopes {
+		if !slices.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+
+	buf := make([]byte, 20)
+	_, err = rand.Read(buf)
+	helper.PanicIfError(err)
+	rawToken := domain.AccessTokenPrefix + hex.EncodeToString(buf)
+
+	now := time.Now().UTC()
+	token := domain.AccessToken{
+		UserId:    request.UserId,
+		Name:      request.Name,
+		Prefix:    rawToken[:accessTokenShownPrefix],
+		Hash:      hashAccessToken(rawToken),
+		Scopes:    scopes,
+		CreatedAt: now,
+	}
+	if request.ExpiresInDays > 0 {
+		expiresAt := now.AddDate(0, 0, request.ExpiresInDays)
+		token.ExpiresAt = &expiresAt
+	}
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	err = service.AccessTokenRepository.SaveAccessToken(ctx, tx, &token)
+	helper.PanicIfError(err)
+
+	response := helper.ToAccessTokenResponse(token)
+	response.Token = rawToken
+
+	return response
+}
+
+// DeleteAccessToken revokes the token; requests using it fail from now on.
+func (service *accessTokenServiceImpl) DeleteAccessToken(ctx context.Context, tokenId, userId int) {
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	err = service.AccessTokenRepository.DeleteAccessToken(ctx, tx, tokenId, userId)
+	if errors.Is(err, sql.ErrNoRows) {
+		panic(exception.NewNotFoundError(fmt.Sprintf("access token with id:%d not found", tokenId)))
+	}
+	helper.PanicIfError(err)
+}
+
+func (service *accessTokenServiceImpl) FindAllAccessToken(ctx context.Context, userId int) []web.AccessTokenResponse {
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	tokens, err := service.AccessTokenRepository.FindAllAccessToken(ctx, tx, userId)
+	helper.PanicIfError(err)
+
+	return helper.ToAccessTokenResponses(tokens)
+}
+
+func (service *accessTokenServiceImpl) Authenticate(ctx context.Context, rawToken string) domain.AccessToken {
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	token, err := service.AccessTokenRepository.FindAccessTokenByHash(ctx, tx, hashAccessToken(rawToken))
+	if errors.Is(err, sql.ErrNoRows) {
+		panic(exception.NewUnauthorizedError("invalid or expired token"))
+	}
+	helper.PanicIfError(err)
+
+	now := time.Now().UTC()
+	if token.ExpiresAt != nil && !now.Before(*token.ExpiresAt) {
+		panic(exception.NewUnauthorizedError("invalid or expired token"))
+	}
+
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) >= accessTokenLastUsedInterval {
+		err = service.AccessTokenRepository.UpdateLastUsed(ctx, tx, token.Id, now)
+		helper.PanicIfError(err)
+		token.LastUsedAt = &now
+	}
+
+	return *token
+}
+
+// hashAccessToken is the form a token is stored and looked up in. Tokens
+// carry 160 random bits, so a plain SHA-256 is enough and, unlike bcrypt,
+// can be looked up directly.
+func hashAccessToken(rawToken string) string {
+	sum := sha256.Sum256([]byte(rawToken))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,44 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/ryhnfhrza/Expense-Tracker/helper"
+	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
+	"github.com/ryhnfhrza/Expense-Tracker/repository"
+)
+
+// recordAudit appends an entry to the audit log inside tx, so the entry and
+// the change it describes commit or roll back together. before and after are
+// the response representations of the entity; pass nil for the side that
+// does not exist. The actor and request id come from ctx.
+func recordAudit(ctx context.Context, tx *sql.Tx, auditRepository repository.AuditRepository, userId int, entityType string, entityId int, action string, before, after any) {
+	entry := domain.AuditEntry{
+		UserId:     userId,
+		EntityType: entityType,
+		EntityId:   entityId,
+		Action:     action,
+		RequestId:  helper.GetRequestIDFromContext(ctx),
+		CreatedAt:  time.Now().UTC(),
+	}
+
+	if actorId, ok := helper.GetUserIDFromContext(ctx); ok {
+		entry.ActorId = &actorId
+	}
+
+	var err error
+	if before != nil {
+		entry.Before, err = json.Marshal(before)
+		helper.PanicIfError(err)
+	}
+	if after != nil {
+		entry.After, err = json.Marshal(after)
+		helper.PanicIfError(err)
+	}
+
+	err = auditRepository.SaveAudit(ctx, tx, &entry)
+	helper.PanicIfError(err)
+}
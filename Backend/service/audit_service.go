@@ -0,0 +1,13 @@
+package service
+
+import (
+	"context"
+
+	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
+	"github.com/ryhnfhrza/Expense-Tracker/model/web"
+)
+
+type AuditService interface {
+	FindExpenseHistory(ctx context.Context, expenseId, userId int) web.AuditListResponse
+	FindActivity(ctx context.Context, userId int, filter domain.AuditFilter) web.AuditListResponse
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/ryhnfhrza/Expense-Tracker/exception"
+	"github.com/ryhnfhrza/Expense-Tracker/helper"
+	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
+	"github.com/ryhnfhrza/Expense-Tracker/model/web"
+	"github.com/ryhnfhrza/Expense-Tracker/repository"
+)
+
+const (
+	defaultAuditLimit = 50
+	maxAuditLimit     = 200
+	maxExpenseHistory = 500
+)
+
+type auditServiceImpl struct {
+	DB              *sql.DB
+	AuditRepository repository.AuditRepository
+}
+
+func NewAuditService(db *sql.DB, auditRepository repository.AuditRepository) AuditService {
+	return &auditServiceImpl{
+		DB:              db,
+		AuditRepository: auditRepository,
+	}
+}
+
+// FindExpenseHistory returns every recorded change of one expense, newest
+// first. It still works after the expense is deleted.
+func (service *auditServiceImpl) FindExpenseHistory(ctx context.Context, expenseId, userId int) web.AuditListResponse {
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	entries, err := service.AuditRepository.FindAllAudit(ctx, tx, userId, domain.AuditFilter{
+		EntityType: domain.AuditEntityExpense,
+		EntityId:   expenseId,
+		Limit:      maxExpenseHistory,
+	})
+	helper.PanicIfError(err)
+
+	if len(entries) == 0 {
+		panic(exception.NewNotFoundError(fmt.Sprintf("no history for expense with id:%d", expenseId)))
+	}
+
+	return helper.ToAuditListResponse(entries, 0)
+}
+
+func (service *auditServiceImpl) FindActivity(ctx context.Context, userId int, filter domain.AuditFilter) web.AuditListResponse {
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	if filter.Limit <= 0 {
+		filter.Limit = defaultAuditLimit
+	}
+	if filter.Limit > maxAuditLimit {
+		filter.Limit = maxAuditLimit
+	}
+
+	entries, err := service.AuditRepository.FindAllAudit(ctx, tx, userId, filter)
+	helper.PanicIfError(err)
+
+	return helper.ToAuditListResponse(entries, filter.Limit)
+}
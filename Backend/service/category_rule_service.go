@@ -0,0 +1,16 @@
+package service
+
+import (
+	"context"
+
+	"github.com/ryhnfhrza/Expense-Tracker/model/web"
+)
+
+type CategoryRuleService interface {
+	CreateRule(ctx context.Context, request web.CategoryRuleRequest) web.CategoryRuleResponse
+	UpdateRule(ctx context.Context, request web.CategoryRuleRequest) web.CategoryRuleResponse
+	DeleteRule(ctx context.Context, ruleId, userId int)
+	FindRuleById(ctx context.Context, ruleId, userId int) web.CategoryRuleResponse
+	FindAllRule(ctx context.Context, userId int) []web.CategoryRuleResponse
+	ApplyRules(ctx context.Context, request web.CategoryRuleApplyRequest) web.CategoryRuleApplyResponse
+}
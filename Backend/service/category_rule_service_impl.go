@@ -0,0 +1,255 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/ryhnfhrza/Expense-Tracker/classifier"
+	"github.com/ryhnfhrza/Expense-Tracker/exception"
+	"github.com/ryhnfhrza/Expense-Tracker/helper"
+	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
+	"github.com/ryhnfhrza/Expense-Tracker/model/web"
+	"github.com/ryhnfhrza/Expense-Tracker/repository"
+)
+
+type categoryRuleServiceImpl struct {
+	DB                     *sql.DB
+	Validate               *validator.Validate
+	CategoryRuleRepository repository.CategoryRuleRepository
+	CategoryRepository     repository.CategoryRepository
+	ExpenseRepository      repository.ExpenseRepository
+	AuditRepository        repository.AuditRepository
+	WebhookRepository      repository.WebhookRepository
+	UndoRepository         repository.UndoRedisRepository
+	CategoryModels         *classifier.Store
+}
+
+func NewCategoryRuleService(db *sql.DB, validate *validator.Validate, categoryRuleRepository repository.CategoryRuleRepository, categoryRepository repository.CategoryRepository, expenseRepository repository.ExpenseRepository, auditRepository repository.AuditRepository, webhookRepository repository.WebhookRepository, undoRepository repository.UndoRedisRepository, categoryModels *classifier.Store) CategoryRuleService {
+	return &categoryRuleServiceImpl{
+		DB:                     db,
+		Validate:               validate,
+		CategoryRuleRepository: categoryRuleRepository,
+		CategoryRepository:     categoryRepository,
+		ExpenseRepository:      expenseRepository,
+		AuditRepository:        auditRepository,
+		WebhookRepository:      webhookRepository,
+		UndoRepository:         undoRepository,
+		CategoryModels:         categoryModels,
+	}
+}
+
+func (service *categoryRuleServiceImpl) CreateRule(ctx context.Context, request web.CategoryRuleRequest) web.CategoryRuleResponse {
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	err = service.Validate.Struct(request)
+	helper.PanicIfError(err)
+	validateCategoryRuleConditions(request)
+
+	category, err := service.CategoryRepository.FindCategoryById(ctx, tx, request.CategoryId, request.UserId)
+	if err != nil {
+		panic(exception.NewNotFoundError(fmt.Sprintf("category with id:%d not found", request.CategoryId)))
+	}
+
+	rule := domain.CategoryRule{
+		UserId:              request.UserId,
+		CategoryId:          request.CategoryId,
+		Name:                strings.TrimSpace(request.Name),
+		DescriptionContains: request.DescriptionContains,
+		DescriptionPattern:  request.DescriptionPattern,
+		MinAmount:           request.MinAmount,
+		MaxAmount:           request.MaxAmount,
+		Priority:            request.Priority,
+		CreatedAt:           time.Now().UTC(),
+	}
+
+	err = service.CategoryRuleRepository.SaveRule(ctx, tx, &rule)
+	helper.PanicIfError(err)
+
+	return helper.ToCategoryRuleResponse(rule, category.Name)
+}
+
+func (service *categoryRuleServiceImpl) UpdateRule(ctx context.Context, request web.CategoryRuleRequest) web.CategoryRuleResponse {
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	rule, err := service.CategoryRuleRepository.FindRuleById(ctx, tx, request.Id, request.UserId)
+	if err != nil {
+		panic(exception.NewNotFoundError(fmt.Sprintf("rule with id:%d not found", request.Id)))
+	}
+
+	err = service.Validate.Struct(request)
+	helper.PanicIfError(err)
+	validateCategoryRuleConditions(request)
+
+	category, err := service.CategoryRepository.FindCategoryById(ctx, tx, request.CategoryId, request.UserId)
+	if err != nil {
+		panic(exception.NewNotFoundError(fmt.Sprintf("category with id:%d not found", request.CategoryId)))
+	}
+
+	rule.CategoryId = request.CategoryId
+	rule.Name = strings.TrimSpace(request.Name)
+	rule.DescriptionContains = request.DescriptionContains
+	rule.DescriptionPattern = request.DescriptionPattern
+	rule.MinAmount = request.MinAmount
+	rule.MaxAmount = request.MaxAmount
+	rule.Priority = request.Priority
+
+	err = service.CategoryRuleRepository.UpdateRule(ctx, tx, rule)
+	helper.PanicIfError(err)
+
+	return helper.ToCategoryRuleResponse(*rule, category.Name)
+}
+
+func (service *categoryRuleServiceImpl) DeleteRule(ctx context.Context, ruleId, userId int) {
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	_, err = service.CategoryRuleRepository.FindRuleById(ctx, tx, ruleId, userId)
+	if err != nil {
+		panic(exception.NewNotFoundError(fmt.Sprintf("rule with id:%d not found", ruleId)))
+	}
+
+	err = service.CategoryRuleRepository.DeleteRule(ctx, tx, ruleId, userId)
+	helper.PanicIfError(err)
+}
+
+func (service *categoryRuleServiceImpl) FindRuleById(ctx context.Context, ruleId, userId int) web.CategoryRuleResponse {
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	rule, err := service.CategoryRuleRepository.FindRuleById(ctx, tx, ruleId, userId)
+	if err != nil {
+		panic(exception.NewNotFoundError(fmt.Sprintf("rule with id:%d not found", ruleId)))
+	}
+
+	category, err := service.CategoryRepository.FindCategoryById(ctx, tx, rule.CategoryId, userId)
+	if err != nil {
+		panic(exception.NewNotFoundError(fmt.Sprintf("category with id:%d not found", rule.CategoryId)))
+	}
+
+	return helper.ToCategoryRuleResponse(*rule, category.Name)
+}
+
+func (service *categoryRuleServiceImpl) FindAllRule(ctx context.Context, userId int) []web.CategoryRuleResponse {
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	rules, err := service.CategoryRuleRepository.FindAllRule(ctx, tx, userId)
+	helper.PanicIfError(err)
+
+	categories, err := service.CategoryRepository.FindAllCategory(ctx, tx, userId)
+	helper.PanicIfError(err)
+
+	return helper.ToCategoryRuleResponses(rules, categories)
+}
+
+// ApplyRules re-runs the rules over every expense of the user and moves the
+// ones whose matching rule points to another category. The whole pass runs in
+// one transaction; with DryRun the changes are only reported. Expenses edited
+// or deleted by someone else during the pass are skipped and counted.
+func (service *categoryRuleServiceImpl) ApplyRules(ctx context.Context, request web.CategoryRuleApplyRequest) web.CategoryRuleApplyResponse {
+	defer helper.AfterCommit(func() {
+		if !request.DryRun {
+			service.CategoryModels.Reset(request.UserId)
+		}
+	})
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	err = service.Validate.Struct(request)
+	helper.PanicIfError(err)
+
+	rules, err := service.CategoryRuleRepository.FindAllRule(ctx, tx, request.UserId)
+	helper.PanicIfError(err)
+
+	matcher, err := helper.NewCategoryRuleMatcher(rules)
+	helper.PanicIfError(err)
+
+	expenses, err := service.ExpenseRepository.FindAllExpanse(ctx, tx, request.UserId, domain.ExpenseFilter{})
+	helper.PanicIfError(err)
+
+	categories, err := service.CategoryRepository.FindAllCategory(ctx, tx, request.UserId)
+	helper.PanicIfError(err)
+
+	categoryNames := make(map[int]string, len(categories))
+	for _, category := range categories {
+		categoryNames[category.Id] = category.Name
+	}
+
+	var (
+		changes   []domain.CategoryRuleChange
+		undoSteps []domain.UndoStep
+		skipped   int
+	)
+	for _, expense := range expenses {
+		rule := matcher.Match(expense.Description, expense.Amount)
+		if rule == nil || rule.CategoryId == expense.CategoryId {
+			continue
+		}
+
+		change := domain.CategoryRuleChange{
+			ExpenseId:      expense.Id,
+			FromCategoryId: expense.CategoryId,
+			ToCategoryId:   rule.CategoryId,
+			RuleId:         rule.Id,
+		}
+		if request.DryRun {
+			changes = append(changes, change)
+			continue
+		}
+
+		before := helper.ToExpenseResponse(expense, categoryNames[expense.CategoryId])
+		original := expense
+		expense.CategoryId = rule.CategoryId
+		expense.UserId = request.UserId
+		_, err = service.ExpenseRepository.UpdateExpense(ctx, tx, &expense)
+		if errors.Is(err, sql.ErrNoRows) {
+			// Edited or deleted since it was read; that change wins.
+			skipped++
+			continue
+		}
+		helper.PanicIfError(err)
+		changes = append(changes, change)
+
+		after := helper.ToExpenseResponse(expense, categoryNames[expense.CategoryId])
+		recordExpenseChange(ctx, tx, service.AuditRepository, service.WebhookRepository, request.UserId, expense.Id, domain.AuditActionUpdate, before, after)
+
+		updated := expense
+		undoSteps = append(undoSteps, domain.UndoStep{Action: domain.UndoRevertExpense, EntityId: expense.Id, Before: &original, After: &updated})
+	}
+
+	response := helper.ToCategoryRuleApplyResponse(request.DryRun, len(expenses), changes)
+	response.Skipped = skipped
+	response.Undo = saveUndo(ctx, service.UndoRepository, request.UserId, domain.UndoKindApplyRules, undoSteps)
+
+	return response
+}
+
+func validateCategoryRuleConditions(request web.CategoryRuleRequest) {
+	if request.DescriptionContains == "" && request.DescriptionPattern == "" && request.MinAmount == nil && request.MaxAmount == nil {
+		panic(exception.NewBadRequest("rule needs at least one condition: description_contains, description_pattern, min_amount or max_amount"))
+	}
+
+	if request.MinAmount != nil && request.MaxAmount != nil && *request.MinAmount > *request.MaxAmount {
+		panic(exception.NewBadRequest("min_amount must be less than or equal to max_amount"))
+	}
+
+	if request.DescriptionPattern != "" {
+		if _, err := helper.CompileRulePattern(request.DescriptionPattern); err != nil {
+			panic(exception.NewBadRequest(err.Error()))
+		}
+	}
+}
@@ -8,7 +8,7 @@ import (
 
 type CategoryService interface {
 	CreateCategory(ctx context.Context, request *web.CategoryRequest) web.CategoryResponse
-	DeleteCategory(ctx context.Context, categoryId, userId int)
+	DeleteCategory(ctx context.Context, categoryId, userId int) web.UndoTokenResponse
 	FindCategoryById(ctx context.Context, categoryId, userId int) web.CategoryResponse
 	FindAllCategoryById(ctx context.Context, userId int) []web.CategoryResponse
 }
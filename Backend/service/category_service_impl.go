@@ -6,6 +6,7 @@ import (
 	"errors"
 
 	"github.com/go-playground/validator/v10"
+	"github.com/ryhnfhrza/Expense-Tracker/classifier"
 	"github.com/ryhnfhrza/Expense-Tracker/exception"
 	"github.com/ryhnfhrza/Expense-Tracker/helper"
 	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
@@ -17,13 +18,23 @@ type categoryServiceImpl struct {
 	DB                 *sql.DB
 	Validate           *validator.Validate
 	CategoryRepository repository.CategoryRepository
+	ExpenseRepository  repository.ExpenseRepository
+	AuditRepository    repository.AuditRepository
+	WebhookRepository  repository.WebhookRepository
+	UndoRepository     repository.UndoRedisRepository
+	CategoryModels     *classifier.Store
 }
 
-func NewCategoryService(db *sql.DB, validate *validator.Validate, categoryRepository repository.CategoryRepository) CategoryService {
+func NewCategoryService(db *sql.DB, validate *validator.Validate, categoryRepository repository.CategoryRepository, expenseRepository repository.ExpenseRepository, auditRepository repository.AuditRepository, webhookRepository repository.WebhookRepository, undoRepository repository.UndoRedisRepository, categoryModels *classifier.Store) CategoryService {
 	return &categoryServiceImpl{
 		DB:                 db,
 		Validate:           validate,
 		CategoryRepository: categoryRepository,
+		ExpenseRepository:  expenseRepository,
+		AuditRepository:    auditRepository,
+		WebhookRepository:  webhookRepository,
+		UndoRepository:     undoRepository,
+		CategoryModels:     categoryModels,
 	}
 }
 
@@ -43,22 +54,46 @@ func (categoryService *categoryServiceImpl) CreateCategory(ctx context.Context,
 	err = categoryService.CategoryRepository.SaveCategory(ctx, tx, &category)
 	helper.PanicIfError(err)
 
-	return helper.ToCategoryResponse(category)
+	response := helper.ToCategoryResponse(category)
+	recordAudit(ctx, tx, categoryService.AuditRepository, category.UserId, domain.AuditEntityCategory, category.Id, domain.AuditActionCreate, nil, response)
+
+	return response
 }
 
-func (categoryService *categoryServiceImpl) DeleteCategory(ctx context.Context, categoryId, userId int) {
+// DeleteCategory moves the category and its expenses to the trash and returns
+// a token that restores them.
+func (categoryService *categoryServiceImpl) DeleteCategory(ctx context.Context, categoryId, userId int) web.UndoTokenResponse {
+	defer helper.AfterCommit(func() {
+		categoryService.CategoryModels.Reset(userId)
+	})
+
 	tx, err := categoryService.DB.Begin()
 	helper.PanicIfError(err)
 	defer helper.CommitOrRollback(tx)
 
-	_, err = categoryService.CategoryRepository.FindCategoryById(ctx, tx, categoryId, userId)
+	category, err := categoryService.CategoryRepository.FindCategoryById(ctx, tx, categoryId, userId)
 	if errors.Is(err, sql.ErrNoRows) {
 		panic(exception.NewNotFoundError("category not found"))
 	}
 	helper.PanicIfError(err)
 
+	expenses, err := categoryService.ExpenseRepository.FindAllExpanse(ctx, tx, userId, domain.ExpenseFilter{CategoryIds: []int{categoryId}})
+	helper.PanicIfError(err)
+
 	err = categoryService.CategoryRepository.DeleteCategory(ctx, tx, categoryId, userId)
 	helper.PanicIfError(err)
+
+	for _, expense := range expenses {
+		recordExpenseChange(ctx, tx, categoryService.AuditRepository, categoryService.WebhookRepository, userId, expense.Id, domain.AuditActionDelete, helper.ToExpenseResponse(expense, category.Name), nil)
+	}
+
+	recordAudit(ctx, tx, categoryService.AuditRepository, userId, domain.AuditEntityCategory, categoryId, domain.AuditActionDelete, helper.ToCategoryResponse(*category), nil)
+
+	undo := saveUndo(ctx, categoryService.UndoRepository, userId, domain.UndoKindDeleteCategory, []domain.UndoStep{
+		{Action: domain.UndoRestoreCategory, EntityId: categoryId},
+	})
+
+	return *undo
 }
 
 func (categoryService *categoryServiceImpl) FindCategoryById(ctx context.Context, categoryId, userId int) web.CategoryResponse {
@@ -0,0 +1,24 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/ryhnfhrza/Expense-Tracker/model/web"
+)
+
+type DataExportService interface {
+	// RequestExport queues a takeout of everything held about the user. A
+	// request while another one is still running returns that one.
+	RequestExport(ctx context.Context, userId int) web.DataExportResponse
+	FindExport(ctx context.Context, exportId int64, userId int) web.DataExportResponse
+	FindAllExport(ctx context.Context, userId int) []web.DataExportResponse
+	// DownloadExport returns the ZIP archive behind a signed download link.
+	DownloadExport(ctx context.Context, exportId int64, expires int64, signature string) []byte
+	// RunPending builds the archives of queued exports and returns how
+	// many it finished.
+	RunPending(ctx context.Context, now time.Time) int
+	// ExpireArchives deletes archives past their expiry and returns how
+	// many it deleted.
+	ExpireArchives(ctx context.Context, now time.Time) int
+}
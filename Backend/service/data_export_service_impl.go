@@ -0,0 +1,379 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/ryhnfhrza/Expense-Tracker/exception"
+	"github.com/ryhnfhrza/Expense-Tracker/helper"
+	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
+	"github.com/ryhnfhrza/Expense-Tracker/model/web"
+	"github.com/ryhnfhrza/Expense-Tracker/repository"
+	"github.com/ryhnfhrza/Expense-Tracker/takeout"
+	"github.com/ryhnfhrza/Expense-Tracker/util"
+)
+
+const (
+	dataExportBatchSize = 5
+	dataExportListLimit = 20
+	dataExportAuditPage = 500
+
+	// dataExportRetention is how long a finished archive is kept, and
+	// dataExportLinkTTL how long one download link works.
+	dataExportRetention = 7 * 24 * time.Hour
+	dataExportLinkTTL   = 15 * time.Minute
+
+	// dataExportLease hands an export to another worker when the one
+	// building it died.
+	dataExportLease = 10 * time.Minute
+)
+
+type dataExportServiceImpl struct {
+	DB                         *sql.DB
+	DataExportRepository       repository.DataExportRepository
+	UserRepository             repository.UserRepository
+	UserIdentityRepository     repository.UserIdentityRepository
+	TwoFactorRepository        repository.TwoFactorRepository
+	CategoryRepository         repository.CategoryRepository
+	ExpenseRepository          repository.ExpenseRepository
+	TrashRepository            repository.TrashRepository
+	RecurringExpenseRepository repository.RecurringExpenseRepository
+	CategoryRuleRepository     repository.CategoryRuleRepository
+	SavedViewRepository        repository.SavedViewRepository
+	WebhookRepository          repository.WebhookRepository
+	AccessTokenRepository      repository.AccessTokenRepository
+	AuditRepository            repository.AuditRepository
+}
+
+func NewDataExportService(db *sql.DB, dataExportRepository repository.DataExportRepository, userRepository repository.UserRepository, userIdentityRepository repository.UserIdentityRepository, twoFactorRepository repository.TwoFactorRepository, categoryRepository repository.CategoryRepository, expenseRepository repository.ExpenseRepository, trashRepository repository.TrashRepository, recurringExpenseRepository repository.RecurringExpenseRepository, categoryRuleRepository repository.CategoryRuleRepository, savedViewRepository repository.SavedViewRepository, webhookRepository repository.WebhookRepository, accessTokenRepository repository.AccessTokenRepository, auditRepository repository.AuditRepository) DataExportService {
+	return &dataExportServiceImpl{
+		DB:                         db,
+		DataExportRepository:       dataExportRepository,
+		UserRepository:             userRepository,
+		UserIdentityRepository:     userIdentityRepository,
+		TwoFactorRepository:        twoFactorRepository,
+		CategoryRepository:         categoryRepository,
+		ExpenseRepository:          expenseRepository,
+		TrashRepository:            trashRepository,
+		RecurringExpenseRepository: recurringExpenseRepository,
+		CategoryRuleRepository:     categoryRuleRepository,
+		SavedViewRepository:        savedViewRepository,
+		WebhookRepository:          webhookRepository,
+		AccessTokenRepository:      accessTokenRepository,
+		AuditRepository:            auditRepository,
+	}
+}
+
+func (service *dataExportServiceImpl) RequestExport(ctx context.Context, userId int) web.DataExportResponse {
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	active, err := service.DataExportRepository.FindActiveExport(ctx, tx, userId)
+	if err == nil {
+		return helper.ToDataExportResponse(*active)
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		panic(err)
+	}
+
+	export := &domain.DataExport{
+		UserId:      userId,
+		Status:      domain.DataExportPending,
+		RequestedAt: time.Now().UTC(),
+	}
+	err = service.DataExportRepository.SaveExport(ctx, tx, export)
+	helper.PanicIfError(err)
+
+	return helper.ToDataExportResponse(*export)
+}
+
+func (service *dataExportServiceImpl) FindExport(ctx context.Context, exportId int64, userId int) web.DataExportResponse {
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	export, err := service.DataExportRepository.FindExportById(ctx, tx, exportId, userId)
+	if err != nil {
+		panic(exception.NewNotFoundError(fmt.Sprintf("data export with id:%d not found", exportId)))
+	}
+
+	response := helper.ToDataExportResponse(*export)
+	if export.Status == domain.DataExportReady {
+		linkExpires := time.Now().UTC().Add(dataExportLinkTTL).Truncate(time.Second)
+		if export.ExpiresAt != nil && export.ExpiresAt.Before(linkExpires) {
+			linkExpires = export.ExpiresAt.UTC().Truncate(time.Second)
+		}
+
+		path := dataExportDownloadPath(export.Id)
+		signature, err := util.SignLink(path, linkExpires)
+		helper.PanicIfError(err)
+
+		query := url.Values{}
+		query.Set("expires", strconv.FormatInt(linkExpires.Unix(), 10))
+		query.Set("signature", signature)
+		response.DownloadUrl = path + "?" + query.Encode()
+		response.DownloadExpiresAt = &linkExpires
+	}
+
+	return response
+}
+
+func (service *dataExportServiceImpl) FindAllExport(ctx context.Context, userId int) []web.DataExportResponse {
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	exports, err := service.DataExportRepository.FindAllExport(ctx, tx, userId, dataExportListLimit)
+	helper.PanicIfError(err)
+
+	return helper.ToDataExportResponses(exports)
+}
+
+func (service *dataExportServiceImpl) DownloadExport(ctx context.Context, exportId int64, expires int64, signature string) []byte {
+	if !util.VerifyLink(dataExportDownloadPath(exportId), expires, signature, time.Now()) {
+		panic(exception.NewForbiddenError("download link is invalid or expired"))
+	}
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	archive, err := service.DataExportRepository.FindExportArchive(ctx, tx, exportId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			panic(exception.NewNotFoundError("the archive has expired, request a new export"))
+		}
+		panic(err)
+	}
+
+	return archive
+}
+
+func (service *dataExportServiceImpl) RunPending(ctx context.Context, now time.Time) int {
+	finished := 0
+	for finished < dataExportBatchSize {
+		export := service.claimExport(ctx, now)
+		if export == nil {
+			break
+		}
+		service.runExport(ctx, export)
+		finished++
+	}
+
+	return finished
+}
+
+func (service *dataExportServiceImpl) ExpireArchives(ctx context.Context, now time.Time) int {
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	expired, err := service.DataExportRepository.ExpireExports(ctx, tx, now)
+	helper.PanicIfError(err)
+
+	return expired
+}
+
+// claimExport marks the next export as running in a short transaction, so
+// no row lock is held while the archive is built.
+func (service *dataExportServiceImpl) claimExport(ctx context.Context, now time.Time) *domain.DataExport {
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	export, err := service.DataExportRepository.ClaimExport(ctx, tx, now.Add(-dataExportLease))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	helper.PanicIfError(err)
+
+	export.Status = domain.DataExportRunning
+	export.StartedAt = &now
+	err = service.DataExportRepository.UpdateExport(ctx, tx, export)
+	helper.PanicIfError(err)
+
+	return export
+}
+
+func (service *dataExportServiceImpl) runExport(ctx context.Context, export *domain.DataExport) {
+	archive, buildErr := service.buildArchive(ctx, export.UserId)
+
+	now := time.Now().UTC()
+	export.CompletedAt = &now
+	if buildErr == nil {
+		expiresAt := now.Add(dataExportRetention)
+		export.Status = domain.DataExportReady
+		export.SizeBytes = int64(len(archive))
+		export.ExpiresAt = &expiresAt
+	} else {
+		log.Printf("data export %d failed: %v", export.Id, buildErr)
+		export.Status = domain.DataExportFailed
+		export.Error = "the archive could not be built, please request a new export"
+	}
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	if buildErr == nil {
+		err = service.DataExportRepository.SaveExportArchive(ctx, tx, export.Id, archive)
+		helper.PanicIfError(err)
+	}
+	err = service.DataExportRepository.UpdateExport(ctx, tx, export)
+	helper.PanicIfError(err)
+}
+
+// buildArchive reads everything in one transaction, so the archive is a
+// consistent snapshot, and zips it.
+func (service *dataExportServiceImpl) buildArchive(ctx context.Context, userId int) (archive []byte, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	content := service.collect(ctx, tx, userId)
+
+	var buf bytes.Buffer
+	err = takeout.Write(&buf, content)
+	helper.PanicIfError(err)
+
+	return buf.Bytes(), nil
+}
+
+func (service *dataExportServiceImpl) collect(ctx context.Context, tx *sql.Tx, userId int) *takeout.Archive {
+	user, err := service.UserRepository.FindById(ctx, tx, userId)
+	helper.PanicIfError(err)
+
+	twoFactor, err := service.TwoFactorRepository.FindTwoFactor(ctx, tx, userId)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		panic(err)
+	}
+
+	identities, err := service.UserIdentityRepository.FindAllIdentity(ctx, tx, userId)
+	helper.PanicIfError(err)
+
+	categories, err := service.CategoryRepository.FindAllCategory(ctx, tx, userId)
+	helper.PanicIfError(err)
+
+	trashedCategories, err := service.TrashRepository.FindTrashedCategories(ctx, tx, userId)
+	helper.PanicIfError(err)
+
+	expenses, err := service.ExpenseRepository.FindAllExpanse(ctx, tx, userId, domain.ExpenseFilter{})
+	helper.PanicIfError(err)
+
+	trashedExpenses, err := service.TrashRepository.FindTrashedExpenses(ctx, tx, userId)
+	helper.PanicIfError(err)
+
+	recurring, err := service.RecurringExpenseRepository.FindAllRecurring(ctx, tx, userId)
+	helper.PanicIfError(err)
+
+	rules, err := service.CategoryRuleRepository.FindAllRule(ctx, tx, userId)
+	helper.PanicIfError(err)
+
+	views, err := service.SavedViewRepository.FindAllView(ctx, tx, userId)
+	helper.PanicIfError(err)
+
+	webhooks, err := service.WebhookRepository.FindAllWebhook(ctx, tx, userId)
+	helper.PanicIfError(err)
+
+	tokens, err := service.AccessTokenRepository.FindAllAccessToken(ctx, tx, userId)
+	helper.PanicIfError(err)
+
+	archive := &takeout.Archive{
+		Manifest: takeout.Manifest{
+			Format:     takeout.Format,
+			Version:    takeout.Version,
+			ExportedAt: time.Now().UTC(),
+			UserId:     userId,
+		},
+		Profile: takeout.Profile{
+			UserProfileResponse: helper.ToUserProfileResponse(*user, twoFactor != nil && twoFactor.ConfirmedAt != nil),
+			Identities:          []takeout.Identity{},
+		},
+	}
+
+	for _, identity := range identities {
+		archive.Profile.Identities = append(archive.Profile.Identities, takeout.Identity{
+			Issuer:    identity.Issuer,
+			Subject:   identity.Subject,
+			Email:     identity.Email,
+			CreatedAt: identity.CreatedAt,
+		})
+	}
+
+	allCategories := slices.Clone(categories)
+	for _, category := range categories {
+		archive.Categories = append(archive.Categories, takeout.Category{Id: category.Id, Name: category.Name})
+	}
+	for _, trashed := range trashedCategories {
+		deletedAt := trashed.DeletedAt
+		allCategories = append(allCategories, trashed.Category)
+		archive.Categories = append(archive.Categories, takeout.Category{Id: trashed.Category.Id, Name: trashed.Category.Name, DeletedAt: &deletedAt})
+	}
+	slices.SortFunc(archive.Categories, func(a, b takeout.Category) int { return a.Id - b.Id })
+
+	names := make(map[int]string, len(allCategories))
+	for _, category := range allCategories {
+		names[category.Id] = category.Name
+	}
+	for _, expense := range expenses {
+		archive.Expenses = append(archive.Expenses, toTakeoutExpense(expense, names[expense.CategoryId], nil))
+	}
+	for _, trashed := range trashedExpenses {
+		deletedAt := trashed.DeletedAt
+		archive.Expenses = append(archive.Expenses, toTakeoutExpense(trashed.Expense, trashed.CategoryName, &deletedAt))
+	}
+	slices.SortFunc(archive.Expenses, func(a, b takeout.Expense) int { return a.Id - b.Id })
+
+	archive.RecurringExpenses = helper.ToRecurringExpenseResponses(recurring, allCategories)
+	archive.CategoryRules = helper.ToCategoryRuleResponses(rules, allCategories)
+	archive.SavedViews = helper.ToSavedViewResponses(views)
+	archive.Webhooks = helper.ToWebhookResponses(webhooks)
+	archive.AccessTokens = helper.ToAccessTokenResponses(tokens)
+
+	filter := domain.AuditFilter{Limit: dataExportAuditPage}
+	for {
+		entries, err := service.AuditRepository.FindAllAudit(ctx, tx, userId, filter)
+		helper.PanicIfError(err)
+
+		for _, entry := range entries {
+			archive.AuditLog = append(archive.AuditLog, helper.ToAuditEntryResponse(entry))
+		}
+		if len(entries) < filter.Limit {
+			break
+		}
+		filter.BeforeId = entries[len(entries)-1].Id
+	}
+
+	return archive
+}
+
+func toTakeoutExpense(expense domain.Expense, categoryName string, deletedAt *time.Time) takeout.Expense {
+	return takeout.Expense{
+		Id:           expense.Id,
+		CategoryId:   expense.CategoryId,
+		CategoryName: categoryName,
+		Description:  expense.Description,
+		Amount:       expense.Amount,
+		CreatedAt:    expense.CreatedAt,
+		DeletedAt:    deletedAt,
+	}
+}
+
+func dataExportDownloadPath(exportId int64) string {
+	return fmt.Sprintf("/api/exports/%d/download", exportId)
+}
@@ -0,0 +1,11 @@
+package service
+
+import (
+	"context"
+
+	"github.com/ryhnfhrza/Expense-Tracker/model/web"
+)
+
+type ExpenseImportService interface {
+	ImportStatement(ctx context.Context, request web.StatementImportRequest) web.StatementImportResponse
+}
@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/ryhnfhrza/Expense-Tracker/classifier"
+	"github.com/ryhnfhrza/Expense-Tracker/exception"
+	"github.com/ryhnfhrza/Expense-Tracker/helper"
+	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
+	"github.com/ryhnfhrza/Expense-Tracker/model/web"
+	"github.com/ryhnfhrza/Expense-Tracker/repository"
+	"github.com/ryhnfhrza/Expense-Tracker/statement"
+)
+
+type expenseImportServiceImpl struct {
+	DB                      *sql.DB
+	Validate                *validator.Validate
+	ExpenseRepository       repository.ExpenseRepository
+	CategoryRepository      repository.CategoryRepository
+	CategoryRuleRepository  repository.CategoryRuleRepository
+	ExpenseImportRepository repository.ExpenseImportRepository
+	AuditRepository         repository.AuditRepository
+	WebhookRepository       repository.WebhookRepository
+	UndoRepository          repository.UndoRedisRepository
+	CategoryModels          *classifier.Store
+}
+
+func NewExpenseImportService(db *sql.DB, validate *validator.Validate, expenseRepository repository.ExpenseRepository, categoryRepository repository.CategoryRepository, categoryRuleRepository repository.CategoryRuleRepository, expenseImportRepository repository.ExpenseImportRepository, auditRepository repository.AuditRepository, webhookRepository repository.WebhookRepository, undoRepository repository.UndoRedisRepository, categoryModels *classifier.Store) ExpenseImportService {
+	return &expenseImportServiceImpl{
+		DB:                      db,
+		Validate:                validate,
+		ExpenseRepository:       expenseRepository,
+		CategoryRepository:      categoryRepository,
+		CategoryRuleRepository:  categoryRuleRepository,
+		ExpenseImportRepository: expenseImportRepository,
+		AuditRepository:         auditRepository,
+		WebhookRepository:       webhookRepository,
+		UndoRepository:          undoRepository,
+		CategoryModels:          categoryModels,
+	}
+}
+
+// ImportStatement turns the debits of a bank statement into expenses in one
+// transaction. Transactions imported before are skipped by their account and
+// bank id. Ones that look like an expense the user already typed in are held
+// back as possible duplicates until their id is passed in Confirm. The
+// category comes from the user's rules, falling back to CategoryId.
+func (service *expenseImportServiceImpl) ImportStatement(ctx context.Context, request web.StatementImportRequest) web.StatementImportResponse {
+	var created []domain.Expense
+	defer helper.AfterCommit(func() {
+		for _, expense := range created {
+			service.CategoryModels.Learn(expense.UserId, expense.CategoryId, expense.Description, expense.Amount)
+		}
+	})
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	err = service.Validate.Struct(request)
+	helper.PanicIfError(err)
+
+	format, transactions, err := statement.Parse(request.Format, request.Data)
+	if err != nil {
+		panic(exception.NewBadRequest(err.Error()))
+	}
+
+	if request.CategoryId > 0 {
+		_, err := service.CategoryRepository.FindCategoryById(ctx, tx, request.CategoryId, request.UserId)
+		if err != nil {
+			panic(exception.NewNotFoundError(fmt.Sprintf("category with id:%d not found", request.CategoryId)))
+		}
+	}
+
+	result := domain.StatementImportResult{Format: format, DryRun: request.DryRun}
+
+	var debits []domain.StatementTransaction
+	for _, transaction := range transactions {
+		if !transaction.Debit {
+			result.SkippedCredits++
+			continue
+		}
+		debits = append(debits, transaction)
+	}
+	result.Debits = len(debits)
+	if len(debits) == 0 {
+		return helper.ToStatementImportResponse(result)
+	}
+
+	var externalIds []string
+	from, to := debits[0].PostedAt, debits[0].PostedAt
+	for _, debit := range debits {
+		externalIds = append(externalIds, debit.ExternalId)
+		if debit.BankId != debit.ExternalId {
+			externalIds = append(externalIds, debit.BankId)
+		}
+		if debit.PostedAt.Before(from) {
+			from = debit.PostedAt
+		}
+		if debit.PostedAt.After(to) {
+			to = debit.PostedAt
+		}
+	}
+
+	imported, err := service.ExpenseImportRepository.FindImportedIds(ctx, tx, request.UserId, externalIds)
+	helper.PanicIfError(err)
+
+	candidates, err := service.ExpenseImportRepository.FindManualExpenses(ctx, tx, request.UserId, from.Add(-statement.DuplicateWindow), to.Add(statement.DuplicateWindow))
+	helper.PanicIfError(err)
+
+	rules, err := service.CategoryRuleRepository.FindAllRule(ctx, tx, request.UserId)
+	helper.PanicIfError(err)
+	matcher, err := helper.NewCategoryRuleMatcher(rules)
+	helper.PanicIfError(err)
+
+	categories, err := service.CategoryRepository.FindAllCategory(ctx, tx, request.UserId)
+	helper.PanicIfError(err)
+	categoryNames := make(map[int]string, len(categories))
+	for _, category := range categories {
+		categoryNames[category.Id] = category.Name
+	}
+
+	confirmed := map[string]bool{}
+	for _, id := range request.Confirm {
+		confirmed[id] = true
+	}
+
+	for _, debit := range debits {
+		item := domain.StatementImportItem{Transaction: debit}
+		result.Items = append(result.Items, item)
+		current := &result.Items[len(result.Items)-1]
+
+		// Imports from before ids were scoped by account were saved under
+		// the bank's id alone.
+		if imported[debit.ExternalId] || imported[debit.BankId] {
+			current.Status = domain.StatementItemAlreadyImported
+			continue
+		}
+		imported[debit.ExternalId] = true
+
+		if duplicate, score := statement.FindDuplicate(debit, candidates); duplicate != nil && !confirmed[debit.ExternalId] {
+			current.Status = domain.StatementItemDuplicate
+			current.DuplicateOf = duplicate.Id
+			current.DuplicateScore = score
+			continue
+		}
+
+		current.CategoryId = request.CategoryId
+		if rule := matcher.Match(debit.Description, debit.Amount); rule != nil {
+			current.CategoryId = rule.CategoryId
+		}
+		if current.CategoryId == 0 {
+			current.Status = domain.StatementItemUncategorized
+			continue
+		}
+
+		if request.DryRun {
+			current.Status = domain.StatementItemWouldImport
+			continue
+		}
+
+		expense := domain.Expense{
+			CategoryId:  current.CategoryId,
+			Description: debit.Description,
+			Amount:      debit.Amount,
+			CreatedAt:   debit.PostedAt.UTC(),
+			UserId:      request.UserId,
+		}
+		err = service.ExpenseRepository.CreateExpense(ctx, tx, &expense)
+		helper.PanicIfError(err)
+
+		err = service.ExpenseImportRepository.SaveImport(ctx, tx, request.UserId, expense.Id, debit.ExternalId, format)
+		helper.PanicIfError(err)
+
+		recordExpenseChange(ctx, tx, service.AuditRepository, service.WebhookRepository, request.UserId, expense.Id, domain.AuditActionCreate, nil, helper.ToExpenseResponse(expense, categoryNames[expense.CategoryId]))
+
+		current.Status = domain.StatementItemImported
+		current.ExpenseId = expense.Id
+		created = append(created, expense)
+	}
+
+	var undoSteps []domain.UndoStep
+	for i := range created {
+		undoSteps = append(undoSteps, domain.UndoStep{Action: domain.UndoRemoveExpense, EntityId: created[i].Id, After: &created[i]})
+	}
+
+	response := helper.ToStatementImportResponse(result)
+	response.Undo = saveUndo(ctx, service.UndoRepository, request.UserId, domain.UndoKindImport, undoSteps)
+
+	return response
+}
@@ -10,8 +10,12 @@ import (
 type ExpenseService interface {
 	CreateExpense(ctx context.Context, request web.ExpenseCreateRequest) web.ExpenseResponse
 	UpdateExpense(ctx context.Context, request web.ExpenseUpdateRequest) web.ExpenseResponse
-	DeleteExpense(ctx context.Context, expenseId, userId int)
+	DeleteExpense(ctx context.Context, expenseId, userId int) web.UndoTokenResponse
 	FindExpenseById(ctx context.Context, expenseId, userId int) web.ExpenseResponse
 	FindAllExpense(ctx context.Context, userId int, filter domain.ExpenseFilter) []web.ExpenseResponse
 	GetSummaryDetails(ctx context.Context, userId int, filter domain.SummaryFilter) web.SummaryResponse
+	QuickAddExpense(ctx context.Context, request web.ExpenseQuickAddRequest) web.ExpenseQuickAddResponse
+	SearchExpense(ctx context.Context, userId int, query domain.ExpenseSearchQuery) web.ExpenseSearchResponse
+	SuggestCategory(ctx context.Context, request web.CategorySuggestRequest) web.CategorySuggestResponse
+	BulkExpense(ctx context.Context, request web.ExpenseBulkRequest) web.ExpenseBulkResponse
 }
@@ -3,16 +3,21 @@ package service
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"net/url"
 	"strings"
 	"time"
 
 	"github.com/go-playground/validator/v10"
+	"github.com/ryhnfhrza/Expense-Tracker/classifier"
 	"github.com/ryhnfhrza/Expense-Tracker/exception"
 	"github.com/ryhnfhrza/Expense-Tracker/helper"
+	"github.com/ryhnfhrza/Expense-Tracker/insights"
 	"github.com/ryhnfhrza/Expense-Tracker/internal/types"
 	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
 	"github.com/ryhnfhrza/Expense-Tracker/model/web"
+	"github.com/ryhnfhrza/Expense-Tracker/parser"
 	"github.com/ryhnfhrza/Expense-Tracker/repository"
 )
 
@@ -22,19 +27,38 @@ type expenseServiceImpl struct {
 	ExpenseRepository       repository.ExpenseRepository
 	CategoryRepository      repository.CategoryRepository
 	ExspenseSummeryReposity repository.ExpenseSummaryRepository
+	ExpenseSearchRepository repository.ExpenseSearchRepository
+	CategoryRuleRepository  repository.CategoryRuleRepository
+	AuditRepository         repository.AuditRepository
+	WebhookRepository       repository.WebhookRepository
+	UndoRepository          repository.UndoRedisRepository
+	CategoryModels          *classifier.Store
+	PreferencesRepository   repository.UserPreferencesRepository
 }
 
-func NewExpenseService(db *sql.DB, validate *validator.Validate, expenseRepository repository.ExpenseRepository, categoryRepository repository.CategoryRepository, exspenseSummeryReposity repository.ExpenseSummaryRepository) ExpenseService {
+func NewExpenseService(db *sql.DB, validate *validator.Validate, expenseRepository repository.ExpenseRepository, categoryRepository repository.CategoryRepository, exspenseSummeryReposity repository.ExpenseSummaryRepository, expenseSearchRepository repository.ExpenseSearchRepository, categoryRuleRepository repository.CategoryRuleRepository, auditRepository repository.AuditRepository, webhookRepository repository.WebhookRepository, undoRepository repository.UndoRedisRepository, categoryModels *classifier.Store, preferencesRepository repository.UserPreferencesRepository) ExpenseService {
 	return &expenseServiceImpl{
 		DB:                      db,
 		Validate:                validate,
 		ExpenseRepository:       expenseRepository,
 		CategoryRepository:      categoryRepository,
 		ExspenseSummeryReposity: exspenseSummeryReposity,
+		ExpenseSearchRepository: expenseSearchRepository,
+		CategoryRuleRepository:  categoryRuleRepository,
+		AuditRepository:         auditRepository,
+		WebhookRepository:       webhookRepository,
+		UndoRepository:          undoRepository,
+		CategoryModels:          categoryModels,
+		PreferencesRepository:   preferencesRepository,
 	}
 }
 
 func (service *expenseServiceImpl) CreateExpense(ctx context.Context, request web.ExpenseCreateRequest) web.ExpenseResponse {
+	var expense domain.Expense
+	defer helper.AfterCommit(func() {
+		service.CategoryModels.Learn(expense.UserId, expense.CategoryId, expense.Description, expense.Amount)
+	})
+
 	tx, err := service.DB.Begin()
 	helper.PanicIfError(err)
 	defer helper.CommitOrRollback(tx)
@@ -42,30 +66,53 @@ func (service *expenseServiceImpl) CreateExpense(ctx context.Context, request we
 	err = service.Validate.Struct(request)
 	helper.PanicIfError(err)
 
+	if request.CategoryId == 0 {
+		rule := service.matchCategoryRule(ctx, tx, request.UserId, request.Description, request.Amount)
+		if rule == nil {
+			panic(exception.NewBadRequest("category_id is required: no category rule matched this expense"))
+		}
+		request.CategoryId = rule.CategoryId
+	}
+
 	category, err := service.CategoryRepository.FindCategoryById(ctx, tx, request.CategoryId, request.UserId)
 	if err != nil {
 		panic(exception.NewNotFoundError(fmt.Sprintf("category with id:%d not found", request.CategoryId)))
 	}
 
-	if request.CreatedAt == nil || request.CreatedAt.Time.IsZero() {
-		request.CreatedAt = &types.CustomTime{Time: time.Now().UTC()}
+	createdAt := time.Now().UTC()
+	if request.CreatedAt != nil && !request.CreatedAt.Time.IsZero() {
+		preferences := findPreferences(ctx, tx, service.PreferencesRepository, request.UserId)
+		createdAt = request.CreatedAt.InLocation(helper.PreferredLocation(preferences))
 	}
 
-	expense := domain.Expense{
+	expense = domain.Expense{
 		CategoryId:  request.CategoryId,
 		Description: request.Description,
 		Amount:      request.Amount,
-		CreatedAt:   request.CreatedAt.Time,
+		CreatedAt:   createdAt,
 		UserId:      request.UserId,
 	}
 
 	err = service.ExpenseRepository.CreateExpense(ctx, tx, &expense)
 	helper.PanicIfError(err)
 
-	return helper.ToExpenseResponse(expense, category.Name)
+	response := helper.ToExpenseResponse(expense, category.Name)
+	recordExpenseChange(ctx, tx, service.AuditRepository, service.WebhookRepository, expense.UserId, expense.Id, domain.AuditActionCreate, nil, response)
+
+	for _, anomaly := range service.detectExpenseAnomalies(ctx, tx, expense) {
+		response.Anomalies = append(response.Anomalies, helper.ToAnomalyResponse(anomaly, category.Name))
+	}
+
+	return response
 }
 
 func (service *expenseServiceImpl) UpdateExpense(ctx context.Context, request web.ExpenseUpdateRequest) web.ExpenseResponse {
+	var before, after domain.Expense
+	defer helper.AfterCommit(func() {
+		service.CategoryModels.Forget(before.UserId, before.CategoryId, before.Description, before.Amount)
+		service.CategoryModels.Learn(after.UserId, after.CategoryId, after.Description, after.Amount)
+	})
+
 	tx, err := service.DB.Begin()
 	helper.PanicIfError(err)
 	defer helper.CommitOrRollback(tx)
@@ -74,6 +121,12 @@ func (service *expenseServiceImpl) UpdateExpense(ctx context.Context, request we
 	if err != nil {
 		panic(exception.NewNotFoundError(fmt.Sprintf("expense with id:%d not found", request.Id)))
 	}
+	before = *expense
+	before.UserId = request.UserId
+	beforeResponse := helper.ToExpenseResponse(before, categoryName(ctx, tx, service.CategoryRepository, before.CategoryId, before.UserId))
+	if request.Version != 0 && request.Version != expense.Version {
+		panic(staleExpenseError(beforeResponse))
+	}
 
 	if request.CategoryId == nil {
 		catID := expense.CategoryId
@@ -106,27 +159,71 @@ func (service *expenseServiceImpl) UpdateExpense(ctx context.Context, request we
 	expense.CategoryId = *request.CategoryId
 	expense.Description = request.Description
 	expense.Amount = *request.Amount
-	expense.CreatedAt = request.CreatedAt.Time.UTC()
+	preferences := findPreferences(ctx, tx, service.PreferencesRepository, request.UserId)
+	expense.CreatedAt = request.CreatedAt.InLocation(helper.PreferredLocation(preferences))
 	expense.UserId = request.UserId
 
 	expense, err = service.ExpenseRepository.UpdateExpense(ctx, tx, expense)
+	if errors.Is(err, sql.ErrNoRows) {
+		// Another writer got in between the read above and this update.
+		current, findErr := service.ExpenseRepository.FindExpanseById(ctx, tx, request.Id, request.UserId)
+		if findErr != nil {
+			panic(exception.NewNotFoundError(fmt.Sprintf("expense with id:%d not found", request.Id)))
+		}
+		panic(staleExpenseError(helper.ToExpenseResponse(*current, categoryName(ctx, tx, service.CategoryRepository, current.CategoryId, request.UserId))))
+	}
 	helper.PanicIfError(err)
+	after = *expense
 
-	return helper.ToExpenseResponse(*expense, category.Name)
+	response := helper.ToExpenseResponse(*expense, category.Name)
+	recordExpenseChange(ctx, tx, service.AuditRepository, service.WebhookRepository, request.UserId, expense.Id, domain.AuditActionUpdate, beforeResponse, response)
+
+	return response
 }
 
-func (service *expenseServiceImpl) DeleteExpense(ctx context.Context, expenseId, userId int) {
+// staleExpenseError answers an update whose If-Match no longer matches,
+// carrying the current row so the client can merge and retry.
+func staleExpenseError(current web.ExpenseResponse) error {
+	return exception.NewPreconditionFailedError(
+		fmt.Sprintf("expense with id:%d has changed, it is now at version %d", current.Id, current.Version),
+		current,
+		helper.FormatETag(current.Version),
+	)
+}
+
+// DeleteExpense moves the expense to the trash and returns a token that
+// restores it.
+func (service *expenseServiceImpl) DeleteExpense(ctx context.Context, expenseId, userId int) web.UndoTokenResponse {
+	var deleted domain.Expense
+	defer helper.AfterCommit(func() {
+		service.CategoryModels.Forget(deleted.UserId, deleted.CategoryId, deleted.Description, deleted.Amount)
+	})
+
 	tx, err := service.DB.Begin()
 	helper.PanicIfError(err)
 	defer helper.CommitOrRollback(tx)
 
-	_, err = service.ExpenseRepository.FindExpanseById(ctx, tx, expenseId, userId)
+	expense, err := service.ExpenseRepository.FindExpanseById(ctx, tx, expenseId, userId)
 	if err != nil {
 		panic(exception.NewNotFoundError(fmt.Sprintf("expense with id:%d not found", expenseId)))
 	}
+	deleted = *expense
+	deleted.UserId = userId
 
 	err = service.ExpenseRepository.DeleteExpanse(ctx, tx, expenseId, userId)
+	if errors.Is(err, sql.ErrNoRows) {
+		panic(exception.NewNotFoundError(fmt.Sprintf("expense with id:%d not found", expenseId)))
+	}
 	helper.PanicIfError(err)
+
+	beforeResponse := helper.ToExpenseResponse(deleted, categoryName(ctx, tx, service.CategoryRepository, deleted.CategoryId, userId))
+	recordExpenseChange(ctx, tx, service.AuditRepository, service.WebhookRepository, userId, expenseId, domain.AuditActionDelete, beforeResponse, nil)
+
+	undo := saveUndo(ctx, service.UndoRepository, userId, domain.UndoKindDeleteExpense, []domain.UndoStep{
+		{Action: domain.UndoRestoreExpense, EntityId: expenseId},
+	})
+
+	return *undo
 }
 
 func (service *expenseServiceImpl) FindExpenseById(ctx context.Context, expenseId, userId int) web.ExpenseResponse {
@@ -194,6 +291,32 @@ func (service *expenseServiceImpl) GetSummaryDetails(ctx context.Context, userId
 	helper.PanicIfError(err)
 	defer helper.CommitOrRollback(tx)
 
+	preferences := findPreferences(ctx, tx, service.PreferencesRepository, userId)
+	if filter.Timezone != "" {
+		preferences.Timezone = filter.Timezone
+	}
+	loc := helper.PreferredLocation(preferences)
+
+	// Periods follow the user's calendar, so an expense at 23:30 local time
+	// counts on that day even when it is already the next day in UTC.
+	var from, to time.Time
+	switch {
+	case filter.Week != nil:
+		from, to = helper.WeekRange(*filter.Week, preferences)
+	case filter.Day != nil:
+		from = time.Date(*filter.Year, time.Month(*filter.Month), *filter.Day, 0, 0, 0, 0, loc)
+		to = from.AddDate(0, 0, 1)
+	case filter.Month != nil:
+		from = time.Date(*filter.Year, time.Month(*filter.Month), 1, 0, 0, 0, 0, loc)
+		to = from.AddDate(0, 1, 0)
+	case filter.Year != nil:
+		from = time.Date(*filter.Year, time.January, 1, 0, 0, 0, 0, loc)
+		to = from.AddDate(1, 0, 0)
+	}
+	if !from.IsZero() {
+		filter.From, filter.To = &from, &to
+	}
+
 	summaries, err := service.ExspenseSummeryReposity.GetSummaryDetails(ctx, tx, userId, filter)
 	helper.PanicIfError(err)
 
@@ -202,5 +325,456 @@ func (service *expenseServiceImpl) GetSummaryDetails(ctx context.Context, userId
 		totalAll += s.Total
 	}
 
-	return helper.ToSummaryResult(totalAll, summaries)
+	return helper.ToSummaryResult(totalAll, summaries, preferences)
+}
+
+func (service *expenseServiceImpl) SearchExpense(ctx context.Context, userId int, query domain.ExpenseSearchQuery) web.ExpenseSearchResponse {
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	if query.Limit <= 0 {
+		query.Limit = 20
+	}
+	if query.Limit > 100 {
+		query.Limit = 100
+	}
+	if query.Offset < 0 {
+		query.Offset = 0
+	}
+
+	results, total, err := service.ExpenseSearchRepository.SearchExpense(ctx, tx, userId, query)
+	helper.PanicIfError(err)
+
+	return helper.ToExpenseSearchResponse(results, query, total)
+}
+
+func (service *expenseServiceImpl) QuickAddExpense(ctx context.Context, request web.ExpenseQuickAddRequest) web.ExpenseQuickAddResponse {
+	var created domain.Expense
+	defer helper.AfterCommit(func() {
+		service.CategoryModels.Learn(created.UserId, created.CategoryId, created.Description, created.Amount)
+	})
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	err = service.Validate.Struct(request)
+	helper.PanicIfError(err)
+
+	tz := request.Timezone
+	if tz == "" {
+		tz = findPreferences(ctx, tx, service.PreferencesRepository, request.UserId).Timezone
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		panic(exception.NewBadRequest("invalid tz"))
+	}
+
+	categories, err := service.CategoryRepository.FindAllCategory(ctx, tx, request.UserId)
+	helper.PanicIfError(err)
+
+	parsed := parser.ParseExpenseText(request.Text, time.Now().In(loc), categories)
+
+	if request.CategoryId != nil {
+		category, err := service.CategoryRepository.FindCategoryById(ctx, tx, *request.CategoryId, request.UserId)
+		if err != nil {
+			panic(exception.NewNotFoundError(fmt.Sprintf("category with id:%d not found", *request.CategoryId)))
+		}
+		parsed.CategoryId = category.Id
+		parsed.CategoryName = category.Name
+		parsed.CategoryKeyword = ""
+	} else if parsed.CategoryId == 0 && parsed.Amount != nil {
+		if rule := service.matchCategoryRule(ctx, tx, request.UserId, parsed.Description, *parsed.Amount); rule != nil {
+			for _, category := range categories {
+				if category.Id == rule.CategoryId {
+					parsed.CategoryId = category.Id
+					parsed.CategoryName = category.Name
+					parsed.CategoryKeyword = "rule:" + rule.Name
+					break
+				}
+			}
+		}
+	}
+
+	response := web.ExpenseQuickAddResponse{
+		Parsed: helper.ToParsedExpenseResponse(parsed),
+	}
+	if !request.Create {
+		return response
+	}
+
+	if parsed.Amount == nil {
+		panic(exception.NewBadRequest("could not find an amount in text, e.g. \"kopi 25rb\" or \"Rp 25.000\""))
+	}
+	if parsed.CategoryId == 0 {
+		panic(exception.NewBadRequest("could not match a category from text, please pass category_id"))
+	}
+
+	expense := domain.Expense{
+		CategoryId:  parsed.CategoryId,
+		Description: parsed.Description,
+		Amount:      *parsed.Amount,
+		CreatedAt:   parsed.CreatedAt.UTC(),
+		UserId:      request.UserId,
+	}
+
+	err = service.ExpenseRepository.CreateExpense(ctx, tx, &expense)
+	helper.PanicIfError(err)
+	created = expense
+
+	expenseResponse := helper.ToExpenseResponse(expense, parsed.CategoryName)
+	response.Expense = &expenseResponse
+	recordExpenseChange(ctx, tx, service.AuditRepository, service.WebhookRepository, expense.UserId, expense.Id, domain.AuditActionCreate, nil, expenseResponse)
+
+	return response
+}
+
+// matchCategoryRule returns the first of the user's category rules that
+// matches, or nil when none does.
+func (service *expenseServiceImpl) matchCategoryRule(ctx context.Context, tx *sql.Tx, userId int, description string, amount float64) *domain.CategoryRule {
+	rules, err := service.CategoryRuleRepository.FindAllRule(ctx, tx, userId)
+	helper.PanicIfError(err)
+
+	matcher, err := helper.NewCategoryRuleMatcher(rules)
+	helper.PanicIfError(err)
+
+	return matcher.Match(description, amount)
+}
+
+// SuggestCategory ranks the user's categories for a description and amount
+// with a model trained on their own expenses. The model is built on first use
+// and kept current by CreateExpense, UpdateExpense and DeleteExpense.
+func (service *expenseServiceImpl) SuggestCategory(ctx context.Context, request web.CategorySuggestRequest) web.CategorySuggestResponse {
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	err = service.Validate.Struct(request)
+	helper.PanicIfError(err)
+
+	if request.Limit == 0 {
+		request.Limit = 3
+	}
+
+	model := service.CategoryModels.Load(request.UserId, func() *classifier.NaiveBayes {
+		expenses, err := service.ExpenseRepository.FindAllExpanse(ctx, tx, request.UserId, domain.ExpenseFilter{})
+		helper.PanicIfError(err)
+
+		model := classifier.NewNaiveBayes()
+		for _, expense := range expenses {
+			model.Add(expense.CategoryId, classifier.Features(expense.Description, expense.Amount))
+		}
+		return model
+	})
+
+	categories, err := service.CategoryRepository.FindAllCategory(ctx, tx, request.UserId)
+	helper.PanicIfError(err)
+
+	features := classifier.Features(request.Description, request.Amount)
+	predictions := service.CategoryModels.Predict(model, features, 0)
+	response := helper.ToCategorySuggestResponse(model.Docs(), predictions, categories)
+	if len(response.Suggestions) > request.Limit {
+		response.Suggestions = response.Suggestions[:request.Limit]
+	}
+
+	return response
+}
+
+// detectExpenseAnomalies checks a freshly created expense against the history
+// of its category, so the client can point out an unusual entry right away.
+func (service *expenseServiceImpl) detectExpenseAnomalies(ctx context.Context, tx *sql.Tx, expense domain.Expense) []domain.Anomaly {
+	since := expense.CreatedAt.Add(-insights.BaselineLookback)
+	history, err := service.ExpenseRepository.FindAllExpanse(ctx, tx, expense.UserId, domain.ExpenseFilter{
+		CategoryIds:  []int{expense.CategoryId},
+		CreatedAfter: &since,
+	})
+	helper.PanicIfError(err)
+
+	var anomalies []domain.Anomaly
+	for _, anomaly := range insights.DetectAnomalies(history, expense.CreatedAt, expense.CreatedAt) {
+		if anomaly.Expense.Id == expense.Id {
+			anomalies = append(anomalies, anomaly)
+		}
+	}
+	return anomalies
+}
+
+const maxBulkExpenses = 1000
+
+// Statuses of the items of a bulk request.
+const (
+	bulkStatusCreated  = "created"
+	bulkStatusUpdated  = "updated"
+	bulkStatusDeleted  = "deleted"
+	bulkStatusNotFound = "not_found"
+	bulkStatusInvalid  = "invalid"
+	bulkStatusConflict = "conflict"
+)
+
+// BulkExpense runs a bulk create, update or delete in one transaction.
+// Items that fail, such as ids the user does not own, are reported and
+// skipped; the rest are committed together and can be undone as one.
+func (service *expenseServiceImpl) BulkExpense(ctx context.Context, request web.ExpenseBulkRequest) web.ExpenseBulkResponse {
+	defer helper.AfterCommit(func() {
+		service.CategoryModels.Reset(request.UserId)
+	})
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	err = service.Validate.Struct(request)
+	helper.PanicIfError(err)
+
+	categories, err := service.CategoryRepository.FindAllCategory(ctx, tx, request.UserId)
+	helper.PanicIfError(err)
+	categoryNames := make(map[int]string, len(categories))
+	for _, category := range categories {
+		categoryNames[category.Id] = category.Name
+	}
+
+	var (
+		items     []web.ExpenseBulkItemResponse
+		undoSteps []domain.UndoStep
+		undoKind  string
+	)
+
+	switch request.Action {
+	case "create":
+		if len(request.Items) == 0 {
+			panic(exception.NewBadRequest("items is required for create"))
+		}
+		items, undoSteps = service.bulkCreate(ctx, tx, request, categoryNames)
+		undoKind = domain.UndoKindBulkCreate
+
+	case "update":
+		if request.Set == nil || (request.Set.CategoryId == nil && request.Set.Description == nil && request.Set.Amount == nil && request.Set.CreatedAt == nil) {
+			panic(exception.NewBadRequest("set needs at least one of category_id, description, amount or created_at"))
+		}
+		if request.Set.CategoryId != nil {
+			if _, ok := categoryNames[*request.Set.CategoryId]; !ok {
+				panic(exception.NewNotFoundError(fmt.Sprintf("category with id:%d not found", *request.Set.CategoryId)))
+			}
+		}
+		items, undoSteps = service.bulkUpdate(ctx, tx, request, service.bulkTargets(ctx, tx, request), categoryNames)
+		undoKind = domain.UndoKindBulkUpdate
+
+	case "delete":
+		items, undoSteps = service.bulkDelete(ctx, tx, request.UserId, service.bulkTargets(ctx, tx, request), categoryNames)
+		undoKind = domain.UndoKindBulkDelete
+	}
+
+	response := web.ExpenseBulkResponse{
+		Action: request.Action,
+		Total:  len(items),
+		Items:  items,
+	}
+	for _, item := range items {
+		if item.Status == bulkStatusNotFound || item.Status == bulkStatusInvalid || item.Status == bulkStatusConflict {
+			response.Failed++
+		} else {
+			response.Succeeded++
+		}
+	}
+	response.Undo = saveUndo(ctx, service.UndoRepository, request.UserId, undoKind, undoSteps)
+
+	return response
+}
+
+// bulkTarget is an expense picked by ids or by filter; Expense is nil for an
+// id the user has no expense with.
+type bulkTarget struct {
+	Id      int
+	Expense *domain.Expense
+}
+
+func (service *expenseServiceImpl) bulkTargets(ctx context.Context, tx *sql.Tx, request web.ExpenseBulkRequest) []bulkTarget {
+	if (len(request.Ids) > 0) == (request.Filter != nil) {
+		panic(exception.NewBadRequest("pass either ids or filter"))
+	}
+
+	var targets []bulkTarget
+	if len(request.Ids) > 0 {
+		seen := map[int]bool{}
+		for _, id := range request.Ids {
+			if seen[id] {
+				panic(exception.NewBadRequest(fmt.Sprintf("ids lists %d more than once", id)))
+			}
+			seen[id] = true
+
+			target := bulkTarget{Id: id}
+			if expense, err := service.ExpenseRepository.FindExpanseById(ctx, tx, id, request.UserId); err == nil {
+				expense.UserId = request.UserId
+				target.Expense = expense
+			}
+			targets = append(targets, target)
+		}
+		return targets
+	}
+
+	query := url.Values{}
+	for key, value := range request.Filter {
+		query.Set(key, value)
+	}
+	if query.Get("tz") == "" {
+		query.Set("tz", findPreferences(ctx, tx, service.PreferencesRepository, request.UserId).Timezone)
+	}
+	filter, err := helper.ParseExpenseFilter(query)
+	if err != nil {
+		panic(exception.NewBadRequest("invalid filter: " + err.Error()))
+	}
+	if filter.Limit <= 0 || filter.Limit > maxBulkExpenses {
+		filter.Limit = maxBulkExpenses + 1
+	}
+
+	expenses, err := service.ExpenseRepository.FindAllExpanse(ctx, tx, request.UserId, filter)
+	helper.PanicIfError(err)
+	if len(expenses) > maxBulkExpenses {
+		panic(exception.NewBadRequest(fmt.Sprintf("filter matches more than %d expenses, narrow it down", maxBulkExpenses)))
+	}
+
+	for i := range expenses {
+		expenses[i].UserId = request.UserId
+		targets = append(targets, bulkTarget{Id: expenses[i].Id, Expense: &expenses[i]})
+	}
+
+	return targets
+}
+
+func (service *expenseServiceImpl) bulkCreate(ctx context.Context, tx *sql.Tx, request web.ExpenseBulkRequest, categoryNames map[int]string) ([]web.ExpenseBulkItemResponse, []domain.UndoStep) {
+	var (
+		items     []web.ExpenseBulkItemResponse
+		undoSteps []domain.UndoStep
+	)
+	loc := helper.PreferredLocation(findPreferences(ctx, tx, service.PreferencesRepository, request.UserId))
+	for i, itemRequest := range request.Items {
+		item := web.ExpenseBulkItemResponse{Index: i}
+		itemRequest.UserId = request.UserId
+
+		if err := service.Validate.Struct(itemRequest); err != nil {
+			item.Status, item.Error = bulkStatusInvalid, err.Error()
+			items = append(items, item)
+			continue
+		}
+
+		if itemRequest.CategoryId == 0 {
+			if rule := service.matchCategoryRule(ctx, tx, request.UserId, itemRequest.Description, itemRequest.Amount); rule != nil {
+				itemRequest.CategoryId = rule.CategoryId
+			}
+		}
+		name, ok := categoryNames[itemRequest.CategoryId]
+		if !ok {
+			item.Status, item.Error = bulkStatusInvalid, "category_id is missing or not found"
+			items = append(items, item)
+			continue
+		}
+
+		createdAt := time.Now().UTC()
+		if itemRequest.CreatedAt != nil && !itemRequest.CreatedAt.Time.IsZero() {
+			createdAt = itemRequest.CreatedAt.InLocation(loc)
+		}
+
+		expense := domain.Expense{
+			CategoryId:  itemRequest.CategoryId,
+			Description: itemRequest.Description,
+			Amount:      itemRequest.Amount,
+			CreatedAt:   createdAt,
+			UserId:      request.UserId,
+		}
+		err := service.ExpenseRepository.CreateExpense(ctx, tx, &expense)
+		helper.PanicIfError(err)
+
+		expenseResponse := helper.ToExpenseResponse(expense, name)
+		recordExpenseChange(ctx, tx, service.AuditRepository, service.WebhookRepository, request.UserId, expense.Id, domain.AuditActionCreate, nil, expenseResponse)
+
+		item.Id, item.Status, item.Expense = expense.Id, bulkStatusCreated, &expenseResponse
+		items = append(items, item)
+		undoSteps = append(undoSteps, domain.UndoStep{Action: domain.UndoRemoveExpense, EntityId: expense.Id, After: &expense})
+	}
+
+	return items, undoSteps
+}
+
+func (service *expenseServiceImpl) bulkUpdate(ctx context.Context, tx *sql.Tx, request web.ExpenseBulkRequest, targets []bulkTarget, categoryNames map[int]string) ([]web.ExpenseBulkItemResponse, []domain.UndoStep) {
+	var (
+		items     []web.ExpenseBulkItemResponse
+		undoSteps []domain.UndoStep
+	)
+	loc := helper.PreferredLocation(findPreferences(ctx, tx, service.PreferencesRepository, request.UserId))
+	for i, target := range targets {
+		item := web.ExpenseBulkItemResponse{Index: i, Id: target.Id}
+		if target.Expense == nil {
+			item.Status = bulkStatusNotFound
+			items = append(items, item)
+			continue
+		}
+
+		before := *target.Expense
+		after := before
+		if request.Set.CategoryId != nil {
+			after.CategoryId = *request.Set.CategoryId
+		}
+		if request.Set.Description != nil {
+			after.Description = *request.Set.Description
+		}
+		if request.Set.Amount != nil {
+			after.Amount = *request.Set.Amount
+		}
+		if request.Set.CreatedAt != nil && !request.Set.CreatedAt.Time.IsZero() {
+			after.CreatedAt = request.Set.CreatedAt.InLocation(loc)
+		}
+
+		_, err := service.ExpenseRepository.UpdateExpense(ctx, tx, &after)
+		if errors.Is(err, sql.ErrNoRows) {
+			// Edited or deleted since the targets were read.
+			item.Status, item.Error = bulkStatusConflict, "expense changed since it was read"
+			items = append(items, item)
+			continue
+		}
+		helper.PanicIfError(err)
+
+		beforeResponse := helper.ToExpenseResponse(before, categoryNames[before.CategoryId])
+		afterResponse := helper.ToExpenseResponse(after, categoryNames[after.CategoryId])
+		recordExpenseChange(ctx, tx, service.AuditRepository, service.WebhookRepository, request.UserId, after.Id, domain.AuditActionUpdate, beforeResponse, afterResponse)
+
+		item.Status, item.Expense = bulkStatusUpdated, &afterResponse
+		items = append(items, item)
+		undoSteps = append(undoSteps, domain.UndoStep{Action: domain.UndoRevertExpense, EntityId: after.Id, Before: &before, After: &after})
+	}
+
+	return items, undoSteps
+}
+
+func (service *expenseServiceImpl) bulkDelete(ctx context.Context, tx *sql.Tx, userId int, targets []bulkTarget, categoryNames map[int]string) ([]web.ExpenseBulkItemResponse, []domain.UndoStep) {
+	var (
+		items     []web.ExpenseBulkItemResponse
+		undoSteps []domain.UndoStep
+	)
+	for i, target := range targets {
+		item := web.ExpenseBulkItemResponse{Index: i, Id: target.Id}
+		if target.Expense == nil {
+			item.Status = bulkStatusNotFound
+			items = append(items, item)
+			continue
+		}
+
+		err := service.ExpenseRepository.DeleteExpanse(ctx, tx, target.Id, userId)
+		if errors.Is(err, sql.ErrNoRows) {
+			// Trashed by another request since the targets were read.
+			item.Status = bulkStatusNotFound
+			items = append(items, item)
+			continue
+		}
+		helper.PanicIfError(err)
+
+		beforeResponse := helper.ToExpenseResponse(*target.Expense, categoryNames[target.Expense.CategoryId])
+		recordExpenseChange(ctx, tx, service.AuditRepository, service.WebhookRepository, userId, target.Id, domain.AuditActionDelete, beforeResponse, nil)
+
+		item.Status = bulkStatusDeleted
+		items = append(items, item)
+		undoSteps = append(undoSteps, domain.UndoStep{Action: domain.UndoRestoreExpense, EntityId: target.Id})
+	}
+
+	return items, undoSteps
 }
@@ -0,0 +1,14 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/ryhnfhrza/Expense-Tracker/model/web"
+)
+
+type InsightService interface {
+	FindAnomalies(ctx context.Context, userId, days int) web.AnomalyListResponse
+	Forecast(ctx context.Context, userId, months int, loc *time.Location) web.ForecastResponse
+	FindSubscriptions(ctx context.Context, userId int) web.SubscriptionListResponse
+}
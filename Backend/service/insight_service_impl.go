@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/ryhnfhrza/Expense-Tracker/helper"
+	"github.com/ryhnfhrza/Expense-Tracker/insights"
+	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
+	"github.com/ryhnfhrza/Expense-Tracker/model/web"
+	"github.com/ryhnfhrza/Expense-Tracker/repository"
+)
+
+type insightServiceImpl struct {
+	DB                  *sql.DB
+	ExpenseRepository   repository.ExpenseRepository
+	CategoryRepository  repository.CategoryRepository
+	SummaryRepository   repository.ExpenseSummaryRepository
+	RecurringRepository repository.RecurringExpenseRepository
+}
+
+func NewInsightService(db *sql.DB, expenseRepository repository.ExpenseRepository, categoryRepository repository.CategoryRepository, summaryRepository repository.ExpenseSummaryRepository, recurringRepository repository.RecurringExpenseRepository) InsightService {
+	return &insightServiceImpl{
+		DB:                  db,
+		ExpenseRepository:   expenseRepository,
+		CategoryRepository:  categoryRepository,
+		SummaryRepository:   summaryRepository,
+		RecurringRepository: recurringRepository,
+	}
+}
+
+// FindAnomalies lists the expenses of the last days days that stand out
+// against the year of history before them.
+func (service *insightServiceImpl) FindAnomalies(ctx context.Context, userId, days int) web.AnomalyListResponse {
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	to := time.Now().UTC()
+	from := to.AddDate(0, 0, -days)
+	since := from.Add(-insights.BaselineLookback)
+
+	history, err := service.ExpenseRepository.FindAllExpanse(ctx, tx, userId, domain.ExpenseFilter{CreatedAfter: &since})
+	helper.PanicIfError(err)
+
+	categories, err := service.CategoryRepository.FindAllCategory(ctx, tx, userId)
+	helper.PanicIfError(err)
+
+	anomalies := insights.DetectAnomalies(history, from, to)
+
+	return helper.ToAnomalyListResponse(from, to, anomalies, categories)
+}
+
+// Forecast projects this month's total per category and the next months,
+// following the calendar of loc.
+func (service *insightServiceImpl) Forecast(ctx context.Context, userId, months int, loc *time.Location) web.ForecastResponse {
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	now := time.Now().In(loc)
+	from := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, loc).AddDate(0, -insights.ForecastHistoryMonths, 0)
+	to := time.Date(now.Year(), now.Month()+1, 1, 0, 0, 0, 0, loc)
+
+	records, err := service.SummaryRepository.GetSummaryRecords(ctx, tx, userId, from, to)
+	helper.PanicIfError(err)
+
+	recurring := insights.FindRecurringCharges(records, now)
+	forecast := insights.ForecastSpending(records, recurring, now, months)
+
+	return helper.ToForecastResponse(forecast)
+}
+
+// FindSubscriptions lists the charges the user pays at a regular cadence,
+// detected from the last two years of expenses.
+func (service *insightServiceImpl) FindSubscriptions(ctx context.Context, userId int) web.SubscriptionListResponse {
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	now := time.Now().UTC()
+	since := now.Add(-insights.SubscriptionLookback)
+
+	expenses, err := service.ExpenseRepository.FindAllExpanse(ctx, tx, userId, domain.ExpenseFilter{CreatedAfter: &since})
+	helper.PanicIfError(err)
+
+	categories, err := service.CategoryRepository.FindAllCategory(ctx, tx, userId)
+	helper.PanicIfError(err)
+
+	recurring, err := service.RecurringRepository.FindAllRecurring(ctx, tx, userId)
+	helper.PanicIfError(err)
+
+	subscriptions := insights.FindSubscriptions(expenses, now)
+
+	return helper.ToSubscriptionListResponse(subscriptions, categories, recurring)
+}
@@ -0,0 +1,23 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/ryhnfhrza/Expense-Tracker/helper"
+	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
+	"github.com/ryhnfhrza/Expense-Tracker/repository"
+)
+
+// findPreferences returns the user's saved preferences, or the defaults when
+// they never saved any.
+func findPreferences(ctx context.Context, tx *sql.Tx, preferencesRepository repository.UserPreferencesRepository, userId int) domain.UserPreferences {
+	preferences, err := preferencesRepository.FindPreferences(ctx, tx, userId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.DefaultUserPreferences(userId)
+	}
+	helper.PanicIfError(err)
+
+	return *preferences
+}
@@ -0,0 +1,15 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/ryhnfhrza/Expense-Tracker/model/web"
+)
+
+type RecurringExpenseService interface {
+	ConvertSubscription(ctx context.Context, request web.RecurringExpenseCreateRequest) web.RecurringExpenseResponse
+	DeleteRecurring(ctx context.Context, recurringId, userId int)
+	FindAllRecurring(ctx context.Context, userId int) []web.RecurringExpenseResponse
+	RunDue(ctx context.Context, now time.Time) int
+}
@@ -0,0 +1,191 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/ryhnfhrza/Expense-Tracker/classifier"
+	"github.com/ryhnfhrza/Expense-Tracker/exception"
+	"github.com/ryhnfhrza/Expense-Tracker/helper"
+	"github.com/ryhnfhrza/Expense-Tracker/insights"
+	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
+	"github.com/ryhnfhrza/Expense-Tracker/model/web"
+	"github.com/ryhnfhrza/Expense-Tracker/repository"
+)
+
+const (
+	// recurringBatchSize rules are processed per RunDue call.
+	recurringBatchSize = 100
+	// maxRecurringCatchUp bounds the expenses created for one rule in one
+	// run, e.g. after the job was down for a long time.
+	maxRecurringCatchUp = 12
+)
+
+type recurringExpenseServiceImpl struct {
+	DB                  *sql.DB
+	Validate            *validator.Validate
+	RecurringRepository repository.RecurringExpenseRepository
+	ExpenseRepository   repository.ExpenseRepository
+	CategoryRepository  repository.CategoryRepository
+	AuditRepository     repository.AuditRepository
+	WebhookRepository   repository.WebhookRepository
+	CategoryModels      *classifier.Store
+}
+
+func NewRecurringExpenseService(db *sql.DB, validate *validator.Validate, recurringRepository repository.RecurringExpenseRepository, expenseRepository repository.ExpenseRepository, categoryRepository repository.CategoryRepository, auditRepository repository.AuditRepository, webhookRepository repository.WebhookRepository, categoryModels *classifier.Store) RecurringExpenseService {
+	return &recurringExpenseServiceImpl{
+		DB:                  db,
+		Validate:            validate,
+		RecurringRepository: recurringRepository,
+		ExpenseRepository:   expenseRepository,
+		CategoryRepository:  categoryRepository,
+		AuditRepository:     auditRepository,
+		WebhookRepository:   webhookRepository,
+		CategoryModels:      categoryModels,
+	}
+}
+
+// ConvertSubscription turns a detected subscription into a recurring expense.
+// The first expense is created at the next expected date that is still in
+// the future, so nothing is backfilled.
+func (service *recurringExpenseServiceImpl) ConvertSubscription(ctx context.Context, request web.RecurringExpenseCreateRequest) web.RecurringExpenseResponse {
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	err = service.Validate.Struct(request)
+	helper.PanicIfError(err)
+
+	now := time.Now().UTC()
+	since := now.Add(-insights.SubscriptionLookback)
+	expenses, err := service.ExpenseRepository.FindAllExpanse(ctx, tx, request.UserId, domain.ExpenseFilter{CreatedAfter: &since})
+	helper.PanicIfError(err)
+
+	var subscription *domain.Subscription
+	for _, s := range insights.FindSubscriptions(expenses, now) {
+		if s.Key == request.SubscriptionKey {
+			subscription = &s
+			break
+		}
+	}
+	if subscription == nil {
+		panic(exception.NewNotFoundError(fmt.Sprintf("subscription %q not found", request.SubscriptionKey)))
+	}
+
+	existing, err := service.RecurringRepository.FindAllRecurring(ctx, tx, request.UserId)
+	helper.PanicIfError(err)
+	for _, r := range existing {
+		if r.SourceKey == subscription.Key {
+			panic(exception.NewConflictError(fmt.Sprintf("subscription %q is already a recurring expense", subscription.Key)))
+		}
+	}
+
+	category, err := service.CategoryRepository.FindCategoryById(ctx, tx, subscription.CategoryId, request.UserId)
+	if err != nil {
+		panic(exception.NewNotFoundError(fmt.Sprintf("category with id:%d not found", subscription.CategoryId)))
+	}
+
+	nextRunAt := subscription.NextExpected
+	for !nextRunAt.After(now) {
+		nextRunAt = insights.NextOccurrence(subscription.Cadence, nextRunAt, subscription.AnchorDay)
+	}
+
+	recurring := domain.RecurringExpense{
+		UserId:      request.UserId,
+		CategoryId:  subscription.CategoryId,
+		Description: subscription.Description,
+		Amount:      subscription.Amount,
+		Cadence:     subscription.Cadence,
+		NextRunAt:   nextRunAt.UTC(),
+		AnchorDay:   subscription.AnchorDay,
+		SourceKey:   subscription.Key,
+		CreatedAt:   now,
+	}
+	err = service.RecurringRepository.SaveRecurring(ctx, tx, &recurring)
+	helper.PanicIfError(err)
+
+	return helper.ToRecurringExpenseResponse(recurring, category.Name)
+}
+
+func (service *recurringExpenseServiceImpl) DeleteRecurring(ctx context.Context, recurringId, userId int) {
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	_, err = service.RecurringRepository.FindRecurringById(ctx, tx, recurringId, userId)
+	if err != nil {
+		panic(exception.NewNotFoundError(fmt.Sprintf("recurring expense with id:%d not found", recurringId)))
+	}
+
+	err = service.RecurringRepository.DeleteRecurring(ctx, tx, recurringId, userId)
+	helper.PanicIfError(err)
+}
+
+func (service *recurringExpenseServiceImpl) FindAllRecurring(ctx context.Context, userId int) []web.RecurringExpenseResponse {
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	recurring, err := service.RecurringRepository.FindAllRecurring(ctx, tx, userId)
+	helper.PanicIfError(err)
+
+	categories, err := service.CategoryRepository.FindAllCategory(ctx, tx, userId)
+	helper.PanicIfError(err)
+
+	return helper.ToRecurringExpenseResponses(recurring, categories)
+}
+
+// RunDue creates the expenses of every recurring rule due at now and moves
+// the rules to their next date, returning how many expenses were created.
+func (service *recurringExpenseServiceImpl) RunDue(ctx context.Context, now time.Time) int {
+	changedUsers := map[int]bool{}
+	defer helper.AfterCommit(func() {
+		for userId := range changedUsers {
+			service.CategoryModels.Reset(userId)
+		}
+	})
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	due, err := service.RecurringRepository.FindDueRecurring(ctx, tx, now, recurringBatchSize)
+	helper.PanicIfError(err)
+
+	created := 0
+	for _, recurring := range due {
+		var categoryName string
+		if category, err := service.CategoryRepository.FindCategoryById(ctx, tx, recurring.CategoryId, recurring.UserId); err == nil {
+			categoryName = category.Name
+		}
+
+		runAt := recurring.NextRunAt
+		for i := 0; i < maxRecurringCatchUp && !runAt.After(now); i++ {
+			expense := domain.Expense{
+				CategoryId:  recurring.CategoryId,
+				Description: recurring.Description,
+				Amount:      recurring.Amount,
+				CreatedAt:   runAt.UTC(),
+				UserId:      recurring.UserId,
+			}
+			err = service.ExpenseRepository.CreateExpense(ctx, tx, &expense)
+			helper.PanicIfError(err)
+
+			recordExpenseChange(ctx, tx, service.AuditRepository, service.WebhookRepository, recurring.UserId, expense.Id, domain.AuditActionCreate, nil, helper.ToExpenseResponse(expense, categoryName))
+			changedUsers[recurring.UserId] = true
+			created++
+			runAt = insights.NextOccurrence(recurring.Cadence, runAt, recurring.AnchorDay)
+		}
+		for !runAt.After(now) {
+			runAt = insights.NextOccurrence(recurring.Cadence, runAt, recurring.AnchorDay)
+		}
+
+		err = service.RecurringRepository.UpdateNextRun(ctx, tx, recurring.Id, runAt.UTC())
+		helper.PanicIfError(err)
+	}
+
+	return created
+}
@@ -0,0 +1,14 @@
+package service
+
+import (
+	"context"
+
+	"github.com/ryhnfhrza/Expense-Tracker/model/web"
+)
+
+type RestoreService interface {
+	// RestoreTakeout recreates the categories and expenses of a takeout
+	// archive in the user's account, in one transaction. Category ids are
+	// remapped, so the archive can come from another account.
+	RestoreTakeout(ctx context.Context, request web.TakeoutRestoreRequest) web.TakeoutRestoreResponse
+}
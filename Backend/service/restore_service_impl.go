@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/ryhnfhrza/Expense-Tracker/classifier"
+	"github.com/ryhnfhrza/Expense-Tracker/exception"
+	"github.com/ryhnfhrza/Expense-Tracker/helper"
+	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
+	"github.com/ryhnfhrza/Expense-Tracker/model/web"
+	"github.com/ryhnfhrza/Expense-Tracker/repository"
+	"github.com/ryhnfhrza/Expense-Tracker/takeout"
+)
+
+type restoreServiceImpl struct {
+	DB                 *sql.DB
+	Validate           *validator.Validate
+	CategoryRepository repository.CategoryRepository
+	ExpenseRepository  repository.ExpenseRepository
+	AuditRepository    repository.AuditRepository
+	WebhookRepository  repository.WebhookRepository
+	CategoryModels     *classifier.Store
+}
+
+func NewRestoreService(db *sql.DB, validate *validator.Validate, categoryRepository repository.CategoryRepository, expenseRepository repository.ExpenseRepository, auditRepository repository.AuditRepository, webhookRepository repository.WebhookRepository, categoryModels *classifier.Store) RestoreService {
+	return &restoreServiceImpl{
+		DB:                 db,
+		Validate:           validate,
+		CategoryRepository: categoryRepository,
+		ExpenseRepository:  expenseRepository,
+		AuditRepository:    auditRepository,
+		WebhookRepository:  webhookRepository,
+		CategoryModels:     categoryModels,
+	}
+}
+
+// expenseKey identifies an expense at the precision MySQL stores, for
+// finding the ones a merge would add twice.
+type expenseKey struct {
+	CategoryId  int
+	Description string
+	Cents       int64
+	CreatedAt   int64
+}
+
+func newExpenseKey(expense domain.Expense) expenseKey {
+	return expenseKey{
+		CategoryId:  expense.CategoryId,
+		Description: expense.Description,
+		Cents:       int64(math.Round(expense.Amount * 100)),
+		CreatedAt:   expense.CreatedAt.Unix(),
+	}
+}
+
+func (service *restoreServiceImpl) RestoreTakeout(ctx context.Context, request web.TakeoutRestoreRequest) web.TakeoutRestoreResponse {
+	defer helper.AfterCommit(func() {
+		service.CategoryModels.Reset(request.UserId)
+	})
+
+	err := service.Validate.Struct(request)
+	helper.PanicIfError(err)
+
+	archive, err := takeout.Read(request.Data)
+	if err != nil {
+		panic(exception.NewBadRequest(err.Error()))
+	}
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	userId := request.UserId
+	response := web.TakeoutRestoreResponse{
+		Mode:        request.Mode,
+		CategoryIds: map[int]int{},
+	}
+
+	categories, err := service.CategoryRepository.FindAllCategory(ctx, tx, userId)
+	helper.PanicIfError(err)
+
+	expenses, err := service.ExpenseRepository.FindAllExpanse(ctx, tx, userId, domain.ExpenseFilter{})
+	helper.PanicIfError(err)
+
+	if request.Mode == domain.RestoreModeReplace {
+		service.trashAll(ctx, tx, userId, categories, expenses, &response)
+		categories, expenses = nil, nil
+	}
+
+	byName := make(map[string]domain.Category, len(categories))
+	for _, category := range categories {
+		byName[strings.ToLower(strings.TrimSpace(category.Name))] = category
+	}
+
+	// A trashed category is still restored when a live expense points at
+	// it, so the expense has somewhere to go.
+	var restoring []takeout.Expense
+	needed := map[int]bool{}
+	for _, expense := range archive.Expenses {
+		if expense.DeletedAt != nil {
+			response.TrashedSkipped++
+			continue
+		}
+		restoring = append(restoring, expense)
+		needed[expense.CategoryId] = true
+	}
+
+	names := map[int]string{}
+	for _, archived := range archive.Categories {
+		if archived.DeletedAt != nil && !needed[archived.Id] {
+			response.TrashedSkipped++
+			continue
+		}
+
+		name := strings.TrimSpace(archived.Name)
+		if existing, ok := byName[strings.ToLower(name)]; ok {
+			response.CategoryIds[archived.Id] = existing.Id
+			names[existing.Id] = existing.Name
+			response.CategoriesMatched++
+			continue
+		}
+
+		category := domain.Category{Name: name, UserId: userId}
+		err = service.CategoryRepository.SaveCategory(ctx, tx, &category)
+		helper.PanicIfError(err)
+
+		recordAudit(ctx, tx, service.AuditRepository, userId, domain.AuditEntityCategory, category.Id, domain.AuditActionCreate, nil, helper.ToCategoryResponse(category))
+
+		// A later archived category with the same name (one live, one
+		// trashed) maps onto this one instead of creating a duplicate.
+		byName[strings.ToLower(name)] = category
+		response.CategoryIds[archived.Id] = category.Id
+		names[category.Id] = category.Name
+		response.CategoriesCreated++
+	}
+
+	present := make(map[expenseKey]bool, len(expenses))
+	for _, expense := range expenses {
+		present[newExpenseKey(expense)] = true
+	}
+
+	for _, archived := range restoring {
+		expense := domain.Expense{
+			CategoryId:  response.CategoryIds[archived.CategoryId],
+			Description: archived.Description,
+			Amount:      archived.Amount,
+			CreatedAt:   archived.CreatedAt.UTC().Truncate(time.Second),
+			UserId:      userId,
+		}
+		if present[newExpenseKey(expense)] {
+			response.ExpensesSkipped++
+			continue
+		}
+
+		err = service.ExpenseRepository.CreateExpense(ctx, tx, &expense)
+		helper.PanicIfError(err)
+
+		recordExpenseChange(ctx, tx, service.AuditRepository, service.WebhookRepository, userId, expense.Id, domain.AuditActionCreate, nil, helper.ToExpenseResponse(expense, names[expense.CategoryId]))
+		response.ExpensesCreated++
+	}
+
+	return response
+}
+
+// trashAll moves every category of the user, and with them every expense,
+// to the trash, so a replace can still be undone from there.
+func (service *restoreServiceImpl) trashAll(ctx context.Context, tx *sql.Tx, userId int, categories []domain.Category, expenses []domain.Expense, response *web.TakeoutRestoreResponse) {
+	names := make(map[int]string, len(categories))
+	for _, category := range categories {
+		err := service.CategoryRepository.DeleteCategory(ctx, tx, category.Id, userId)
+		helper.PanicIfError(err)
+
+		names[category.Id] = category.Name
+		recordAudit(ctx, tx, service.AuditRepository, userId, domain.AuditEntityCategory, category.Id, domain.AuditActionDelete, helper.ToCategoryResponse(category), nil)
+		response.CategoriesTrashed++
+	}
+
+	for _, expense := range expenses {
+		recordExpenseChange(ctx, tx, service.AuditRepository, service.WebhookRepository, userId, expense.Id, domain.AuditActionDelete, helper.ToExpenseResponse(expense, names[expense.CategoryId]), nil)
+		response.ExpensesTrashed++
+	}
+}
@@ -0,0 +1,17 @@
+package service
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/ryhnfhrza/Expense-Tracker/model/web"
+)
+
+type SavedViewService interface {
+	CreateView(ctx context.Context, request web.SavedViewRequest) web.SavedViewResponse
+	UpdateView(ctx context.Context, request web.SavedViewRequest) web.SavedViewResponse
+	DeleteView(ctx context.Context, viewId, userId int)
+	FindViewById(ctx context.Context, viewId, userId int) web.SavedViewResponse
+	FindAllView(ctx context.Context, userId int) []web.SavedViewResponse
+	ApplyView(ctx context.Context, userId int, kind, viewRef string, query url.Values) url.Values
+}
@@ -0,0 +1,243 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/ryhnfhrza/Expense-Tracker/exception"
+	"github.com/ryhnfhrza/Expense-Tracker/helper"
+	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
+	"github.com/ryhnfhrza/Expense-Tracker/model/web"
+	"github.com/ryhnfhrza/Expense-Tracker/repository"
+)
+
+type savedViewServiceImpl struct {
+	DB                  *sql.DB
+	Validate            *validator.Validate
+	SavedViewRepository repository.SavedViewRepository
+}
+
+func NewSavedViewService(db *sql.DB, validate *validator.Validate, savedViewRepository repository.SavedViewRepository) SavedViewService {
+	return &savedViewServiceImpl{
+		DB:                  db,
+		Validate:            validate,
+		SavedViewRepository: savedViewRepository,
+	}
+}
+
+func (service *savedViewServiceImpl) CreateView(ctx context.Context, request web.SavedViewRequest) web.SavedViewResponse {
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	request = normalizeSavedViewRequest(request)
+	err = service.Validate.Struct(request)
+	helper.PanicIfError(err)
+	validateSavedViewFilter(request)
+
+	_, err = service.SavedViewRepository.FindViewByName(ctx, tx, request.Name, request.UserId)
+	if err == nil {
+		panic(exception.NewConflictError(fmt.Sprintf("view with name %q already exist!", request.Name)))
+	}
+
+	if request.IsDefault {
+		err = service.SavedViewRepository.ClearDefaultView(ctx, tx, request.Kind, request.UserId)
+		helper.PanicIfError(err)
+	}
+
+	now := time.Now().UTC()
+	view := domain.SavedView{
+		UserId:    request.UserId,
+		Name:      request.Name,
+		Kind:      request.Kind,
+		Filter:    request.Filter,
+		SortBy:    request.SortBy,
+		Order:     request.Order,
+		IsDefault: request.IsDefault,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	err = service.SavedViewRepository.SaveView(ctx, tx, &view)
+	helper.PanicIfError(err)
+
+	return helper.ToSavedViewResponse(view)
+}
+
+func (service *savedViewServiceImpl) UpdateView(ctx context.Context, request web.SavedViewRequest) web.SavedViewResponse {
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	view, err := service.SavedViewRepository.FindViewById(ctx, tx, request.Id, request.UserId)
+	if err != nil {
+		panic(exception.NewNotFoundError(fmt.Sprintf("view with id:%d not found", request.Id)))
+	}
+
+	request = normalizeSavedViewRequest(request)
+	err = service.Validate.Struct(request)
+	helper.PanicIfError(err)
+	validateSavedViewFilter(request)
+
+	existing, err := service.SavedViewRepository.FindViewByName(ctx, tx, request.Name, request.UserId)
+	if err == nil && existing.Id != view.Id {
+		panic(exception.NewConflictError(fmt.Sprintf("view with name %q already exist!", request.Name)))
+	}
+
+	if request.IsDefault {
+		err = service.SavedViewRepository.ClearDefaultView(ctx, tx, request.Kind, request.UserId)
+		helper.PanicIfError(err)
+	}
+
+	view.Name = request.Name
+	view.Kind = request.Kind
+	view.Filter = request.Filter
+	view.SortBy = request.SortBy
+	view.Order = request.Order
+	view.IsDefault = request.IsDefault
+	view.UpdatedAt = time.Now().UTC()
+
+	err = service.SavedViewRepository.UpdateView(ctx, tx, view)
+	helper.PanicIfError(err)
+
+	return helper.ToSavedViewResponse(*view)
+}
+
+func (service *savedViewServiceImpl) DeleteView(ctx context.Context, viewId, userId int) {
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	_, err = service.SavedViewRepository.FindViewById(ctx, tx, viewId, userId)
+	if err != nil {
+		panic(exception.NewNotFoundError(fmt.Sprintf("view with id:%d not found", viewId)))
+	}
+
+	err = service.SavedViewRepository.DeleteView(ctx, tx, viewId, userId)
+	helper.PanicIfError(err)
+}
+
+func (service *savedViewServiceImpl) FindViewById(ctx context.Context, viewId, userId int) web.SavedViewResponse {
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	view, err := service.SavedViewRepository.FindViewById(ctx, tx, viewId, userId)
+	if err != nil {
+		panic(exception.NewNotFoundError(fmt.Sprintf("view with id:%d not found", viewId)))
+	}
+
+	return helper.ToSavedViewResponse(*view)
+}
+
+func (service *savedViewServiceImpl) FindAllView(ctx context.Context, userId int) []web.SavedViewResponse {
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	views, err := service.SavedViewRepository.FindAllView(ctx, tx, userId)
+	helper.PanicIfError(err)
+
+	return helper.ToSavedViewResponses(views)
+}
+
+// viewNeutralParams page, sort or localize a listing without filtering it,
+// so they do not keep the default view from applying.
+var viewNeutralParams = map[string]bool{"limit": true, "offset": true, "sort_by": true, "order": true, "tz": true}
+
+// ApplyView resolves viewRef (a view id, "default" or "none") and returns
+// the saved filter merged with query. Without a viewRef the default view is
+// applied when query filters nothing itself, and "none" skips it.
+// Parameters present on the request win over the saved ones so a view can
+// still be narrowed or re-paged.
+func (service *savedViewServiceImpl) ApplyView(ctx context.Context, userId int, kind, viewRef string, query url.Values) url.Values {
+	switch viewRef {
+	case "none":
+		query.Del("view")
+		return query
+	case "":
+		for key := range query {
+			if !viewNeutralParams[key] {
+				return query
+			}
+		}
+	}
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	var view *domain.SavedView
+	switch viewRef {
+	case "":
+		view, err = service.SavedViewRepository.FindDefaultView(ctx, tx, kind, userId)
+		if errors.Is(err, sql.ErrNoRows) {
+			return query
+		}
+		helper.PanicIfError(err)
+	case "default":
+		view, err = service.SavedViewRepository.FindDefaultView(ctx, tx, kind, userId)
+		if err != nil {
+			panic(exception.NewNotFoundError(fmt.Sprintf("no default %s view set", kind)))
+		}
+	default:
+		viewId, convErr := strconv.Atoi(viewRef)
+		if convErr != nil || viewId <= 0 {
+			panic(exception.NewBadRequest("invalid view"))
+		}
+		view, err = service.SavedViewRepository.FindViewById(ctx, tx, viewId, userId)
+		if err != nil {
+			panic(exception.NewNotFoundError(fmt.Sprintf("view with id:%d not found", viewId)))
+		}
+	}
+
+	if view.Kind != kind {
+		panic(exception.NewBadRequest(fmt.Sprintf("view %d is a %s view and cannot be applied here", view.Id, view.Kind)))
+	}
+
+	return helper.MergeViewQuery(*view, query)
+}
+
+func normalizeSavedViewRequest(request web.SavedViewRequest) web.SavedViewRequest {
+	request.Name = strings.TrimSpace(request.Name)
+	request.Kind = strings.ToLower(request.Kind)
+	if request.Kind == "" {
+		request.Kind = domain.SavedViewKindExpense
+	}
+	request.Order = strings.ToLower(request.Order)
+	if request.Filter == nil {
+		request.Filter = map[string]string{}
+	}
+	return request
+}
+
+func validateSavedViewFilter(request web.SavedViewRequest) {
+	query := url.Values{}
+	for key, value := range request.Filter {
+		if key == "view" {
+			panic(exception.NewBadRequest("invalid filter: a view cannot reference another view"))
+		}
+		query.Set(key, value)
+	}
+
+	var err error
+	switch request.Kind {
+	case domain.SavedViewKindSummary:
+		if request.SortBy != "" || request.Order != "" {
+			panic(exception.NewBadRequest("invalid filter: summary views do not support sorting"))
+		}
+		_, err = helper.ParseSummaryFilter(query)
+	default:
+		_, err = helper.ParseExpenseFilter(query)
+	}
+	if err != nil {
+		panic(exception.NewBadRequest("invalid filter: " + err.Error()))
+	}
+}
@@ -0,0 +1,15 @@
+package service
+
+import (
+	"context"
+
+	"github.com/ryhnfhrza/Expense-Tracker/model/web"
+)
+
+type SsoService interface {
+	// Authorize starts a sign-in and returns where to send the user.
+	Authorize(ctx context.Context) web.SsoAuthorizeResponse
+	// Callback finishes the sign-in with the code the provider redirected
+	// back with, and logs the user in like Login does.
+	Callback(ctx context.Context, request web.SsoCallbackRequest) web.UserLoginResponse
+}
@@ -0,0 +1,195 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/ryhnfhrza/Expense-Tracker/exception"
+	"github.com/ryhnfhrza/Expense-Tracker/helper"
+	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
+	"github.com/ryhnfhrza/Expense-Tracker/model/web"
+	"github.com/ryhnfhrza/Expense-Tracker/oidc"
+	"github.com/ryhnfhrza/Expense-Tracker/repository"
+	"github.com/ryhnfhrza/Expense-Tracker/util"
+)
+
+// ssoStateTTL is how long the user has to sign in at the provider.
+const ssoStateTTL = 10 * time.Minute
+
+type ssoServiceImpl struct {
+	DB                       *sql.DB
+	Validate                 *validator.Validate
+	UserRepository           repository.UserRepository
+	UserRedisRepository      repository.UserRedisRepository
+	UserIdentityRepository   repository.UserIdentityRepository
+	SsoRedisRepository       repository.SsoRedisRepository
+	TwoFactorRepository      repository.TwoFactorRepository
+	TwoFactorRedisRepository repository.TwoFactorRedisRepository
+	Client                   *oidc.Client
+}
+
+// NewSsoService returns the single sign-on service. client is nil when
+// single sign-on is not configured; every call then fails with 404.
+func NewSsoService(db *sql.DB, validate *validator.Validate, userRepository repository.UserRepository, userRedisRepository repository.UserRedisRepository, userIdentityRepository repository.UserIdentityRepository, ssoRedisRepository repository.SsoRedisRepository, twoFactorRepository repository.TwoFactorRepository, twoFactorRedisRepository repository.TwoFactorRedisRepository, client *oidc.Client) SsoService {
+	return &ssoServiceImpl{
+		DB:                       db,
+		Validate:                 validate,
+		UserRepository:           userRepository,
+		UserRedisRepository:      userRedisRepository,
+		UserIdentityRepository:   userIdentityRepository,
+		SsoRedisRepository:       ssoRedisRepository,
+		TwoFactorRepository:      twoFactorRepository,
+		TwoFactorRedisRepository: twoFactorRedisRepository,
+		Client:                   client,
+	}
+}
+
+func (service *ssoServiceImpl) Authorize(ctx context.Context) web.SsoAuthorizeResponse {
+	service.requireClient()
+
+	state, err := oidc.RandomString(24)
+	helper.PanicIfError(err)
+	nonce, err := oidc.RandomString(24)
+	helper.PanicIfError(err)
+	verifier, err := oidc.RandomString(32)
+	helper.PanicIfError(err)
+
+	authorizationUrl, err := service.Client.AuthCodeURL(ctx, state, nonce, oidc.Challenge(verifier))
+	helper.PanicIfError(err)
+
+	err = service.SsoRedisRepository.SaveSsoState(ctx, state, ssoStateTTL, domain.SsoState{Verifier: verifier, Nonce: nonce})
+	helper.PanicIfError(err)
+
+	return web.SsoAuthorizeResponse{
+		AuthorizationUrl: authorizationUrl,
+		State:            state,
+	}
+}
+
+func (service *ssoServiceImpl) Callback(ctx context.Context, request web.SsoCallbackRequest) web.UserLoginResponse {
+	service.requireClient()
+
+	err := service.Validate.Struct(request)
+	helper.PanicIfError(err)
+
+	ssoState, err := service.SsoRedisRepository.TakeSsoState(ctx, request.State)
+	helper.PanicIfError(err)
+	if ssoState == nil {
+		panic(exception.NewUnauthorizedError("sign-in expired or invalid, please start again"))
+	}
+
+	claims, err := service.Client.Exchange(ctx, request.Code, ssoState.Verifier, ssoState.Nonce)
+	if err != nil {
+		log.Printf("sso: %v", err)
+		panic(exception.NewUnauthorizedError("sign-in with the provider failed, please start again"))
+	}
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	user := service.findOrLinkUser(ctx, tx, claims)
+
+	return startLogin(ctx, tx, service.TwoFactorRepository, service.TwoFactorRedisRepository, user)
+}
+
+// findOrLinkUser returns the user the provider account is linked to. An
+// account not linked yet is linked to the user with the same email, which
+// the provider must have verified, or to a new user; the provider has
+// already checked the email, so no code is sent for it.
+func (service *ssoServiceImpl) findOrLinkUser(ctx context.Context, tx *sql.Tx, claims *oidc.Claims) *domain.User {
+	issuer := service.Client.Issuer()
+
+	identity, err := service.UserIdentityRepository.FindIdentity(ctx, tx, issuer, claims.Subject)
+	if err == nil {
+		user, err := service.UserRepository.FindById(ctx, tx, identity.UserId)
+		helper.PanicIfError(err)
+		return user
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		panic(err)
+	}
+
+	email := claims.VerifiedEmail()
+	if email == "" {
+		panic(exception.NewUnauthorizedError("the provider has not verified your email address"))
+	}
+
+	user, err := service.UserRepository.FindByEmail(ctx, tx, email)
+	if errors.Is(err, sql.ErrNoRows) {
+		user = &domain.User{
+			Username: service.newUsername(ctx, tx, claims),
+			Email:    email,
+		}
+		err = service.UserRepository.SaveToDb(ctx, tx, user)
+		helper.PanicIfError(err)
+
+		// A registration still waiting for its code would now clash on email.
+		_ = service.UserRedisRepository.DeleteTempUser(ctx, email)
+	} else {
+		helper.PanicIfError(err)
+	}
+
+	err = service.UserIdentityRepository.SaveIdentity(ctx, tx, &domain.UserIdentity{
+		UserId:    user.Id,
+		Issuer:    issuer,
+		Subject:   claims.Subject,
+		Email:     email,
+		CreatedAt: time.Now().UTC(),
+	})
+	helper.PanicIfError(err)
+
+	return user
+}
+
+// newUsername derives a free username for a new account from the
+// provider's preferred username or the email address, within the 5 to 30
+// characters Register allows.
+func (service *ssoServiceImpl) newUsername(ctx context.Context, tx *sql.Tx, claims *oidc.Claims) string {
+	source := claims.PreferredUsername
+	if source == "" {
+		source, _, _ = strings.Cut(claims.Email, "@")
+	}
+
+	var base strings.Builder
+	for _, r := range strings.ToLower(source) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '.' || r == '_' || r == '-' {
+			base.WriteRune(r)
+		}
+		if base.Len() == 24 {
+			break
+		}
+	}
+	username := base.String()
+	if len(username) < 5 {
+		username += "_user"
+	}
+
+	for attempt := 0; attempt < 5; attempt++ {
+		candidate := username
+		if attempt > 0 {
+			code, err := util.Generate6DigitCode()
+			helper.PanicIfError(err)
+			candidate = username + "_" + code[:5]
+		}
+
+		_, err := service.UserRepository.FindByUsername(ctx, tx, candidate)
+		if errors.Is(err, sql.ErrNoRows) {
+			return candidate
+		}
+		helper.PanicIfError(err)
+	}
+
+	panic(exception.NewConflictError("could not pick a username for the new account, please register instead"))
+}
+
+func (service *ssoServiceImpl) requireClient() {
+	if service.Client == nil {
+		panic(exception.NewNotFoundError("single sign-on is not configured"))
+	}
+}
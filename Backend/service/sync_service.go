@@ -0,0 +1,12 @@
+package service
+
+import (
+	"context"
+
+	"github.com/ryhnfhrza/Expense-Tracker/model/web"
+)
+
+type SyncService interface {
+	FindChanges(ctx context.Context, userId int, since int64, limit int) web.SyncFeedResponse
+	Push(ctx context.Context, request web.SyncPushRequest) web.SyncPushResponse
+}
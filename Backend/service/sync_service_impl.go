@@ -0,0 +1,507 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/ryhnfhrza/Expense-Tracker/classifier"
+	"github.com/ryhnfhrza/Expense-Tracker/exception"
+	"github.com/ryhnfhrza/Expense-Tracker/helper"
+	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
+	"github.com/ryhnfhrza/Expense-Tracker/model/web"
+	"github.com/ryhnfhrza/Expense-Tracker/repository"
+)
+
+const (
+	syncStatusApplied   = "applied"
+	syncStatusDuplicate = "duplicate"
+	syncStatusConflict  = "conflict"
+	syncStatusFailed    = "failed"
+	syncStatusNotFound  = "not_found"
+
+	syncClientWins = "client_wins"
+	syncServerWins = "server_wins"
+
+	defaultSyncLimit = 500
+	maxSyncLimit     = 1000
+)
+
+type syncServiceImpl struct {
+	DB                 *sql.DB
+	Validate           *validator.Validate
+	SyncRepository     repository.SyncRepository
+	ExpenseRepository  repository.ExpenseRepository
+	CategoryRepository repository.CategoryRepository
+	TrashRepository    repository.TrashRepository
+	AuditRepository    repository.AuditRepository
+	WebhookRepository  repository.WebhookRepository
+	CategoryModels     *classifier.Store
+}
+
+func NewSyncService(db *sql.DB, validate *validator.Validate, syncRepository repository.SyncRepository, expenseRepository repository.ExpenseRepository, categoryRepository repository.CategoryRepository, trashRepository repository.TrashRepository, auditRepository repository.AuditRepository, webhookRepository repository.WebhookRepository, categoryModels *classifier.Store) SyncService {
+	return &syncServiceImpl{
+		DB:                 db,
+		Validate:           validate,
+		SyncRepository:     syncRepository,
+		ExpenseRepository:  expenseRepository,
+		CategoryRepository: categoryRepository,
+		TrashRepository:    trashRepository,
+		AuditRepository:    auditRepository,
+		WebhookRepository:  webhookRepository,
+		CategoryModels:     categoryModels,
+	}
+}
+
+// FindChanges returns the expenses and categories changed after the since
+// token, read off the audit log. Without a token it returns a snapshot of
+// everything live together with the token to continue from.
+func (service *syncServiceImpl) FindChanges(ctx context.Context, userId int, since int64, limit int) web.SyncFeedResponse {
+	if limit <= 0 {
+		limit = defaultSyncLimit
+	}
+	if limit > maxSyncLimit {
+		limit = maxSyncLimit
+	}
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	response := web.SyncFeedResponse{
+		Expenses:   []web.SyncExpenseChange{},
+		Categories: []web.SyncCategoryChange{},
+		NextSince:  strconv.FormatInt(since, 10),
+	}
+
+	if since == 0 {
+		cursor, err := service.SyncRepository.FindSyncCursor(ctx, tx, userId)
+		helper.PanicIfError(err)
+
+		expenses, err := service.SyncRepository.FindSyncExpenses(ctx, tx, userId, nil)
+		helper.PanicIfError(err)
+		categories, err := service.SyncRepository.FindSyncCategories(ctx, tx, userId, nil)
+		helper.PanicIfError(err)
+
+		for _, expense := range expenses {
+			response.Expenses = append(response.Expenses, helper.ToSyncExpenseChange(expense))
+		}
+		for _, category := range categories {
+			response.Categories = append(response.Categories, helper.ToSyncCategoryChange(category))
+		}
+		response.Snapshot = true
+		response.NextSince = strconv.FormatInt(cursor, 10)
+
+		return response
+	}
+
+	changes, err := service.SyncRepository.FindSyncChanges(ctx, tx, userId, since, limit)
+	helper.PanicIfError(err)
+	if len(changes) == 0 {
+		return response
+	}
+	response.NextSince = strconv.FormatInt(changes[len(changes)-1].Seq, 10)
+	response.HasMore = len(changes) == limit
+
+	expenseIds := syncChangedIds(changes, domain.AuditEntityExpense)
+	expenses, err := service.SyncRepository.FindSyncExpenses(ctx, tx, userId, expenseIds)
+	helper.PanicIfError(err)
+	expensesById := map[int]domain.SyncedExpense{}
+	for _, expense := range expenses {
+		expensesById[expense.Expense.Id] = expense
+	}
+	for _, id := range expenseIds {
+		if expense, ok := expensesById[id]; ok {
+			response.Expenses = append(response.Expenses, helper.ToSyncExpenseChange(expense))
+		} else {
+			// Purged from the trash: only the id is left to report.
+			response.Expenses = append(response.Expenses, web.SyncExpenseChange{Id: id, Deleted: true})
+		}
+	}
+
+	categoryIds := syncChangedIds(changes, domain.AuditEntityCategory)
+	categories, err := service.SyncRepository.FindSyncCategories(ctx, tx, userId, categoryIds)
+	helper.PanicIfError(err)
+	categoriesById := map[int]domain.SyncedCategory{}
+	for _, category := range categories {
+		categoriesById[category.Category.Id] = category
+	}
+	for _, id := range categoryIds {
+		if category, ok := categoriesById[id]; ok {
+			response.Categories = append(response.Categories, helper.ToSyncCategoryChange(category))
+		} else {
+			response.Categories = append(response.Categories, web.SyncCategoryChange{Id: id, Deleted: true})
+		}
+	}
+
+	return response
+}
+
+// syncChangedIds lists the entities of one type in a page of changes once
+// each, ordered by their latest change.
+func syncChangedIds(changes []domain.SyncChange, entityType string) []int {
+	seen := map[int]bool{}
+	var reversed []int
+	for i := len(changes) - 1; i >= 0; i-- {
+		change := changes[i]
+		if change.EntityType != entityType || seen[change.EntityId] {
+			continue
+		}
+		seen[change.EntityId] = true
+		reversed = append(reversed, change.EntityId)
+	}
+
+	ids := make([]int, 0, len(reversed))
+	for i := len(reversed) - 1; i >= 0; i-- {
+		ids = append(ids, reversed[i])
+	}
+	return ids
+}
+
+// Push applies a batch of offline mutations in order, in one transaction.
+// A mutation made on an out-of-date expense is a conflict, settled by last
+// writer wins: the client's changed_at against the server's latest change.
+// Both outcomes are reported. Mutations that cannot apply are reported and
+// skipped without failing the batch.
+func (service *syncServiceImpl) Push(ctx context.Context, request web.SyncPushRequest) web.SyncPushResponse {
+	changed := false
+	defer helper.AfterCommit(func() {
+		if changed {
+			service.CategoryModels.Reset(request.UserId)
+		}
+	})
+
+	err := service.Validate.Struct(request)
+	helper.PanicIfError(err)
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	response := web.SyncPushResponse{Results: []web.SyncMutationResponse{}}
+	for i, mutation := range request.Mutations {
+		result := web.SyncMutationResponse{Index: i, ClientId: mutation.ClientId, Entity: mutation.Entity, Op: mutation.Op}
+
+		if err := service.Validate.Struct(mutation); err != nil {
+			result.Status, result.Error = syncStatusFailed, err.Error()
+		} else {
+			switch mutation.Entity + " " + mutation.Op {
+			case "expense create":
+				service.syncCreateExpense(ctx, tx, request.UserId, mutation, &result)
+			case "expense update":
+				service.syncUpdateExpense(ctx, tx, request.UserId, mutation, &result)
+			case "expense delete":
+				service.syncDeleteExpense(ctx, tx, request.UserId, mutation, &result)
+			case "category create":
+				service.syncCreateCategory(ctx, tx, request.UserId, mutation, &result)
+			case "category delete":
+				service.syncDeleteCategory(ctx, tx, request.UserId, mutation, &result)
+			default:
+				result.Status, result.Error = syncStatusFailed, "categories cannot be updated"
+			}
+		}
+
+		switch result.Status {
+		case syncStatusApplied, syncStatusDuplicate:
+			response.Applied++
+		case syncStatusFailed, syncStatusNotFound:
+			response.Failed++
+		}
+		if result.Conflict != nil {
+			response.Conflicts++
+		}
+		response.Results = append(response.Results, result)
+	}
+	changed = response.Applied > 0
+
+	return response
+}
+
+func (service *syncServiceImpl) syncCreateExpense(ctx context.Context, tx *sql.Tx, userId int, mutation web.SyncMutationRequest, result *web.SyncMutationResponse) {
+	if mutation.ClientId == "" {
+		result.Status, result.Error = syncStatusFailed, "client_id is required to create an expense"
+		return
+	}
+
+	// The client sent this create before but never saw the response.
+	if existing := service.findSyncedExpense(ctx, tx, userId, 0, mutation.ClientId); existing != nil {
+		change := helper.ToSyncExpenseChange(*existing)
+		result.Id, result.Status, result.Expense = existing.Expense.Id, syncStatusDuplicate, change.Expense
+		return
+	}
+
+	if mutation.Expense == nil {
+		result.Status, result.Error = syncStatusFailed, "expense is required"
+		return
+	}
+	category := service.findSyncCategory(ctx, tx, userId, *mutation.Expense)
+	if category == nil {
+		result.Status, result.Error = syncStatusFailed, "category is missing or not found"
+		return
+	}
+
+	createdAt := mutation.ChangedAt.Time.UTC()
+	if mutation.Expense.CreatedAt != nil && !mutation.Expense.CreatedAt.Time.IsZero() {
+		createdAt = mutation.Expense.CreatedAt.Time.UTC()
+	}
+
+	expense := domain.Expense{
+		CategoryId:  category.Id,
+		Description: mutation.Expense.Description,
+		Amount:      mutation.Expense.Amount,
+		CreatedAt:   createdAt,
+		UserId:      userId,
+	}
+	err := service.ExpenseRepository.CreateExpense(ctx, tx, &expense)
+	helper.PanicIfError(err)
+	err = service.SyncRepository.SaveExpenseClientId(ctx, tx, userId, expense.Id, mutation.ClientId)
+	helper.PanicIfError(err)
+
+	response := helper.ToExpenseResponse(expense, category.Name)
+	recordExpenseChange(ctx, tx, service.AuditRepository, service.WebhookRepository, userId, expense.Id, domain.AuditActionCreate, nil, response)
+
+	result.Id, result.Status, result.Expense = expense.Id, syncStatusApplied, &response
+}
+
+func (service *syncServiceImpl) syncUpdateExpense(ctx context.Context, tx *sql.Tx, userId int, mutation web.SyncMutationRequest, result *web.SyncMutationResponse) {
+	target := service.findSyncedExpense(ctx, tx, userId, mutation.Id, mutation.ClientId)
+	if target == nil {
+		result.Status = syncStatusNotFound
+		return
+	}
+	result.Id = target.Expense.Id
+
+	if mutation.Expense == nil {
+		result.Status, result.Error = syncStatusFailed, "expense is required"
+		return
+	}
+	category := service.findSyncCategory(ctx, tx, userId, *mutation.Expense)
+	if category == nil {
+		result.Status, result.Error = syncStatusFailed, "category is missing or not found"
+		return
+	}
+
+	current := target.Expense
+	before := helper.ToExpenseResponse(current, target.CategoryName)
+	deleted := target.DeletedAt != nil
+
+	if deleted || mutation.BaseVersion != current.Version {
+		result.Conflict = service.syncConflict(ctx, tx, userId, *target, mutation)
+		if result.Conflict.Resolution == syncServerWins {
+			result.Status = syncStatusConflict
+			if !deleted {
+				result.Expense = &before
+			}
+			return
+		}
+	}
+
+	// The client edited an expense that was deleted here before that edit.
+	if deleted {
+		err := service.TrashRepository.RestoreExpense(ctx, tx, current.Id, userId)
+		helper.PanicIfError(err)
+		recordExpenseChange(ctx, tx, service.AuditRepository, service.WebhookRepository, userId, current.Id, domain.AuditActionRestore, nil, before)
+	}
+
+	updated := current
+	updated.CategoryId = category.Id
+	updated.Description = mutation.Expense.Description
+	updated.Amount = mutation.Expense.Amount
+	if mutation.Expense.CreatedAt != nil && !mutation.Expense.CreatedAt.Time.IsZero() {
+		updated.CreatedAt = mutation.Expense.CreatedAt.Time.UTC()
+	}
+
+	_, err := service.ExpenseRepository.UpdateExpense(ctx, tx, &updated)
+	if errors.Is(err, sql.ErrNoRows) {
+		panic(exception.NewConflictError("expenses changed while syncing, push again"))
+	}
+	helper.PanicIfError(err)
+
+	response := helper.ToExpenseResponse(updated, category.Name)
+	recordExpenseChange(ctx, tx, service.AuditRepository, service.WebhookRepository, userId, updated.Id, domain.AuditActionUpdate, before, response)
+
+	result.Status, result.Expense = syncStatusApplied, &response
+}
+
+func (service *syncServiceImpl) syncDeleteExpense(ctx context.Context, tx *sql.Tx, userId int, mutation web.SyncMutationRequest, result *web.SyncMutationResponse) {
+	target := service.findSyncedExpense(ctx, tx, userId, mutation.Id, mutation.ClientId)
+	if target == nil {
+		result.Status = syncStatusNotFound
+		return
+	}
+	result.Id = target.Expense.Id
+
+	if target.DeletedAt != nil {
+		result.Status = syncStatusApplied
+		return
+	}
+
+	current := helper.ToExpenseResponse(target.Expense, target.CategoryName)
+	if mutation.BaseVersion != target.Expense.Version {
+		result.Conflict = service.syncConflict(ctx, tx, userId, *target, mutation)
+		if result.Conflict.Resolution == syncServerWins {
+			result.Status, result.Expense = syncStatusConflict, &current
+			return
+		}
+	}
+
+	err := service.ExpenseRepository.DeleteExpanse(ctx, tx, target.Expense.Id, userId)
+	if errors.Is(err, sql.ErrNoRows) {
+		panic(exception.NewConflictError("expenses changed while syncing, push again"))
+	}
+	helper.PanicIfError(err)
+	recordExpenseChange(ctx, tx, service.AuditRepository, service.WebhookRepository, userId, target.Expense.Id, domain.AuditActionDelete, current, nil)
+
+	result.Status = syncStatusApplied
+}
+
+func (service *syncServiceImpl) syncCreateCategory(ctx context.Context, tx *sql.Tx, userId int, mutation web.SyncMutationRequest, result *web.SyncMutationResponse) {
+	if mutation.ClientId == "" {
+		result.Status, result.Error = syncStatusFailed, "client_id is required to create a category"
+		return
+	}
+
+	if existing := service.findSyncedCategory(ctx, tx, userId, 0, mutation.ClientId); existing != nil {
+		change := helper.ToSyncCategoryChange(*existing)
+		result.Id, result.Status, result.Category = existing.Category.Id, syncStatusDuplicate, change.Category
+		return
+	}
+
+	if mutation.Category == nil {
+		result.Status, result.Error = syncStatusFailed, "category is required"
+		return
+	}
+
+	category := domain.Category{
+		Name:   mutation.Category.Name,
+		UserId: userId,
+	}
+	err := service.CategoryRepository.SaveCategory(ctx, tx, &category)
+	helper.PanicIfError(err)
+	err = service.SyncRepository.SaveCategoryClientId(ctx, tx, userId, category.Id, mutation.ClientId)
+	helper.PanicIfError(err)
+
+	response := helper.ToCategoryResponse(category)
+	recordAudit(ctx, tx, service.AuditRepository, userId, domain.AuditEntityCategory, category.Id, domain.AuditActionCreate, nil, response)
+
+	result.Id, result.Status, result.Category = category.Id, syncStatusApplied, &response
+}
+
+// syncDeleteCategory deletes the category and its expenses like
+// CategoryService.DeleteCategory. Categories have nothing to edit, so a
+// delete never conflicts.
+func (service *syncServiceImpl) syncDeleteCategory(ctx context.Context, tx *sql.Tx, userId int, mutation web.SyncMutationRequest, result *web.SyncMutationResponse) {
+	target := service.findSyncedCategory(ctx, tx, userId, mutation.Id, mutation.ClientId)
+	if target == nil {
+		result.Status = syncStatusNotFound
+		return
+	}
+	result.Id = target.Category.Id
+
+	if target.DeletedAt != nil {
+		result.Status = syncStatusApplied
+		return
+	}
+
+	category := target.Category
+	expenses, err := service.ExpenseRepository.FindAllExpanse(ctx, tx, userId, domain.ExpenseFilter{CategoryIds: []int{category.Id}})
+	helper.PanicIfError(err)
+
+	err = service.CategoryRepository.DeleteCategory(ctx, tx, category.Id, userId)
+	helper.PanicIfError(err)
+
+	for _, expense := range expenses {
+		recordExpenseChange(ctx, tx, service.AuditRepository, service.WebhookRepository, userId, expense.Id, domain.AuditActionDelete, helper.ToExpenseResponse(expense, category.Name), nil)
+	}
+	recordAudit(ctx, tx, service.AuditRepository, userId, domain.AuditEntityCategory, category.Id, domain.AuditActionDelete, helper.ToCategoryResponse(category), nil)
+
+	result.Status = syncStatusApplied
+}
+
+// syncConflict settles a mutation made on an out-of-date expense: the
+// later of the client's changed_at and the server's latest change wins.
+// Expenses older than the audit log count as changed when created.
+func (service *syncServiceImpl) syncConflict(ctx context.Context, tx *sql.Tx, userId int, target domain.SyncedExpense, mutation web.SyncMutationRequest) *web.SyncConflictResponse {
+	conflict := &web.SyncConflictResponse{
+		ServerChangedAt: target.Expense.CreatedAt,
+		ServerDeleted:   target.DeletedAt != nil,
+	}
+
+	entries, err := service.AuditRepository.FindAllAudit(ctx, tx, userId, domain.AuditFilter{
+		EntityType: domain.AuditEntityExpense,
+		EntityId:   target.Expense.Id,
+		Limit:      1,
+	})
+	helper.PanicIfError(err)
+	if len(entries) > 0 {
+		conflict.ServerChangedAt = entries[0].CreatedAt
+	}
+
+	if !conflict.ServerDeleted {
+		server := helper.ToExpenseResponse(target.Expense, target.CategoryName)
+		conflict.ServerExpense = &server
+	}
+
+	conflict.Resolution = syncServerWins
+	if mutation.ChangedAt.Time.After(conflict.ServerChangedAt) {
+		conflict.Resolution = syncClientWins
+	}
+
+	return conflict
+}
+
+// findSyncedExpense looks up an expense by id, or by client id when id is 0,
+// trashed ones included. It returns nil when there is none.
+func (service *syncServiceImpl) findSyncedExpense(ctx context.Context, tx *sql.Tx, userId, id int, clientId string) *domain.SyncedExpense {
+	if id > 0 {
+		expenses, err := service.SyncRepository.FindSyncExpenses(ctx, tx, userId, []int{id})
+		helper.PanicIfError(err)
+		if len(expenses) == 0 {
+			return nil
+		}
+		return &expenses[0]
+	}
+	if clientId == "" {
+		return nil
+	}
+
+	expense, err := service.SyncRepository.FindExpenseByClientId(ctx, tx, userId, clientId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	helper.PanicIfError(err)
+	return expense
+}
+
+func (service *syncServiceImpl) findSyncedCategory(ctx context.Context, tx *sql.Tx, userId, id int, clientId string) *domain.SyncedCategory {
+	if id > 0 {
+		categories, err := service.SyncRepository.FindSyncCategories(ctx, tx, userId, []int{id})
+		helper.PanicIfError(err)
+		if len(categories) == 0 {
+			return nil
+		}
+		return &categories[0]
+	}
+	if clientId == "" {
+		return nil
+	}
+
+	category, err := service.SyncRepository.FindCategoryByClientId(ctx, tx, userId, clientId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	helper.PanicIfError(err)
+	return category
+}
+
+// findSyncCategory resolves the live category an expense mutation points
+// at, by category_id or by the client id of a category created offline.
+func (service *syncServiceImpl) findSyncCategory(ctx context.Context, tx *sql.Tx, userId int, data web.SyncExpenseData) *domain.Category {
+	category := service.findSyncedCategory(ctx, tx, userId, data.CategoryId, data.CategoryClientId)
+	if category == nil || category.DeletedAt != nil {
+		return nil
+	}
+	return &category.Category
+}
@@ -0,0 +1,18 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/ryhnfhrza/Expense-Tracker/model/web"
+)
+
+type TrashService interface {
+	FindTrash(ctx context.Context, userId int) web.TrashResponse
+	RestoreExpense(ctx context.Context, expenseId, userId int) web.ExpenseResponse
+	RestoreCategory(ctx context.Context, categoryId, userId int) web.CategoryResponse
+	PurgeExpense(ctx context.Context, expenseId, userId int)
+	PurgeCategory(ctx context.Context, categoryId, userId int)
+	EmptyTrash(ctx context.Context, userId int)
+	PurgeExpired(ctx context.Context, now time.Time) int
+}
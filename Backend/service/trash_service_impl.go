@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/ryhnfhrza/Expense-Tracker/classifier"
+	"github.com/ryhnfhrza/Expense-Tracker/exception"
+	"github.com/ryhnfhrza/Expense-Tracker/helper"
+	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
+	"github.com/ryhnfhrza/Expense-Tracker/model/web"
+	"github.com/ryhnfhrza/Expense-Tracker/repository"
+)
+
+const trashPurgeBatchSize = 500
+
+type trashServiceImpl struct {
+	DB                 *sql.DB
+	TrashRepository    repository.TrashRepository
+	CategoryRepository repository.CategoryRepository
+	AuditRepository    repository.AuditRepository
+	WebhookRepository  repository.WebhookRepository
+	CategoryModels     *classifier.Store
+	Retention          time.Duration
+}
+
+func NewTrashService(db *sql.DB, trashRepository repository.TrashRepository, categoryRepository repository.CategoryRepository, auditRepository repository.AuditRepository, webhookRepository repository.WebhookRepository, categoryModels *classifier.Store, retention time.Duration) TrashService {
+	return &trashServiceImpl{
+		DB:                 db,
+		TrashRepository:    trashRepository,
+		CategoryRepository: categoryRepository,
+		AuditRepository:    auditRepository,
+		WebhookRepository:  webhookRepository,
+		CategoryModels:     categoryModels,
+		Retention:          retention,
+	}
+}
+
+func (service *trashServiceImpl) FindTrash(ctx context.Context, userId int) web.TrashResponse {
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	expenses, err := service.TrashRepository.FindTrashedExpenses(ctx, tx, userId)
+	helper.PanicIfError(err)
+
+	categories, err := service.TrashRepository.FindTrashedCategories(ctx, tx, userId)
+	helper.PanicIfError(err)
+
+	return helper.ToTrashResponse(expenses, categories, service.Retention)
+}
+
+func (service *trashServiceImpl) RestoreExpense(ctx context.Context, expenseId, userId int) web.ExpenseResponse {
+	var restored domain.Expense
+	defer helper.AfterCommit(func() {
+		service.CategoryModels.Learn(restored.UserId, restored.CategoryId, restored.Description, restored.Amount)
+	})
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	trashed, err := service.TrashRepository.FindTrashedExpenseById(ctx, tx, expenseId, userId)
+	if err != nil {
+		panic(exception.NewNotFoundError(fmt.Sprintf("expense with id:%d not found in trash", expenseId)))
+	}
+	restored = trashed.Expense
+
+	return restoreExpense(ctx, tx, service.TrashRepository, service.CategoryRepository, service.AuditRepository, service.WebhookRepository, *trashed)
+}
+
+func (service *trashServiceImpl) RestoreCategory(ctx context.Context, categoryId, userId int) web.CategoryResponse {
+	defer helper.AfterCommit(func() {
+		service.CategoryModels.Reset(userId)
+	})
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	trashed, err := service.TrashRepository.FindTrashedCategoryById(ctx, tx, categoryId, userId)
+	if err != nil {
+		panic(exception.NewNotFoundError(fmt.Sprintf("category with id:%d not found in trash", categoryId)))
+	}
+
+	return restoreCategory(ctx, tx, service.TrashRepository, service.AuditRepository, service.WebhookRepository, *trashed)
+}
+
+func (service *trashServiceImpl) PurgeExpense(ctx context.Context, expenseId, userId int) {
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	if _, err := service.TrashRepository.FindTrashedExpenseById(ctx, tx, expenseId, userId); err != nil {
+		panic(exception.NewNotFoundError(fmt.Sprintf("expense with id:%d not found in trash", expenseId)))
+	}
+
+	service.purge(ctx, tx, domain.TrashItem{UserId: userId, EntityType: domain.AuditEntityExpense, EntityId: expenseId})
+}
+
+func (service *trashServiceImpl) PurgeCategory(ctx context.Context, categoryId, userId int) {
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	if _, err := service.TrashRepository.FindTrashedCategoryById(ctx, tx, categoryId, userId); err != nil {
+		panic(exception.NewNotFoundError(fmt.Sprintf("category with id:%d not found in trash", categoryId)))
+	}
+
+	service.purge(ctx, tx, domain.TrashItem{UserId: userId, EntityType: domain.AuditEntityCategory, EntityId: categoryId})
+}
+
+func (service *trashServiceImpl) EmptyTrash(ctx context.Context, userId int) {
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	expenses, err := service.TrashRepository.FindTrashedExpenses(ctx, tx, userId)
+	helper.PanicIfError(err)
+	for _, expense := range expenses {
+		service.purge(ctx, tx, domain.TrashItem{UserId: userId, EntityType: domain.AuditEntityExpense, EntityId: expense.Expense.Id})
+	}
+
+	categories, err := service.TrashRepository.FindTrashedCategories(ctx, tx, userId)
+	helper.PanicIfError(err)
+	for _, category := range categories {
+		service.purge(ctx, tx, domain.TrashItem{UserId: userId, EntityType: domain.AuditEntityCategory, EntityId: category.Category.Id})
+	}
+}
+
+// PurgeExpired deletes for good everything that has been in the trash longer
+// than the retention period and returns how many items were purged.
+func (service *trashServiceImpl) PurgeExpired(ctx context.Context, now time.Time) int {
+	purged := 0
+	for {
+		count := service.purgeExpiredBatch(ctx, now.Add(-service.Retention))
+		purged += count
+		if count < trashPurgeBatchSize {
+			return purged
+		}
+	}
+}
+
+func (service *trashServiceImpl) purgeExpiredBatch(ctx context.Context, deletedBefore time.Time) int {
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	items, err := service.TrashRepository.FindExpiredTrash(ctx, tx, deletedBefore, trashPurgeBatchSize)
+	helper.PanicIfError(err)
+
+	for _, item := range items {
+		service.purge(ctx, tx, item)
+	}
+
+	return len(items)
+}
+
+// purge deletes one trashed item for good. The audit entry keeps no snapshot:
+// the delete entry before it already has one.
+func (service *trashServiceImpl) purge(ctx context.Context, tx *sql.Tx, item domain.TrashItem) {
+	var err error
+	switch item.EntityType {
+	case domain.AuditEntityExpense:
+		err = service.TrashRepository.PurgeExpense(ctx, tx, item.EntityId, item.UserId)
+	case domain.AuditEntityCategory:
+		err = service.TrashRepository.PurgeCategory(ctx, tx, item.EntityId, item.UserId)
+	default:
+		err = fmt.Errorf("unknown trash item type %q", item.EntityType)
+	}
+	helper.PanicIfError(err)
+
+	recordAudit(ctx, tx, service.AuditRepository, item.UserId, item.EntityType, item.EntityId, domain.AuditActionPurge, nil, nil)
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/ryhnfhrza/Expense-Tracker/exception"
+	"github.com/ryhnfhrza/Expense-Tracker/helper"
+	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
+	"github.com/ryhnfhrza/Expense-Tracker/model/web"
+	"github.com/ryhnfhrza/Expense-Tracker/repository"
+	"github.com/ryhnfhrza/Expense-Tracker/util"
+)
+
+const (
+	// twoFactorChallengeTTL is how long a login waits for its second factor.
+	twoFactorChallengeTTL = 5 * time.Minute
+	twoFactorMaxAttempts  = 5
+	// twoFactorMaxFailures wrong codes in a row, over any number of
+	// challenges, lock the user's second factor for twoFactorLockout.
+	twoFactorMaxFailures   = 10
+	twoFactorLockout       = 15 * time.Minute
+	twoFactorIssuer        = "Expense Tracker"
+	twoFactorRecoveryCodes = 10
+)
+
+// startLogin finishes the first step of a login. Users without two-factor
+// authentication get their JWT. Everyone else gets a challenge token to
+// trade, together with a code, at /login/2fa.
+func startLogin(ctx context.Context, tx *sql.Tx, twoFactorRepository repository.TwoFactorRepository, twoFactorRedisRepository repository.TwoFactorRedisRepository, user *domain.User) web.UserLoginResponse {
+	twoFactor, err := twoFactorRepository.FindTwoFactor(ctx, tx, user.Id)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		panic(err)
+	}
+
+	if twoFactor == nil || twoFactor.ConfirmedAt == nil {
+		token, err := util.CreateToken(user)
+		helper.PanicIfError(err)
+		return helper.ToUserLoginResponse(*user, token)
+	}
+
+	challengeToken, err := util.RandomToken(32)
+	helper.PanicIfError(err)
+
+	err = twoFactorRedisRepository.SaveChallenge(ctx, challengeToken, twoFactorChallengeTTL, domain.TwoFactorChallenge{UserId: user.Id})
+	helper.PanicIfError(err)
+
+	return web.UserLoginResponse{
+		Id:                user.Id,
+		Username:          user.Username,
+		Email:             user.Email,
+		TwoFactorRequired: true,
+		ChallengeToken:    challengeToken,
+	}
+}
+
+// checkSecondFactor accepts a TOTP code newer than the last one used, or
+// an unused recovery code, which it uses up.
+func checkSecondFactor(ctx context.Context, tx *sql.Tx, twoFactorRepository repository.TwoFactorRepository, twoFactor *domain.TwoFactor, code string) bool {
+	code = strings.ToLower(strings.TrimSpace(code))
+	now := time.Now().UTC()
+
+	if !strings.Contains(code, "-") {
+		step, ok := util.ValidateTOTP(twoFactor.Secret, code, now)
+		if !ok || step <= twoFactor.LastUsedStep {
+			return false
+		}
+
+		twoFactor.LastUsedStep = step
+		err := twoFactorRepository.UpdateTwoFactor(ctx, tx, twoFactor)
+		helper.PanicIfError(err)
+		return true
+	}
+
+	codes, err := twoFactorRepository.FindUnusedRecoveryCodes(ctx, tx, twoFactor.UserId)
+	helper.PanicIfError(err)
+
+	for _, recoveryCode := range codes {
+		if util.CheckPasswordHash(code, recoveryCode.Hash) {
+			err = twoFactorRepository.MarkRecoveryCodeUsed(ctx, tx, recoveryCode.Id, now)
+			helper.PanicIfError(err)
+			return true
+		}
+	}
+
+	return false
+}
+
+// verifySecondFactor runs check, which tests a code, unless the user sent
+// too many wrong codes lately. Wrong codes are counted per user, so
+// starting a new login or disabling two-factor authentication does not
+// give a fresh set of guesses.
+func verifySecondFactor(ctx context.Context, twoFactorRedisRepository repository.TwoFactorRedisRepository, userId int, check func() bool) bool {
+	failures, err := twoFactorRedisRepository.GetFailures(ctx, userId)
+	helper.PanicIfError(err)
+	if failures >= twoFactorMaxFailures {
+		panic(exception.NewTooManyRequestError("too many invalid two-factor codes, please wait 15 minutes"))
+	}
+
+	if check() {
+		err = twoFactorRedisRepository.ClearFailures(ctx, userId)
+		helper.PanicIfError(err)
+		return true
+	}
+
+	failures, err = twoFactorRedisRepository.IncrementFailure(ctx, userId, twoFactorLockout)
+	helper.PanicIfError(err)
+	if failures >= twoFactorMaxFailures {
+		panic(exception.NewTooManyRequestError("too many invalid two-factor codes, please wait 15 minutes"))
+	}
+	return false
+}
+
+// newRecoveryCodes replaces the user's recovery codes and returns the new
+// ones. Only their bcrypt hashes are stored.
+func newRecoveryCodes(ctx context.Context, tx *sql.Tx, twoFactorRepository repository.TwoFactorRepository, userId int) []string {
+	err := twoFactorRepository.DeleteRecoveryCodes(ctx, tx, userId)
+	helper.PanicIfError(err)
+
+	codes := make([]string, 0, twoFactorRecoveryCodes)
+	hashes := make([]string, 0, twoFactorRecoveryCodes)
+	for range twoFactorRecoveryCodes {
+		code, err := util.GenerateRecoveryCode()
+		helper.PanicIfError(err)
+		hash, err := util.HashPassword(code)
+		helper.PanicIfError(err)
+
+		codes = append(codes, code)
+		hashes = append(hashes, hash)
+	}
+
+	err = twoFactorRepository.SaveRecoveryCodes(ctx, tx, userId, hashes)
+	helper.PanicIfError(err)
+
+	return codes
+}
@@ -0,0 +1,15 @@
+package service
+
+import (
+	"context"
+
+	"github.com/ryhnfhrza/Expense-Tracker/model/web"
+)
+
+type TwoFactorService interface {
+	// Enroll starts setting up two-factor authentication. It is not
+	// enforced until Confirm succeeds.
+	Enroll(ctx context.Context, userId int) web.TwoFactorEnrollResponse
+	Confirm(ctx context.Context, request web.TwoFactorCodeRequest) web.TwoFactorConfirmResponse
+	Disable(ctx context.Context, request web.TwoFactorCodeRequest)
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/ryhnfhrza/Expense-Tracker/exception"
+	"github.com/ryhnfhrza/Expense-Tracker/helper"
+	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
+	"github.com/ryhnfhrza/Expense-Tracker/model/web"
+	"github.com/ryhnfhrza/Expense-Tracker/repository"
+	"github.com/ryhnfhrza/Expense-Tracker/util"
+)
+
+type twoFactorServiceImpl struct {
+	DB                       *sql.DB
+	Validate                 *validator.Validate
+	UserRepository           repository.UserRepository
+	TwoFactorRepository      repository.TwoFactorRepository
+	TwoFactorRedisRepository repository.TwoFactorRedisRepository
+}
+
+func NewTwoFactorService(db *sql.DB, validate *validator.Validate, userRepository repository.UserRepository, twoFactorRepository repository.TwoFactorRepository, twoFactorRedisRepository repository.TwoFactorRedisRepository) TwoFactorService {
+	return &twoFactorServiceImpl{
+		DB:                       db,
+		Validate:                 validate,
+		UserRepository:           userRepository,
+		TwoFactorRepository:      twoFactorRepository,
+		TwoFactorRedisRepository: twoFactorRedisRepository,
+	}
+}
+
+// Enroll issues a new secret, replacing one from an earlier enrollment
+// that was never confirmed.
+func (service *twoFactorServiceImpl) Enroll(ctx context.Context, userId int) web.TwoFactorEnrollResponse {
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	current, err := service.TwoFactorRepository.FindTwoFactor(ctx, tx, userId)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		panic(err)
+	}
+	if current != nil && current.ConfirmedAt != nil {
+		panic(exception.NewConflictError("two-factor authentication is already enabled"))
+	}
+
+	user, err := service.UserRepository.FindById(ctx, tx, userId)
+	helper.PanicIfError(err)
+
+	secret, err := util.GenerateTOTPSecret()
+	helper.PanicIfError(err)
+
+	err = service.TwoFactorRepository.SaveTwoFactor(ctx, tx, &domain.TwoFactor{
+		UserId:    userId,
+		Secret:    secret,
+		CreatedAt: time.Now().UTC(),
+	})
+	helper.PanicIfError(err)
+
+	return web.TwoFactorEnrollResponse{
+		Secret:          secret,
+		ProvisioningUri: util.TOTPProvisioningURI(twoFactorIssuer, user.Username, secret),
+	}
+}
+
+// Confirm turns two-factor authentication on once the user sends a code
+// from their authenticator app, and hands out the recovery codes.
+func (service *twoFactorServiceImpl) Confirm(ctx context.Context, request web.TwoFactorCodeRequest) web.TwoFactorConfirmResponse {
+	err := service.Validate.Struct(request)
+	helper.PanicIfError(err)
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	twoFactor, err := service.TwoFactorRepository.FindTwoFactor(ctx, tx, request.UserId)
+	if errors.Is(err, sql.ErrNoRows) {
+		panic(exception.NewNotFoundError("no two-factor enrollment to confirm, enroll first"))
+	}
+	helper.PanicIfError(err)
+	if twoFactor.ConfirmedAt != nil {
+		panic(exception.NewConflictError("two-factor authentication is already enabled"))
+	}
+
+	now := time.Now().UTC()
+	var step int64
+	if !verifySecondFactor(ctx, service.TwoFactorRedisRepository, request.UserId, func() bool {
+		var ok bool
+		step, ok = util.ValidateTOTP(twoFactor.Secret, strings.TrimSpace(request.Code), now)
+		return ok
+	}) {
+		panic(exception.NewBadRequest("invalid two-factor code"))
+	}
+
+	twoFactor.ConfirmedAt = &now
+	twoFactor.LastUsedStep = step
+	err = service.TwoFactorRepository.UpdateTwoFactor(ctx, tx, twoFactor)
+	helper.PanicIfError(err)
+
+	return web.TwoFactorConfirmResponse{
+		RecoveryCodes: newRecoveryCodes(ctx, tx, service.TwoFactorRepository, request.UserId),
+	}
+}
+
+// Disable turns two-factor authentication off. It takes a current code so
+// a stolen session alone cannot do it, and wrong codes count towards the
+// same lockout as logins.
+func (service *twoFactorServiceImpl) Disable(ctx context.Context, request web.TwoFactorCodeRequest) {
+	err := service.Validate.Struct(request)
+	helper.PanicIfError(err)
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	twoFactor, err := service.TwoFactorRepository.FindTwoFactor(ctx, tx, request.UserId)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && twoFactor.ConfirmedAt == nil) {
+		panic(exception.NewNotFoundError("two-factor authentication is not enabled"))
+	}
+	helper.PanicIfError(err)
+
+	if !verifySecondFactor(ctx, service.TwoFactorRedisRepository, request.UserId, func() bool {
+		return checkSecondFactor(ctx, tx, service.TwoFactorRepository, twoFactor, request.Code)
+	}) {
+		panic(exception.NewBadRequest("invalid two-factor code"))
+	}
+
+	err = service.TwoFactorRepository.DeleteRecoveryCodes(ctx, tx, request.UserId)
+	helper.PanicIfError(err)
+	err = service.TwoFactorRepository.DeleteTwoFactor(ctx, tx, request.UserId)
+	helper.PanicIfError(err)
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/ryhnfhrza/Expense-Tracker/exception"
+	"github.com/ryhnfhrza/Expense-Tracker/helper"
+	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
+	"github.com/ryhnfhrza/Expense-Tracker/model/web"
+	"github.com/ryhnfhrza/Expense-Tracker/repository"
+)
+
+// undoWindow is how long an undo token can be used.
+const undoWindow = 15 * time.Minute
+
+// saveUndo stores the steps that reverse an operation and returns the token
+// for them, or nil when there is nothing to undo. It is called inside the
+// operation's transaction, so a failure to save rolls the operation back.
+func saveUndo(ctx context.Context, undoRepository repository.UndoRedisRepository, userId int, kind string, steps []domain.UndoStep) *web.UndoTokenResponse {
+	if len(steps) == 0 {
+		return nil
+	}
+
+	buf := make([]byte, 16)
+	_, err := rand.Read(buf)
+	helper.PanicIfError(err)
+
+	now := time.Now().UTC()
+	operation := domain.UndoOperation{
+		Token:     hex.EncodeToString(buf),
+		UserId:    userId,
+		Kind:      kind,
+		Steps:     steps,
+		CreatedAt: now,
+		ExpiresAt: now.Add(undoWindow),
+	}
+
+	err = undoRepository.SaveUndo(ctx, operation, undoWindow)
+	helper.PanicIfError(err)
+
+	return &web.UndoTokenResponse{
+		Token:     operation.Token,
+		ExpiresAt: operation.ExpiresAt,
+	}
+}
+
+// restoreExpense takes an expense out of the trash. An expense whose
+// category is in the trash too can only come back with its category.
+func restoreExpense(ctx context.Context, tx *sql.Tx, trashRepository repository.TrashRepository, categoryRepository repository.CategoryRepository, auditRepository repository.AuditRepository, webhookRepository repository.WebhookRepository, trashed domain.TrashedExpense) web.ExpenseResponse {
+	expense := trashed.Expense
+	if _, err := categoryRepository.FindCategoryById(ctx, tx, expense.CategoryId, expense.UserId); err != nil {
+		panic(exception.NewConflictError(fmt.Sprintf("category with id:%d is in the trash, restore it first", expense.CategoryId)))
+	}
+
+	err := trashRepository.RestoreExpense(ctx, tx, expense.Id, expense.UserId)
+	helper.PanicIfError(err)
+
+	response := helper.ToExpenseResponse(expense, trashed.CategoryName)
+	recordExpenseChange(ctx, tx, auditRepository, webhookRepository, expense.UserId, expense.Id, domain.AuditActionRestore, nil, response)
+
+	return response
+}
+
+// restoreCategory takes a category out of the trash together with the
+// expenses that were deleted with it.
+func restoreCategory(ctx context.Context, tx *sql.Tx, trashRepository repository.TrashRepository, auditRepository repository.AuditRepository, webhookRepository repository.WebhookRepository, trashed domain.TrashedCategory) web.CategoryResponse {
+	category := trashed.Category
+
+	expenses, err := trashRepository.FindTrashedExpenses(ctx, tx, category.UserId)
+	helper.PanicIfError(err)
+
+	err = trashRepository.RestoreCategory(ctx, tx, category.Id, category.UserId, trashed.DeletedAt)
+	helper.PanicIfError(err)
+
+	response := helper.ToCategoryResponse(category)
+	recordAudit(ctx, tx, auditRepository, category.UserId, domain.AuditEntityCategory, category.Id, domain.AuditActionRestore, nil, response)
+
+	for _, expense := range expenses {
+		if expense.Expense.CategoryId != category.Id || !expense.DeletedAt.Equal(trashed.DeletedAt) {
+			continue
+		}
+		recordExpenseChange(ctx, tx, auditRepository, webhookRepository, category.UserId, expense.Expense.Id, domain.AuditActionRestore, nil, helper.ToExpenseResponse(expense.Expense, expense.CategoryName))
+	}
+
+	return response
+}
+
+// categoryName looks up a category's name for audit snapshots, returning ""
+// when the category no longer exists.
+func categoryName(ctx context.Context, tx *sql.Tx, categoryRepository repository.CategoryRepository, categoryId, userId int) string {
+	category, err := categoryRepository.FindCategoryById(ctx, tx, categoryId, userId)
+	if err != nil {
+		return ""
+	}
+	return category.Name
+}
+
+// sameExpense reports whether an expense still holds the values an operation
+// left it with. Amount and time are compared at the precision MySQL stores.
+func sameExpense(a, b domain.Expense) bool {
+	return a.CategoryId == b.CategoryId &&
+		a.Description == b.Description &&
+		math.Abs(a.Amount-b.Amount) < 0.005 &&
+		a.CreatedAt.Sub(b.CreatedAt).Abs() < time.Second
+}
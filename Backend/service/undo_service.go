@@ -0,0 +1,11 @@
+package service
+
+import (
+	"context"
+
+	"github.com/ryhnfhrza/Expense-Tracker/model/web"
+)
+
+type UndoService interface {
+	Undo(ctx context.Context, token string, userId int) web.UndoResponse
+}
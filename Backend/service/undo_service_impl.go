@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/ryhnfhrza/Expense-Tracker/classifier"
+	"github.com/ryhnfhrza/Expense-Tracker/exception"
+	"github.com/ryhnfhrza/Expense-Tracker/helper"
+	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
+	"github.com/ryhnfhrza/Expense-Tracker/model/web"
+	"github.com/ryhnfhrza/Expense-Tracker/repository"
+)
+
+type undoServiceImpl struct {
+	DB                      *sql.DB
+	UndoRepository          repository.UndoRedisRepository
+	ExpenseRepository       repository.ExpenseRepository
+	CategoryRepository      repository.CategoryRepository
+	TrashRepository         repository.TrashRepository
+	ExpenseImportRepository repository.ExpenseImportRepository
+	AuditRepository         repository.AuditRepository
+	WebhookRepository       repository.WebhookRepository
+	CategoryModels          *classifier.Store
+}
+
+func NewUndoService(db *sql.DB, undoRepository repository.UndoRedisRepository, expenseRepository repository.ExpenseRepository, categoryRepository repository.CategoryRepository, trashRepository repository.TrashRepository, expenseImportRepository repository.ExpenseImportRepository, auditRepository repository.AuditRepository, webhookRepository repository.WebhookRepository, categoryModels *classifier.Store) UndoService {
+	return &undoServiceImpl{
+		DB:                      db,
+		UndoRepository:          undoRepository,
+		ExpenseRepository:       expenseRepository,
+		CategoryRepository:      categoryRepository,
+		TrashRepository:         trashRepository,
+		ExpenseImportRepository: expenseImportRepository,
+		AuditRepository:         auditRepository,
+		WebhookRepository:       webhookRepository,
+		CategoryModels:          categoryModels,
+	}
+}
+
+// Undo reverts every step of the operation behind token in one transaction.
+// The token is claimed before the transaction starts so concurrent calls
+// cannot both revert it. If any row changed since the operation, nothing is
+// reverted and the token is put back, usable until it expires.
+func (service *undoServiceImpl) Undo(ctx context.Context, token string, userId int) web.UndoResponse {
+	operation, ttl, err := service.UndoRepository.ClaimUndo(ctx, token)
+	helper.PanicIfError(err)
+	if operation == nil {
+		panic(exception.NewNotFoundError("undo token not found or expired"))
+	}
+	if operation.UserId != userId {
+		service.restoreUndo(ctx, *operation, ttl)
+		panic(exception.NewNotFoundError("undo token not found or expired"))
+	}
+
+	defer helper.AfterCommit(func() {
+		service.CategoryModels.Reset(userId)
+	})
+	defer helper.OnRollback(func() {
+		service.restoreUndo(ctx, *operation, ttl)
+	})
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	// Steps run newest first so later changes are taken back before the
+	// earlier ones they built on.
+	for i := len(operation.Steps) - 1; i >= 0; i-- {
+		service.undoStep(ctx, tx, userId, operation.Steps[i])
+	}
+
+	return web.UndoResponse{
+		Kind:     operation.Kind,
+		Reverted: len(operation.Steps),
+	}
+}
+
+func (service *undoServiceImpl) undoStep(ctx context.Context, tx *sql.Tx, userId int, step domain.UndoStep) {
+	switch step.Action {
+	case domain.UndoRestoreExpense:
+		trashed, err := service.TrashRepository.FindTrashedExpenseById(ctx, tx, step.EntityId, userId)
+		if err != nil {
+			panic(exception.NewConflictError(fmt.Sprintf("expense with id:%d is no longer in the trash", step.EntityId)))
+		}
+		restoreExpense(ctx, tx, service.TrashRepository, service.CategoryRepository, service.AuditRepository, service.WebhookRepository, *trashed)
+
+	case domain.UndoRestoreCategory:
+		trashed, err := service.TrashRepository.FindTrashedCategoryById(ctx, tx, step.EntityId, userId)
+		if err != nil {
+			panic(exception.NewConflictError(fmt.Sprintf("category with id:%d is no longer in the trash", step.EntityId)))
+		}
+		restoreCategory(ctx, tx, service.TrashRepository, service.AuditRepository, service.WebhookRepository, *trashed)
+
+	case domain.UndoRevertExpense:
+		current := service.findUnchangedExpense(ctx, tx, userId, step)
+		if _, err := service.CategoryRepository.FindCategoryById(ctx, tx, step.Before.CategoryId, userId); err != nil {
+			panic(exception.NewConflictError(fmt.Sprintf("category with id:%d is in the trash, restore it first", step.Before.CategoryId)))
+		}
+
+		reverted := *step.Before
+		reverted.UserId = userId
+		reverted.Version = current.Version
+		_, err := service.ExpenseRepository.UpdateExpense(ctx, tx, &reverted)
+		if errors.Is(err, sql.ErrNoRows) {
+			panic(exception.NewConflictError(fmt.Sprintf("expense with id:%d changed since, it can no longer be undone", step.EntityId)))
+		}
+		helper.PanicIfError(err)
+
+		before := helper.ToExpenseResponse(current, categoryName(ctx, tx, service.CategoryRepository, current.CategoryId, userId))
+		after := helper.ToExpenseResponse(reverted, categoryName(ctx, tx, service.CategoryRepository, reverted.CategoryId, userId))
+		recordExpenseChange(ctx, tx, service.AuditRepository, service.WebhookRepository, userId, step.EntityId, domain.AuditActionUpdate, before, after)
+
+	case domain.UndoRemoveExpense:
+		current := service.findUnchangedExpense(ctx, tx, userId, step)
+
+		err := service.ExpenseImportRepository.DeleteImport(ctx, tx, userId, step.EntityId)
+		helper.PanicIfError(err)
+		err = service.ExpenseRepository.DeleteExpanse(ctx, tx, step.EntityId, userId)
+		if errors.Is(err, sql.ErrNoRows) {
+			panic(exception.NewConflictError(fmt.Sprintf("expense with id:%d changed since, it can no longer be undone", step.EntityId)))
+		}
+		helper.PanicIfError(err)
+		err = service.TrashRepository.PurgeExpense(ctx, tx, step.EntityId, userId)
+		helper.PanicIfError(err)
+
+		before := helper.ToExpenseResponse(current, categoryName(ctx, tx, service.CategoryRepository, current.CategoryId, userId))
+		recordExpenseChange(ctx, tx, service.AuditRepository, service.WebhookRepository, userId, step.EntityId, domain.AuditActionDelete, before, nil)
+
+	default:
+		panic(fmt.Errorf("unknown undo action %q", step.Action))
+	}
+}
+
+func (service *undoServiceImpl) restoreUndo(ctx context.Context, operation domain.UndoOperation, ttl time.Duration) {
+	if err := service.UndoRepository.RestoreUndo(ctx, operation, ttl); err != nil {
+		log.Printf("undo: %v", err)
+	}
+}
+
+// findUnchangedExpense returns the expense a step applies to, refusing to go
+// on when it was edited or deleted after the operation.
+func (service *undoServiceImpl) findUnchangedExpense(ctx context.Context, tx *sql.Tx, userId int, step domain.UndoStep) domain.Expense {
+	expense, err := service.ExpenseRepository.FindExpanseById(ctx, tx, step.EntityId, userId)
+	if err != nil || !sameExpense(*expense, *step.After) {
+		panic(exception.NewConflictError(fmt.Sprintf("expense with id:%d changed since, it can no longer be undone", step.EntityId)))
+	}
+	expense.UserId = userId
+
+	return *expense
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"time"
 
 	"github.com/ryhnfhrza/Expense-Tracker/model/web"
 )
@@ -10,4 +11,22 @@ type UserService interface {
 	Register(ctx context.Context, req web.UserRequest) web.UserResponse
 	VerifyEmail(ctx context.Context, email, code string) web.UserResponse
 	Login(ctx context.Context, req web.UserLoginRequest) (web.UserLoginResponse, error)
+	LoginTwoFactor(ctx context.Context, req web.UserTwoFactorLoginRequest) web.UserLoginResponse
+	FindProfile(ctx context.Context, userId int) web.UserProfileResponse
+	UpdateProfile(ctx context.Context, req web.UserProfileUpdateRequest) web.UserProfileResponse
+	ChangePassword(ctx context.Context, req web.UserPasswordChangeRequest)
+	// RequestEmailChange sends a code to the new address; the email only
+	// changes once VerifyEmailChange gets that code.
+	RequestEmailChange(ctx context.Context, req web.UserEmailChangeRequest) web.UserResponse
+	VerifyEmailChange(ctx context.Context, req web.UserEmailVerifyRequest) web.UserProfileResponse
+	// ScheduleDeletion deletes the account and all its data once the grace
+	// period is over, unless CancelDeletion is called first.
+	ScheduleDeletion(ctx context.Context, req web.UserDeleteRequest) web.UserProfileResponse
+	CancelDeletion(ctx context.Context, userId int) web.UserProfileResponse
+	// FindPreferences returns the defaults for a user who never saved any.
+	FindPreferences(ctx context.Context, userId int) web.UserPreferencesResponse
+	UpdatePreferences(ctx context.Context, req web.UserPreferencesUpdateRequest) web.UserPreferencesResponse
+	// PurgeDeletedAccounts deletes the accounts whose grace period is over
+	// and returns how many it deleted.
+	PurgeDeletedAccounts(ctx context.Context, now time.Time) int
 }
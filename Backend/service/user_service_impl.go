@@ -3,6 +3,10 @@ package service
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
 	"time"
 
 	"github.com/go-playground/validator/v10"
@@ -15,18 +19,26 @@ import (
 )
 
 type userServiceImpl struct {
-	UserRepository      repository.UserRepository
-	UserRedisRepository repository.UserRedisRepository
-	Validate            *validator.Validate
-	DB                  *sql.DB
+	UserRepository           repository.UserRepository
+	UserRedisRepository      repository.UserRedisRepository
+	TwoFactorRepository      repository.TwoFactorRepository
+	TwoFactorRedisRepository repository.TwoFactorRedisRepository
+	PreferencesRepository    repository.UserPreferencesRepository
+	Validate                 *validator.Validate
+	DB                       *sql.DB
+	DeletionGrace            time.Duration
 }
 
-func NewUserService(userRepository repository.UserRepository, userRedisRepository repository.UserRedisRepository, validate *validator.Validate, db *sql.DB) UserService {
+func NewUserService(userRepository repository.UserRepository, userRedisRepository repository.UserRedisRepository, twoFactorRepository repository.TwoFactorRepository, twoFactorRedisRepository repository.TwoFactorRedisRepository, preferencesRepository repository.UserPreferencesRepository, validate *validator.Validate, db *sql.DB, deletionGrace time.Duration) UserService {
 	return &userServiceImpl{
-		UserRepository:      userRepository,
-		UserRedisRepository: userRedisRepository,
-		Validate:            validate,
-		DB:                  db,
+		UserRepository:           userRepository,
+		UserRedisRepository:      userRedisRepository,
+		TwoFactorRepository:      twoFactorRepository,
+		TwoFactorRedisRepository: twoFactorRedisRepository,
+		PreferencesRepository:    preferencesRepository,
+		Validate:                 validate,
+		DB:                       db,
+		DeletionGrace:            deletionGrace,
 	}
 }
 
@@ -127,8 +139,370 @@ func (service *userServiceImpl) Login(ctx context.Context, req web.UserLoginRequ
 		panic(exception.NewUnauthorizedError("username or password invalid!"))
 	}
 
+	return startLogin(ctx, tx, service.TwoFactorRepository, service.TwoFactorRedisRepository, user), nil
+}
+
+// accountPurgeBatch caps how many accounts one PurgeDeletedAccounts run
+// deletes.
+const accountPurgeBatch = 100
+
+// LoginTwoFactor finishes a login that Login answered with a challenge.
+// A challenge allows a few wrong codes before the user has to log in again,
+// and the user's second factor locks after more across challenges.
+func (service *userServiceImpl) LoginTwoFactor(ctx context.Context, req web.UserTwoFactorLoginRequest) web.UserLoginResponse {
+	err := service.Validate.Struct(req)
+	helper.PanicIfError(err)
+
+	challenge, err := service.TwoFactorRedisRepository.GetChallenge(ctx, req.ChallengeToken)
+	helper.PanicIfError(err)
+	if challenge == nil {
+		panic(exception.NewUnauthorizedError("login expired, please log in again"))
+	}
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	twoFactor, err := service.TwoFactorRepository.FindTwoFactor(ctx, tx, challenge.UserId)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		panic(err)
+	}
+
+	// Two-factor authentication may have been turned off since; the
+	// password was checked either way.
+	if twoFactor != nil && twoFactor.ConfirmedAt != nil && !verifySecondFactor(ctx, service.TwoFactorRedisRepository, challenge.UserId, func() bool {
+		return checkSecondFactor(ctx, tx, service.TwoFactorRepository, twoFactor, req.Code)
+	}) {
+		attempts, err := service.TwoFactorRedisRepository.IncrementChallengeAttempt(ctx, req.ChallengeToken, twoFactorChallengeTTL)
+		helper.PanicIfError(err)
+		if attempts >= twoFactorMaxAttempts {
+			_ = service.TwoFactorRedisRepository.DeleteChallenge(ctx, req.ChallengeToken)
+			panic(exception.NewTooManyRequestError("too many invalid codes, please log in again"))
+		}
+		panic(exception.NewUnauthorizedError("invalid two-factor code"))
+	}
+
+	err = service.TwoFactorRedisRepository.DeleteChallenge(ctx, req.ChallengeToken)
+	helper.PanicIfError(err)
+
+	user, err := service.UserRepository.FindById(ctx, tx, challenge.UserId)
+	helper.PanicIfError(err)
+
 	token, err := util.CreateToken(user)
 	helper.PanicIfError(err)
 
-	return helper.ToUserLoginResponse(*user, token), nil
+	return helper.ToUserLoginResponse(*user, token)
+}
+
+func (service *userServiceImpl) FindProfile(ctx context.Context, userId int) web.UserProfileResponse {
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	user := service.findUser(ctx, tx, userId)
+
+	return service.toProfile(ctx, tx, user)
+}
+
+func (service *userServiceImpl) UpdateProfile(ctx context.Context, req web.UserProfileUpdateRequest) web.UserProfileResponse {
+	err := service.Validate.Struct(req)
+	helper.PanicIfError(err)
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	user := service.findUser(ctx, tx, req.UserId)
+
+	if req.Username != user.Username {
+		_, err = service.UserRepository.FindByUsername(ctx, tx, req.Username)
+		if err == nil {
+			panic(exception.NewConflictError("username already exist!"))
+		}
+
+		user.Username = req.Username
+		err = service.UserRepository.UpdateUser(ctx, tx, user)
+		helper.PanicIfError(err)
+	}
+
+	return service.toProfile(ctx, tx, user)
+}
+
+func (service *userServiceImpl) FindPreferences(ctx context.Context, userId int) web.UserPreferencesResponse {
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	preferences := findPreferences(ctx, tx, service.PreferencesRepository, userId)
+
+	return helper.ToUserPreferencesResponse(preferences)
+}
+
+func (service *userServiceImpl) UpdatePreferences(ctx context.Context, req web.UserPreferencesUpdateRequest) web.UserPreferencesResponse {
+	if req.Currency != nil {
+		currency := strings.ToUpper(*req.Currency)
+		req.Currency = &currency
+	}
+	if req.WeekStart != nil {
+		weekStart := strings.ToLower(*req.WeekStart)
+		req.WeekStart = &weekStart
+	}
+
+	err := service.Validate.Struct(req)
+	helper.PanicIfError(err)
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	preferences := findPreferences(ctx, tx, service.PreferencesRepository, req.UserId)
+	if req.Timezone != nil {
+		preferences.Timezone = *req.Timezone
+	}
+	if req.Locale != nil {
+		preferences.Locale = *req.Locale
+	}
+	if req.Currency != nil {
+		preferences.Currency = *req.Currency
+	}
+	if req.WeekStart != nil {
+		preferences.WeekStart = *req.WeekStart
+	}
+	if req.DateFormat != nil {
+		preferences.DateFormat = *req.DateFormat
+	}
+
+	err = service.PreferencesRepository.SavePreferences(ctx, tx, &preferences)
+	helper.PanicIfError(err)
+
+	return helper.ToUserPreferencesResponse(preferences)
+}
+
+func (service *userServiceImpl) ChangePassword(ctx context.Context, req web.UserPasswordChangeRequest) {
+	err := service.Validate.Struct(req)
+	helper.PanicIfError(err)
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	user := service.findUser(ctx, tx, req.UserId)
+	service.requireReauthentication(ctx, tx, user, req.CurrentPassword, req.TwoFactorCode)
+
+	user.PasswordHash, err = util.HashPassword(req.NewPassword)
+	helper.PanicIfError(err)
+
+	err = service.UserRepository.UpdateUser(ctx, tx, user)
+	helper.PanicIfError(err)
+}
+
+func (service *userServiceImpl) RequestEmailChange(ctx context.Context, req web.UserEmailChangeRequest) web.UserResponse {
+	err := service.Validate.Struct(req)
+	helper.PanicIfError(err)
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	user := service.findUser(ctx, tx, req.UserId)
+	service.requireReauthentication(ctx, tx, user, req.Password, req.TwoFactorCode)
+
+	if strings.EqualFold(req.Email, user.Email) {
+		panic(exception.NewBadRequest("this is already your email"))
+	}
+	_, err = service.UserRepository.FindByEmail(ctx, tx, req.Email)
+	if err == nil {
+		panic(exception.NewConflictError("email already exist!"))
+	}
+
+	code, err := util.Generate6DigitCode()
+	helper.PanicIfError(err)
+
+	key := emailChangeKey(user.Id)
+	_ = service.UserRedisRepository.DeleteTempUser(ctx, key)
+
+	err = service.UserRedisRepository.SaveTempUser(ctx, key, 5*time.Minute, domain.TempUser{
+		Username: user.Username,
+		Email:    req.Email,
+		Code:     code,
+	})
+	helper.PanicIfError(err)
+
+	go func() {
+		if err := helper.SendVerificationEmail(req.Email, code); err != nil {
+			log.Printf("email change: failed to send code to user %d: %v", user.Id, err)
+		}
+	}()
+
+	return helper.ToUserResponse(domain.User{
+		Username: user.Username,
+		Email:    req.Email,
+	})
+}
+
+func (service *userServiceImpl) VerifyEmailChange(ctx context.Context, req web.UserEmailVerifyRequest) web.UserProfileResponse {
+	err := service.Validate.Struct(req)
+	helper.PanicIfError(err)
+
+	key := emailChangeKey(req.UserId)
+
+	attempts, _ := service.UserRedisRepository.GetAttempts(ctx, key)
+	if attempts >= 3 {
+		panic(exception.NewTooManyRequestError("too many attempts, please wait 5 minutes"))
+	}
+
+	tempUser, err := service.UserRedisRepository.GetTempUser(ctx, key)
+	if err != nil {
+		panic(exception.NewNotFoundError("verification code expired or invalid. please request a new one"))
+	}
+
+	if tempUser.Code != req.Code {
+		_ = service.UserRedisRepository.IncrementAttempt(ctx, key, 5*time.Minute)
+		panic(exception.NewBadRequest("verification code is invalid"))
+	}
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	// The address may have been taken while the code was on its way.
+	_, err = service.UserRepository.FindByEmail(ctx, tx, tempUser.Email)
+	if err == nil {
+		panic(exception.NewConflictError("email already exist!"))
+	}
+
+	user := service.findUser(ctx, tx, req.UserId)
+	user.Email = tempUser.Email
+	err = service.UserRepository.UpdateUser(ctx, tx, user)
+	helper.PanicIfError(err)
+
+	err = service.UserRedisRepository.DeleteTempUser(ctx, key)
+	helper.PanicIfError(err)
+
+	return service.toProfile(ctx, tx, user)
+}
+
+func (service *userServiceImpl) ScheduleDeletion(ctx context.Context, req web.UserDeleteRequest) web.UserProfileResponse {
+	err := service.Validate.Struct(req)
+	helper.PanicIfError(err)
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	user := service.findUser(ctx, tx, req.UserId)
+	service.requireReauthentication(ctx, tx, user, req.Password, req.TwoFactorCode)
+
+	if user.DeletionScheduledAt == nil {
+		deletionAt := time.Now().UTC().Add(service.DeletionGrace)
+		user.DeletionScheduledAt = &deletionAt
+		err = service.UserRepository.UpdateUser(ctx, tx, user)
+		helper.PanicIfError(err)
+	}
+
+	return service.toProfile(ctx, tx, user)
+}
+
+func (service *userServiceImpl) CancelDeletion(ctx context.Context, userId int) web.UserProfileResponse {
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	user := service.findUser(ctx, tx, userId)
+	if user.DeletionScheduledAt == nil {
+		panic(exception.NewNotFoundError("account deletion is not scheduled"))
+	}
+
+	user.DeletionScheduledAt = nil
+	err = service.UserRepository.UpdateUser(ctx, tx, user)
+	helper.PanicIfError(err)
+
+	return service.toProfile(ctx, tx, user)
+}
+
+func (service *userServiceImpl) PurgeDeletedAccounts(ctx context.Context, now time.Time) int {
+	purged := 0
+	for purged < accountPurgeBatch && service.purgeNextAccount(ctx, now) {
+		purged++
+	}
+
+	return purged
+}
+
+// purgeNextAccount deletes one account that is due, with all its data, in
+// a single transaction. It reports whether there was one.
+func (service *userServiceImpl) purgeNextAccount(ctx context.Context, now time.Time) bool {
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	userIds, err := service.UserRepository.FindUsersDueForDeletion(ctx, tx, now, 1)
+	helper.PanicIfError(err)
+	if len(userIds) == 0 {
+		return false
+	}
+
+	err = service.UserRepository.DeleteUser(ctx, tx, userIds[0])
+	helper.PanicIfError(err)
+
+	return true
+}
+
+func (service *userServiceImpl) findUser(ctx context.Context, tx *sql.Tx, userId int) *domain.User {
+	user, err := service.UserRepository.FindById(ctx, tx, userId)
+	if errors.Is(err, sql.ErrNoRows) {
+		panic(exception.NewNotFoundError("user not found"))
+	}
+	helper.PanicIfError(err)
+
+	return user
+}
+
+func (service *userServiceImpl) toProfile(ctx context.Context, tx *sql.Tx, user *domain.User) web.UserProfileResponse {
+	twoFactor, err := service.TwoFactorRepository.FindTwoFactor(ctx, tx, user.Id)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		panic(err)
+	}
+
+	return helper.ToUserProfileResponse(*user, twoFactor != nil && twoFactor.ConfirmedAt != nil)
+}
+
+// ssoReauthWindow is how recently an account without a password must have
+// signed in at the provider to change the account.
+const ssoReauthWindow = 5 * time.Minute
+
+// requireReauthentication guards changes to the account with the current
+// password. Accounts created through single sign-on have none to give: they
+// give a two-factor code when two-factor authentication is on, and must
+// otherwise have signed in within ssoReauthWindow, so a leaked session
+// alone cannot take the account over.
+func (service *userServiceImpl) requireReauthentication(ctx context.Context, tx *sql.Tx, user *domain.User, password, twoFactorCode string) {
+	if user.PasswordHash != "" {
+		if !util.CheckPasswordHash(password, user.PasswordHash) {
+			panic(exception.NewForbiddenError("password is incorrect"))
+		}
+		return
+	}
+
+	twoFactor, err := service.TwoFactorRepository.FindTwoFactor(ctx, tx, user.Id)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		panic(err)
+	}
+	if twoFactor != nil && twoFactor.ConfirmedAt != nil {
+		if !verifySecondFactor(ctx, service.TwoFactorRedisRepository, user.Id, func() bool {
+			return checkSecondFactor(ctx, tx, service.TwoFactorRepository, twoFactor, twoFactorCode)
+		}) {
+			panic(exception.NewForbiddenError("two-factor code is incorrect"))
+		}
+		return
+	}
+
+	signedInAt, ok := helper.GetSignedInAtFromContext(ctx)
+	if !ok || time.Since(signedInAt) > ssoReauthWindow {
+		panic(exception.NewForbiddenError("please sign in again to confirm this change"))
+	}
+}
+
+func emailChangeKey(userId int) string {
+	return fmt.Sprintf("email-change:%d", userId)
 }
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/ryhnfhrza/Expense-Tracker/helper"
+	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
+	"github.com/ryhnfhrza/Expense-Tracker/repository"
+)
+
+// expenseWebhookEvents maps audit actions on expenses to the webhook event
+// they raise. A restore brings an expense back, so it counts as a create;
+// a purge removes one that was already reported deleted.
+var expenseWebhookEvents = map[string]string{
+	domain.AuditActionCreate:  domain.WebhookEventExpenseCreated,
+	domain.AuditActionRestore: domain.WebhookEventExpenseCreated,
+	domain.AuditActionUpdate:  domain.WebhookEventExpenseUpdated,
+	domain.AuditActionDelete:  domain.WebhookEventExpenseDeleted,
+}
+
+// recordExpenseChange audits a change to an expense and queues the webhook
+// event for it in the outbox, both inside tx: a rolled back change sends
+// nothing and a committed one cannot lose its event.
+func recordExpenseChange(ctx context.Context, tx *sql.Tx, auditRepository repository.AuditRepository, webhookRepository repository.WebhookRepository, userId, expenseId int, action string, before, after any) {
+	recordAudit(ctx, tx, auditRepository, userId, domain.AuditEntityExpense, expenseId, action, before, after)
+
+	eventType, ok := expenseWebhookEvents[action]
+	if !ok {
+		return
+	}
+
+	data := map[string]any{"expense": after}
+	switch eventType {
+	case domain.WebhookEventExpenseUpdated:
+		data["previous"] = before
+	case domain.WebhookEventExpenseDeleted:
+		data["expense"] = before
+	}
+
+	recordWebhookEvent(ctx, tx, webhookRepository, userId, eventType, data)
+}
+
+// recordWebhookEvent writes an event to the outbox inside tx. The dispatch
+// job picks it up once tx has committed.
+func recordWebhookEvent(ctx context.Context, tx *sql.Tx, webhookRepository repository.WebhookRepository, userId int, eventType string, data any) {
+	payload, err := json.Marshal(data)
+	helper.PanicIfError(err)
+
+	event := domain.WebhookEvent{
+		UserId:    userId,
+		Type:      eventType,
+		Payload:   payload,
+		CreatedAt: time.Now().UTC(),
+	}
+	err = webhookRepository.SaveWebhookEvent(ctx, tx, &event)
+	helper.PanicIfError(err)
+}
@@ -0,0 +1,23 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
+	"github.com/ryhnfhrza/Expense-Tracker/model/web"
+)
+
+type WebhookService interface {
+	CreateWebhook(ctx context.Context, request web.WebhookCreateRequest) web.WebhookResponse
+	DeleteWebhook(ctx context.Context, webhookId, userId int)
+	FindAllWebhook(ctx context.Context, userId int) []web.WebhookResponse
+	FindAllDeliveries(ctx context.Context, userId int, filter domain.WebhookDeliveryFilter) web.WebhookDeliveryListResponse
+	RetryDelivery(ctx context.Context, deliveryId int64, userId int) web.WebhookDeliveryResponse
+	// DispatchEvents fans outbox events out to the webhooks subscribed to
+	// them and returns how many events it handled.
+	DispatchEvents(ctx context.Context, now time.Time) int
+	// DeliverDue sends the deliveries that are due and returns how many it
+	// attempted.
+	DeliverDue(ctx context.Context, now time.Time) int
+}
@@ -0,0 +1,357 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/ryhnfhrza/Expense-Tracker/exception"
+	"github.com/ryhnfhrza/Expense-Tracker/helper"
+	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
+	"github.com/ryhnfhrza/Expense-Tracker/model/web"
+	"github.com/ryhnfhrza/Expense-Tracker/repository"
+	"github.com/ryhnfhrza/Expense-Tracker/util"
+)
+
+const (
+	webhookSecretPrefix = "whsec_"
+	webhookBatchSize    = 100
+
+	// A failed delivery is retried after 30s, 1m, 2m and so on, capped at
+	// 6h, and moves to the dead-letter list after webhookMaxAttempts.
+	webhookMaxAttempts = 10
+	webhookBaseBackoff = 30 * time.Second
+	webhookMaxBackoff  = 6 * time.Hour
+
+	// webhookLease keeps a claimed delivery from being picked up again
+	// while it is being sent, well past the client's 10s timeout. If the
+	// sender dies, the delivery comes due again once the lease runs out.
+	webhookLease = 2 * time.Minute
+
+	defaultWebhookDeliveryLimit = 50
+	maxWebhookDeliveryLimit     = 200
+)
+
+type webhookServiceImpl struct {
+	DB                *sql.DB
+	Validate          *validator.Validate
+	WebhookRepository repository.WebhookRepository
+	Client            *http.Client
+}
+
+func NewWebhookService(db *sql.DB, validate *validator.Validate, webhookRepository repository.WebhookRepository, client *http.Client) WebhookService {
+	return &webhookServiceImpl{
+		DB:                db,
+		Validate:          validate,
+		WebhookRepository: webhookRepository,
+		Client:            client,
+	}
+}
+
+// CreateWebhook registers an endpoint and returns it with the secret its
+// deliveries are signed with. The secret is not shown again.
+func (service *webhookServiceImpl) CreateWebhook(ctx context.Context, request web.WebhookCreateRequest) web.WebhookResponse {
+	err := service.Validate.Struct(request)
+	helper.PanicIfError(err)
+
+	parsed, err := url.Parse(request.Url)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		panic(exception.NewBadRequest("url must be an http or https URL"))
+	}
+	// Deliveries only connect to public addresses. Hosts that resolve to a
+	// private one are caught when sending; obvious ones are refused here.
+	host := strings.ToLower(parsed.Hostname())
+	if addr, err := netip.ParseAddr(host); (err == nil && !util.IsPublicAddress(addr)) || host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		panic(exception.NewBadRequest("url must point to a public address"))
+	}
+
+	var events []string
+	for _, event := range request.Events {
+		if !slices.Contains(events, event) {
+			events = append(events, event)
+		}
+	}
+
+	buf := make([]byte, 24)
+	_, err = rand.Read(buf)
+	helper.PanicIfError(err)
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	webhook := domain.Webhook{
+		UserId:    request.UserId,
+		Url:       request.Url,
+		Secret:    webhookSecretPrefix + hex.EncodeToString(buf),
+		Events:    events,
+		CreatedAt: time.Now().UTC(),
+	}
+	err = service.WebhookRepository.SaveWebhook(ctx, tx, &webhook)
+	helper.PanicIfError(err)
+
+	response := helper.ToWebhookResponse(webhook)
+	response.Secret = webhook.Secret
+
+	return response
+}
+
+// DeleteWebhook removes the endpoint together with its deliveries.
+func (service *webhookServiceImpl) DeleteWebhook(ctx context.Context, webhookId, userId int) {
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	err = service.WebhookRepository.DeleteWebhook(ctx, tx, webhookId, userId)
+	if errors.Is(err, sql.ErrNoRows) {
+		panic(exception.NewNotFoundError(fmt.Sprintf("webhook with id:%d not found", webhookId)))
+	}
+	helper.PanicIfError(err)
+}
+
+func (service *webhookServiceImpl) FindAllWebhook(ctx context.Context, userId int) []web.WebhookResponse {
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	webhooks, err := service.WebhookRepository.FindAllWebhook(ctx, tx, userId)
+	helper.PanicIfError(err)
+
+	return helper.ToWebhookResponses(webhooks)
+}
+
+func (service *webhookServiceImpl) FindAllDeliveries(ctx context.Context, userId int, filter domain.WebhookDeliveryFilter) web.WebhookDeliveryListResponse {
+	if filter.Limit <= 0 {
+		filter.Limit = defaultWebhookDeliveryLimit
+	}
+	if filter.Limit > maxWebhookDeliveryLimit {
+		filter.Limit = maxWebhookDeliveryLimit
+	}
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	deliveries, err := service.WebhookRepository.FindAllDeliveries(ctx, tx, userId, filter)
+	helper.PanicIfError(err)
+
+	return helper.ToWebhookDeliveryListResponse(deliveries, filter.Limit)
+}
+
+// RetryDelivery takes a delivery off the dead-letter list and queues it
+// with a fresh set of attempts.
+func (service *webhookServiceImpl) RetryDelivery(ctx context.Context, deliveryId int64, userId int) web.WebhookDeliveryResponse {
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	delivery, err := service.WebhookRepository.FindDeliveryById(ctx, tx, deliveryId, userId)
+	if errors.Is(err, sql.ErrNoRows) {
+		panic(exception.NewNotFoundError(fmt.Sprintf("webhook delivery with id:%d not found", deliveryId)))
+	}
+	helper.PanicIfError(err)
+
+	if delivery.Status != domain.WebhookDeliveryDead {
+		panic(exception.NewConflictError(fmt.Sprintf("webhook delivery with id:%d is %s, only dead deliveries can be retried", deliveryId, delivery.Status)))
+	}
+
+	delivery.Status = domain.WebhookDeliveryPending
+	delivery.Attempts = 0
+	delivery.NextAttemptAt = time.Now().UTC()
+	err = service.WebhookRepository.UpdateDelivery(ctx, tx, delivery)
+	helper.PanicIfError(err)
+
+	return helper.ToWebhookDeliveryResponse(*delivery)
+}
+
+func (service *webhookServiceImpl) DispatchEvents(ctx context.Context, now time.Time) int {
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	events, err := service.WebhookRepository.FindUndispatchedEvents(ctx, tx, webhookBatchSize)
+	helper.PanicIfError(err)
+
+	webhooksByUser := map[int][]domain.Webhook{}
+	for _, event := range events {
+		webhooks, ok := webhooksByUser[event.UserId]
+		if !ok {
+			webhooks, err = service.WebhookRepository.FindAllWebhook(ctx, tx, event.UserId)
+			helper.PanicIfError(err)
+			webhooksByUser[event.UserId] = webhooks
+		}
+
+		subscribed := 0
+		for _, webhook := range webhooks {
+			if !slices.Contains(webhook.Events, event.Type) {
+				continue
+			}
+			delivery := domain.WebhookDelivery{
+				WebhookId:     webhook.Id,
+				EventId:       event.Id,
+				Status:        domain.WebhookDeliveryPending,
+				NextAttemptAt: now,
+				CreatedAt:     now,
+			}
+			err = service.WebhookRepository.SaveDelivery(ctx, tx, &delivery)
+			helper.PanicIfError(err)
+			subscribed++
+		}
+
+		// Most users have no webhooks; their events are dropped rather than
+		// kept around.
+		if subscribed == 0 {
+			err = service.WebhookRepository.DeleteWebhookEvent(ctx, tx, event.Id)
+		} else {
+			err = service.WebhookRepository.MarkEventDispatched(ctx, tx, event.Id, now)
+		}
+		helper.PanicIfError(err)
+	}
+
+	return len(events)
+}
+
+// DeliverDue sends up to webhookBatchSize due deliveries one at a time.
+// Each is leased just before it is sent, so the lease only has to outlast
+// one request and other workers can pick up the rest meanwhile.
+func (service *webhookServiceImpl) DeliverDue(ctx context.Context, now time.Time) int {
+	sent := 0
+	for sent < webhookBatchSize {
+		delivery := service.claimDueDelivery(ctx, now)
+		if delivery == nil {
+			break
+		}
+		service.deliver(ctx, delivery)
+		sent++
+	}
+
+	return sent
+}
+
+// claimDueDelivery leases the next due delivery in a short transaction, so
+// no row lock is held while the endpoint is called. It returns nil when
+// none is due.
+func (service *webhookServiceImpl) claimDueDelivery(ctx context.Context, now time.Time) *domain.WebhookDelivery {
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	deliveries, err := service.WebhookRepository.FindDueDeliveries(ctx, tx, now, 1)
+	helper.PanicIfError(err)
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	delivery := deliveries[0]
+	delivery.NextAttemptAt = time.Now().UTC().Add(webhookLease)
+	err = service.WebhookRepository.UpdateDelivery(ctx, tx, &delivery)
+	helper.PanicIfError(err)
+
+	return &delivery
+}
+
+func (service *webhookServiceImpl) deliver(ctx context.Context, delivery *domain.WebhookDelivery) {
+	statusCode, sendErr := service.send(ctx, *delivery)
+
+	now := time.Now().UTC()
+	delivery.Attempts++
+	delivery.LastStatusCode = statusCode
+	if sendErr == nil {
+		delivery.Status = domain.WebhookDeliveryDelivered
+		delivery.DeliveredAt = &now
+		delivery.LastError = ""
+	} else {
+		delivery.LastError = sendErr.Error()
+		if len(delivery.LastError) > 255 {
+			delivery.LastError = delivery.LastError[:255]
+		}
+		if delivery.Attempts >= webhookMaxAttempts {
+			delivery.Status = domain.WebhookDeliveryDead
+		} else {
+			delivery.NextAttemptAt = now.Add(webhookBackoff(delivery.Attempts))
+		}
+	}
+
+	tx, err := service.DB.Begin()
+	helper.PanicIfError(err)
+	defer helper.CommitOrRollback(tx)
+
+	err = service.WebhookRepository.UpdateDelivery(ctx, tx, delivery)
+	helper.PanicIfError(err)
+}
+
+// send POSTs the event to the webhook. Anything but a 2xx answer counts as
+// a failure; statusCode is nil when no answer came at all.
+func (service *webhookServiceImpl) send(ctx context.Context, delivery domain.WebhookDelivery) (*int, error) {
+	body, err := json.Marshal(web.WebhookEventPayload{
+		Id:        fmt.Sprintf("evt_%d", delivery.EventId),
+		Type:      delivery.EventType,
+		CreatedAt: delivery.EventCreatedAt,
+		Data:      delivery.Payload,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.Url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("User-Agent", "Expense-Tracker-Webhooks/1.0")
+	request.Header.Set("X-Webhook-Event", delivery.EventType)
+	request.Header.Set("X-Webhook-Delivery", strconv.FormatInt(delivery.Id, 10))
+	request.Header.Set("X-Webhook-Signature", signWebhook(delivery.Secret, time.Now().UTC(), body))
+
+	response, err := service.Client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(response.Body, 64<<10))
+
+	statusCode := response.StatusCode
+	if statusCode < 200 || statusCode > 299 {
+		return &statusCode, fmt.Errorf("endpoint answered %d", statusCode)
+	}
+
+	return &statusCode, nil
+}
+
+// signWebhook returns the X-Webhook-Signature header for body:
+// "t=<unix seconds>,v1=<hex HMAC-SHA256 of "<t>.<body>">". Signing the
+// timestamp lets receivers reject old deliveries replayed at them.
+func signWebhook(secret string, at time.Time, body []byte) string {
+	timestamp := strconv.FormatInt(at.Unix(), 10)
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+
+	return "t=" + timestamp + ",v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// webhookBackoff is the wait after the given number of failed attempts.
+func webhookBackoff(attempts int) time.Duration {
+	backoff := webhookBaseBackoff
+	for i := 1; i < attempts && backoff < webhookMaxBackoff; i++ {
+		backoff *= 2
+	}
+	return min(backoff, webhookMaxBackoff)
+}
@@ -0,0 +1,165 @@
+package statement
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
+)
+
+// Only the parts of camt.053 needed for expenses are mapped. Element names
+// are matched without namespace so camt.053.001.02 through .08 all work.
+type camtDocument struct {
+	Statements []struct {
+		IBAN    string      `xml:"Acct>Id>IBAN"`
+		OtherId string      `xml:"Acct>Id>Othr>Id"`
+		Entries []camtEntry `xml:"Ntry"`
+	} `xml:"BkToCstmrStmt>Stmt"`
+}
+
+type camtEntry struct {
+	Amount struct {
+		Value    string `xml:",chardata"`
+		Currency string `xml:"Ccy,attr"`
+	} `xml:"Amt"`
+	CreditDebit    string          `xml:"CdtDbtInd"`
+	Status         camtStatus      `xml:"Sts"`
+	BookingDate    camtDate        `xml:"BookgDt"`
+	ValueDate      camtDate        `xml:"ValDt"`
+	ServicerRef    string          `xml:"AcctSvcrRef"`
+	EntryRef       string          `xml:"NtryRef"`
+	AdditionalInfo string          `xml:"AddtlNtryInf"`
+	Details        []camtTxDetails `xml:"NtryDtls>TxDtls"`
+}
+
+// camtStatus is a plain code up to version .06 (<Sts>BOOK</Sts>) and wrapped
+// from .07 on (<Sts><Cd>BOOK</Cd></Sts>).
+type camtStatus struct {
+	Text string `xml:",chardata"`
+	Code string `xml:"Cd"`
+}
+
+type camtDate struct {
+	Date     string `xml:"Dt"`
+	DateTime string `xml:"DtTm"`
+}
+
+type camtTxDetails struct {
+	ServicerRef    string   `xml:"Refs>AcctSvcrRef"`
+	EndToEndId     string   `xml:"Refs>EndToEndId"`
+	Creditor       string   `xml:"RltdPties>Cdtr>Nm"`
+	CreditorParty  string   `xml:"RltdPties>Cdtr>Pty>Nm"`
+	Unstructured   []string `xml:"RmtInf>Ustrd"`
+	AdditionalInfo string   `xml:"AddtlTxInf"`
+}
+
+// ParseCAMT053 reads the booked entries of an ISO 20022 bank-to-customer
+// statement. Pending entries are skipped because they may still change.
+func ParseCAMT053(data []byte) ([]domain.StatementTransaction, error) {
+	var document camtDocument
+	if err := xml.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("invalid CAMT.053: %w", err)
+	}
+	if len(document.Statements) == 0 {
+		return nil, fmt.Errorf("invalid CAMT.053: no statement found")
+	}
+
+	var transactions []domain.StatementTransaction
+	for _, statement := range document.Statements {
+		account := strings.Join(strings.Fields(statement.IBAN), "")
+		if account == "" {
+			account = strings.TrimSpace(statement.OtherId)
+		}
+
+		for i, entry := range statement.Entries {
+			status := strings.ToUpper(strings.TrimSpace(entry.Status.Code + entry.Status.Text))
+			if status != "" && status != "BOOK" {
+				continue
+			}
+
+			amount, err := strconv.ParseFloat(strings.TrimSpace(entry.Amount.Value), 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid CAMT.053: entry %d has invalid Amt %q", i+1, entry.Amount.Value)
+			}
+
+			date := entry.BookingDate
+			if date.Date == "" && date.DateTime == "" {
+				date = entry.ValueDate
+			}
+			posted, err := date.parse()
+			if err != nil {
+				return nil, fmt.Errorf("invalid CAMT.053: entry %d: %w", i+1, err)
+			}
+
+			transactions = append(transactions, domain.StatementTransaction{
+				BankId:      entry.externalId(),
+				Account:     account,
+				PostedAt:    posted,
+				Amount:      amount,
+				Debit:       strings.EqualFold(strings.TrimSpace(entry.CreditDebit), "DBIT"),
+				Currency:    strings.ToUpper(entry.Amount.Currency),
+				Description: entry.description(),
+			})
+		}
+	}
+
+	return transactions, nil
+}
+
+func (date camtDate) parse() (time.Time, error) {
+	if date.DateTime != "" {
+		for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05"} {
+			if t, err := time.Parse(layout, strings.TrimSpace(date.DateTime)); err == nil {
+				return t.UTC(), nil
+			}
+		}
+		return time.Time{}, fmt.Errorf("invalid DtTm %q", date.DateTime)
+	}
+
+	t, err := time.Parse("2006-01-02", strings.TrimSpace(date.Date))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid booking date %q", date.Date)
+	}
+	return t, nil
+}
+
+func (entry camtEntry) externalId() string {
+	candidates := []string{entry.ServicerRef}
+	if len(entry.Details) == 1 {
+		candidates = append(candidates, entry.Details[0].ServicerRef, entry.Details[0].EndToEndId)
+	}
+	candidates = append(candidates, entry.EntryRef)
+
+	for _, c := range candidates {
+		c = strings.TrimSpace(c)
+		if c != "" && !strings.EqualFold(c, "NOTPROVIDED") {
+			return c
+		}
+	}
+	return ""
+}
+
+func (entry camtEntry) description() string {
+	var parts []string
+	for _, details := range entry.Details {
+		if name := strings.TrimSpace(details.Creditor + details.CreditorParty); name != "" {
+			parts = append(parts, name)
+		}
+		for _, line := range details.Unstructured {
+			if line = strings.TrimSpace(line); line != "" {
+				parts = append(parts, line)
+			}
+		}
+		if len(parts) == 0 && details.AdditionalInfo != "" {
+			parts = append(parts, strings.TrimSpace(details.AdditionalInfo))
+		}
+	}
+	if len(parts) == 0 {
+		parts = append(parts, strings.TrimSpace(entry.AdditionalInfo))
+	}
+
+	return strings.Join(strings.Fields(strings.Join(parts, " ")), " ")
+}
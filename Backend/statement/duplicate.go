@@ -0,0 +1,80 @@
+package statement
+
+import (
+	"math"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
+)
+
+// DuplicateWindow is how far apart the bank date and the date a user typed
+// in may be for the two to still be the same purchase.
+const DuplicateWindow = 3 * 24 * time.Hour
+
+// FindDuplicate returns the manually entered expense that most likely records
+// the same purchase as t, with a score between 0 and 1, or nil. Amounts must
+// match; closeness in time and description similarity make up the score.
+func FindDuplicate(t domain.StatementTransaction, candidates []domain.Expense) (*domain.Expense, float64) {
+	var (
+		best      *domain.Expense
+		bestScore float64
+	)
+
+	for i := range candidates {
+		candidate := &candidates[i]
+		if math.Abs(candidate.Amount-t.Amount) >= 0.005 {
+			continue
+		}
+
+		gap := t.PostedAt.Sub(candidate.CreatedAt)
+		if gap < 0 {
+			gap = -gap
+		}
+		if gap > DuplicateWindow {
+			continue
+		}
+
+		dateScore := 1 - float64(gap)/float64(DuplicateWindow)
+		score := 0.5*dateScore + 0.5*DescriptionSimilarity(t.Description, candidate.Description)
+		if score >= 0.5 && score > bestScore {
+			best, bestScore = candidate, score
+		}
+	}
+
+	return best, math.Round(bestScore*100) / 100
+}
+
+// DescriptionSimilarity is the Dice coefficient of the character trigrams of
+// both descriptions, which tolerates the truncation and merchant codes banks
+// add ("GOJEK*GOFOOD 0812" vs "gofood dinner").
+func DescriptionSimilarity(a, b string) float64 {
+	ta, tb := trigrams(a), trigrams(b)
+	if len(ta) == 0 || len(tb) == 0 {
+		return 0
+	}
+
+	shared := 0
+	for g := range ta {
+		if tb[g] {
+			shared++
+		}
+	}
+	return 2 * float64(shared) / float64(len(ta)+len(tb))
+}
+
+func trigrams(s string) map[string]bool {
+	words := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+
+	grams := map[string]bool{}
+	for _, w := range words {
+		runes := []rune(" " + w + " ")
+		for i := 0; i+3 <= len(runes); i++ {
+			grams[string(runes[i:i+3])] = true
+		}
+	}
+	return grams
+}
@@ -0,0 +1,150 @@
+package statement
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
+)
+
+var (
+	ofxTransactionPattern = regexp.MustCompile(`(?is)<STMTTRN>(.*?)</STMTTRN>`)
+	ofxFieldPattern       = regexp.MustCompile(`(?i)<([A-Z0-9.]+)>([^<\r\n]*)`)
+	ofxCurrencyPattern    = regexp.MustCompile(`(?i)<CURDEF>([^<\r\n]*)`)
+	ofxAccountPattern     = regexp.MustCompile(`(?is)<(?:BANK|CC)ACCTFROM>(.*?)</(?:BANK|CC)ACCTFROM>`)
+	ofxOffsetPattern      = regexp.MustCompile(`\[([+-]?\d+(?:\.\d+)?)(?::[^\]]*)?\]`)
+)
+
+// ParseOFX reads OFX 1.x (SGML, unclosed tags) as well as OFX 2.x (XML)
+// and QFX files, which are OFX with extra Quicken headers.
+func ParseOFX(data []byte) ([]domain.StatementTransaction, error) {
+	content := string(data)
+
+	blocks := ofxTransactionPattern.FindAllStringSubmatchIndex(content, -1)
+	if blocks == nil && !strings.Contains(strings.ToUpper(content), "<BANKTRANLIST>") {
+		return nil, fmt.Errorf("invalid OFX: no transaction list found")
+	}
+
+	// A file can hold statements of several accounts, each with its own
+	// currency. The CURDEF tags and account aggregates are found once and
+	// walked alongside the transactions, each of which takes the last ones
+	// before it.
+	currencies := newOFXTag(ofxCurrencyPattern, content)
+	accounts := newOFXTag(ofxAccountPattern, content)
+
+	var transactions []domain.StatementTransaction
+	previousEnd := 0
+	for i, block := range blocks {
+		currency := strings.ToUpper(currencies.advance(content, previousEnd, block[0]))
+		account := ofxAccount(accounts.advance(content, previousEnd, block[0]))
+		previousEnd = block[1]
+
+		fields := map[string]string{}
+		for _, f := range ofxFieldPattern.FindAllStringSubmatch(content[block[2]:block[3]], -1) {
+			fields[strings.ToUpper(f[1])] = decodeOFXText(strings.TrimSpace(f[2]))
+		}
+
+		amount, err := strconv.ParseFloat(strings.Replace(fields["TRNAMT"], ",", ".", 1), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid OFX: transaction %d has invalid TRNAMT %q", i+1, fields["TRNAMT"])
+		}
+
+		posted, err := parseOFXDate(fields["DTPOSTED"])
+		if err != nil {
+			return nil, fmt.Errorf("invalid OFX: transaction %d: %w", i+1, err)
+		}
+
+		description := fields["NAME"]
+		if memo := fields["MEMO"]; memo != "" && !strings.EqualFold(memo, description) {
+			description = strings.TrimSpace(description + " " + memo)
+		}
+
+		transactions = append(transactions, domain.StatementTransaction{
+			BankId:      fields["FITID"],
+			Account:     account,
+			PostedAt:    posted,
+			Amount:      math.Abs(amount),
+			Debit:       amount < 0,
+			Currency:    currency,
+			Description: description,
+		})
+	}
+
+	return transactions, nil
+}
+
+// ofxTag walks the occurrences of a statement level tag in file order.
+type ofxTag struct {
+	matches [][]int
+	next    int
+	value   string
+}
+
+func newOFXTag(pattern *regexp.Regexp, content string) *ofxTag {
+	return &ofxTag{matches: pattern.FindAllStringSubmatchIndex(content, -1)}
+}
+
+// advance moves past the occurrences before to and returns the last value
+// seen. Occurrences before from are inside a transaction and skipped.
+func (tag *ofxTag) advance(content string, from, to int) string {
+	for tag.next < len(tag.matches) && tag.matches[tag.next][0] < to {
+		if m := tag.matches[tag.next]; m[0] >= from {
+			tag.value = strings.TrimSpace(content[m[2]:m[3]])
+		}
+		tag.next++
+	}
+	return tag.value
+}
+
+// ofxAccount names the account of a BANKACCTFROM or CCACCTFROM aggregate as
+// BANKID:ACCTID, or ACCTID for credit cards, which have no BANKID.
+func ofxAccount(aggregate string) string {
+	fields := map[string]string{}
+	for _, f := range ofxFieldPattern.FindAllStringSubmatch(aggregate, -1) {
+		fields[strings.ToUpper(f[1])] = strings.TrimSpace(f[2])
+	}
+
+	accountId, bankId := fields["ACCTID"], fields["BANKID"]
+	if accountId == "" || bankId == "" {
+		return accountId
+	}
+	return bankId + ":" + accountId
+}
+
+// parseOFXDate understands YYYYMMDD[HHMMSS[.XXX]][[offset:TZ]]. Dates without
+// an offset are taken as UTC.
+func parseOFXDate(value string) (time.Time, error) {
+	loc := time.UTC
+	if m := ofxOffsetPattern.FindStringSubmatch(value); m != nil {
+		hours, err := strconv.ParseFloat(m[1], 64)
+		if err == nil {
+			loc = time.FixedZone("", int(hours*3600))
+		}
+		value = value[:strings.Index(value, "[")]
+	}
+	if dot := strings.Index(value, "."); dot >= 0 {
+		value = value[:dot]
+	}
+
+	layouts := map[int]string{8: "20060102", 12: "200601021504", 14: "20060102150405"}
+	layout, ok := layouts[len(value)]
+	if !ok {
+		return time.Time{}, fmt.Errorf("invalid DTPOSTED %q", value)
+	}
+
+	t, err := time.ParseInLocation(layout, value, loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid DTPOSTED %q", value)
+	}
+	return t.UTC(), nil
+}
+
+var ofxEntities = strings.NewReplacer("&amp;", "&", "&lt;", "<", "&gt;", ">", "&quot;", `"`, "&apos;", "'", "&nbsp;", " ")
+
+func decodeOFXText(value string) string {
+	return ofxEntities.Replace(value)
+}
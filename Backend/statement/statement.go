@@ -0,0 +1,109 @@
+// Package statement reads bank statement exports (OFX/QFX and ISO 20022
+// CAMT.053) into transactions that can be imported as expenses.
+package statement
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
+)
+
+const (
+	FormatOFX     = "ofx"
+	FormatCAMT053 = "camt053"
+)
+
+var ErrUnknownFormat = errors.New("unrecognised statement format, expected OFX/QFX or CAMT.053")
+
+// Detect guesses the format from the content of the file.
+func Detect(data []byte) string {
+	head := data
+	if len(head) > 4096 {
+		head = head[:4096]
+	}
+	upper := bytes.ToUpper(head)
+
+	switch {
+	case bytes.Contains(upper, []byte("OFXHEADER")) || bytes.Contains(upper, []byte("<OFX>")):
+		return FormatOFX
+	case bytes.Contains(head, []byte("camt.053")) || bytes.Contains(head, []byte("BkToCstmrStmt")):
+		return FormatCAMT053
+	}
+	return ""
+}
+
+// Parse reads every transaction of the statement, credits included. format
+// may be empty to detect it from the content.
+func Parse(format string, data []byte) (string, []domain.StatementTransaction, error) {
+	if format == "" || format == "qfx" {
+		if detected := Detect(data); detected != "" {
+			format = detected
+		}
+	}
+
+	var (
+		transactions []domain.StatementTransaction
+		err          error
+	)
+	switch format {
+	case FormatOFX:
+		transactions, err = ParseOFX(data)
+	case FormatCAMT053:
+		transactions, err = ParseCAMT053(data)
+	default:
+		return "", nil, ErrUnknownFormat
+	}
+	if err != nil {
+		return format, nil, err
+	}
+
+	occurrences := map[string]int{}
+	for i := range transactions {
+		t := &transactions[i]
+		if t.BankId == "" {
+			key := fallbackKey(*t)
+			t.BankId = fallbackId(key, occurrences[t.Account+"|"+key])
+			occurrences[t.Account+"|"+key]++
+		}
+		t.ExternalId = scopedId(t.Account, t.BankId)
+	}
+
+	return format, transactions, nil
+}
+
+// maxExternalIdLength is the size of expense_imports.external_id.
+const maxExternalIdLength = 191
+
+func fallbackKey(t domain.StatementTransaction) string {
+	return fmt.Sprintf("%s|%.2f|%t|%s", t.PostedAt.UTC().Format("2006-01-02"), t.Amount, t.Debit, strings.ToLower(t.Description))
+}
+
+// fallbackId derives a stable id for banks that leave the transaction id
+// out, so importing the same file twice still does not double up. Two equal
+// purchases on the same day are told apart by their order in the statement.
+func fallbackId(key string, occurrence int) string {
+	if occurrence > 0 {
+		key += fmt.Sprintf("|%d", occurrence)
+	}
+	sum := sha1.Sum([]byte(key))
+	return "sha1:" + hex.EncodeToString(sum[:])
+}
+
+// scopedId prefixes a bank's transaction id with the account, as banks only
+// keep them unique per account. Ids too long to store are hashed.
+func scopedId(account, bankId string) string {
+	id := bankId
+	if account != "" {
+		id = account + "/" + bankId
+	}
+	if len(id) > maxExternalIdLength {
+		sum := sha1.Sum([]byte(id))
+		id = "sha1:" + hex.EncodeToString(sum[:])
+	}
+	return id
+}
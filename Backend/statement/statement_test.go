@@ -0,0 +1,303 @@
+package statement_test
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ryhnfhrza/Expense-Tracker/model/domain"
+	"github.com/ryhnfhrza/Expense-Tracker/statement"
+)
+
+const sgmlOFX = `OFXHEADER:100
+DATA:OFXSGML
+VERSION:102
+
+<OFX>
+<BANKMSGSRSV1><STMTTRNRS><STMTRS>
+<CURDEF>usd
+<BANKACCTFROM><BANKID>121000248<ACCTID>1234567890<ACCTTYPE>CHECKING</BANKACCTFROM>
+<BANKTRANLIST>
+<DTSTART>20240101<DTEND>20240131
+<STMTTRN>
+<TRNTYPE>DEBIT
+<DTPOSTED>20240105120000.000[-5:EST]
+<TRNAMT>-12.50
+<FITID>T1
+<NAME>Coffee &amp; Co
+<MEMO>Morning
+</STMTTRN>
+<STMTTRN>
+<TRNTYPE>CREDIT
+<DTPOSTED>20240106
+<TRNAMT>1000,00
+<FITID>T2
+<NAME>Salary
+</STMTTRN>
+</BANKTRANLIST>
+</STMTRS></STMTTRNRS></BANKMSGSRSV1>
+</OFX>`
+
+const xmlOFX = `<?xml version="1.0" encoding="UTF-8"?>
+<?OFX OFXHEADER="200" VERSION="220"?>
+<OFX>
+  <BANKMSGSRSV1><STMTTRNRS><STMTRS>
+    <CURDEF>EUR</CURDEF>
+    <BANKACCTFROM><BANKID>BANK1</BANKID><ACCTID>ACC1</ACCTID></BANKACCTFROM>
+    <BANKTRANLIST>
+      <STMTTRN>
+        <TRNTYPE>DEBIT</TRNTYPE>
+        <DTPOSTED>20240210</DTPOSTED>
+        <TRNAMT>-3.20</TRNAMT>
+        <FITID>X1</FITID>
+        <NAME>Bakery</NAME>
+        <MEMO>bakery</MEMO>
+      </STMTTRN>
+    </BANKTRANLIST>
+  </STMTRS></STMTTRNRS></BANKMSGSRSV1>
+</OFX>`
+
+// Two statements in one file: a checking account in IDR and a credit card
+// in USD. The transfer's BANKACCTTO must not be taken for the account.
+const multiAccountOFX = `OFXHEADER:100
+<OFX>
+<STMTRS><CURDEF>IDR
+<BANKACCTFROM><BANKID>B1<ACCTID>CHK</BANKACCTFROM>
+<BANKTRANLIST>
+<STMTTRN><TRNAMT>-50000<DTPOSTED>20240301<FITID>1<NAME>Transfer
+<BANKACCTTO><BANKID>B9<ACCTID>OTHER</BANKACCTTO></STMTTRN>
+<STMTTRN><TRNAMT>-20000<DTPOSTED>20240302<FITID>2<NAME>Groceries</STMTTRN>
+</BANKTRANLIST></STMTRS>
+<CCSTMTRS><CURDEF>USD
+<CCACCTFROM><ACCTID>CARD</CCACCTFROM>
+<BANKTRANLIST>
+<STMTTRN><TRNAMT>-9.99<DTPOSTED>20240303<FITID>1<NAME>Streaming</STMTTRN>
+</BANKTRANLIST></CCSTMTRS>
+</OFX>`
+
+const camt053 = `<?xml version="1.0" encoding="UTF-8"?>
+<Document xmlns="urn:iso:std:iso:20022:tech:xsd:camt.053.001.02">
+<BkToCstmrStmt>
+  <Stmt>
+    <Acct><Id><IBAN>DE89 3704 0044 0532 0130 00</IBAN></Id></Acct>
+    <Ntry>
+      <Amt Ccy="eur">42.00</Amt>
+      <CdtDbtInd>DBIT</CdtDbtInd>
+      <Sts>BOOK</Sts>
+      <BookgDt><Dt>2024-04-01</Dt></BookgDt>
+      <AcctSvcrRef>REF-1</AcctSvcrRef>
+      <NtryDtls><TxDtls>
+        <RltdPties><Cdtr><Nm>Power Company</Nm></Cdtr></RltdPties>
+        <RmtInf><Ustrd>Invoice 77</Ustrd></RmtInf>
+      </TxDtls></NtryDtls>
+    </Ntry>
+    <Ntry>
+      <Amt Ccy="EUR">5.00</Amt>
+      <CdtDbtInd>DBIT</CdtDbtInd>
+      <Sts><Cd>PDNG</Cd></Sts>
+      <BookgDt><Dt>2024-04-02</Dt></BookgDt>
+      <AcctSvcrRef>REF-2</AcctSvcrRef>
+    </Ntry>
+  </Stmt>
+</BkToCstmrStmt>
+</Document>`
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		data   string
+		want   []domain.StatementTransaction
+	}{
+		{
+			name: "SGML OFX",
+			data: sgmlOFX,
+			want: []domain.StatementTransaction{
+				{ExternalId: "121000248:1234567890/T1", BankId: "T1", Account: "121000248:1234567890", PostedAt: time.Date(2024, 1, 5, 17, 0, 0, 0, time.UTC), Amount: 12.5, Debit: true, Currency: "USD", Description: "Coffee & Co Morning"},
+				{ExternalId: "121000248:1234567890/T2", BankId: "T2", Account: "121000248:1234567890", PostedAt: time.Date(2024, 1, 6, 0, 0, 0, 0, time.UTC), Amount: 1000, Currency: "USD", Description: "Salary"},
+			},
+		},
+		{
+			name: "XML OFX",
+			data: xmlOFX,
+			want: []domain.StatementTransaction{
+				{ExternalId: "BANK1:ACC1/X1", BankId: "X1", Account: "BANK1:ACC1", PostedAt: time.Date(2024, 2, 10, 0, 0, 0, 0, time.UTC), Amount: 3.2, Debit: true, Currency: "EUR", Description: "Bakery"},
+			},
+		},
+		{
+			name: "several accounts in one OFX file",
+			data: multiAccountOFX,
+			want: []domain.StatementTransaction{
+				{ExternalId: "B1:CHK/1", BankId: "1", Account: "B1:CHK", PostedAt: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), Amount: 50000, Debit: true, Currency: "IDR", Description: "Transfer"},
+				{ExternalId: "B1:CHK/2", BankId: "2", Account: "B1:CHK", PostedAt: time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC), Amount: 20000, Debit: true, Currency: "IDR", Description: "Groceries"},
+				{ExternalId: "CARD/1", BankId: "1", Account: "CARD", PostedAt: time.Date(2024, 3, 3, 0, 0, 0, 0, time.UTC), Amount: 9.99, Debit: true, Currency: "USD", Description: "Streaming"},
+			},
+		},
+		{
+			name: "CAMT.053",
+			data: camt053,
+			want: []domain.StatementTransaction{
+				{ExternalId: "DE89370400440532013000/REF-1", BankId: "REF-1", Account: "DE89370400440532013000", PostedAt: time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC), Amount: 42, Debit: true, Currency: "EUR", Description: "Power Company Invoice 77"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, got, err := statement.Parse(tt.format, []byte(tt.data))
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d transactions, want %d: %+v", len(got), len(tt.want), got)
+			}
+			for i := range tt.want {
+				if !got[i].PostedAt.Equal(tt.want[i].PostedAt) {
+					t.Errorf("transaction %d: PostedAt = %v, want %v", i, got[i].PostedAt, tt.want[i].PostedAt)
+				}
+				got[i].PostedAt = tt.want[i].PostedAt
+				if got[i] != tt.want[i] {
+					t.Errorf("transaction %d:\n got %+v\nwant %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParseDetectsFormat(t *testing.T) {
+	tests := []struct {
+		data string
+		want string
+	}{
+		{sgmlOFX, statement.FormatOFX},
+		{xmlOFX, statement.FormatOFX},
+		{camt053, statement.FormatCAMT053},
+		{"date,amount\n2024-01-01,5", ""},
+	}
+
+	for _, tt := range tests {
+		if got := statement.Detect([]byte(tt.data)); got != tt.want {
+			t.Errorf("Detect(%.30q) = %q, want %q", tt.data, got, tt.want)
+		}
+	}
+
+	if _, _, err := statement.Parse("", []byte("date,amount\n")); err != statement.ErrUnknownFormat {
+		t.Errorf("Parse of CSV: err = %v, want ErrUnknownFormat", err)
+	}
+}
+
+// legacyFallbackId is the id imports stored before ids were scoped to the
+// account; a statement without an account must still produce it.
+func legacyFallbackId(key string) string {
+	sum := sha1.Sum([]byte(key))
+	return "sha1:" + hex.EncodeToString(sum[:])
+}
+
+func TestParseFallbackIds(t *testing.T) {
+	withoutIds := func(account string) string {
+		header := "OFXHEADER:100\n<OFX><STMTRS><CURDEF>USD\n"
+		if account != "" {
+			header += "<BANKACCTFROM><BANKID>B1<ACCTID>" + account + "</BANKACCTFROM>\n"
+		}
+		return header + `<BANKTRANLIST>
+<STMTTRN><TRNAMT>-4.50<DTPOSTED>20240501<NAME>Coffee</STMTTRN>
+<STMTTRN><TRNAMT>-4.50<DTPOSTED>20240501<NAME>Coffee</STMTTRN>
+<STMTTRN><TRNAMT>-4.50<DTPOSTED>20240502<NAME>Coffee</STMTTRN>
+</BANKTRANLIST></STMTRS></OFX>`
+	}
+
+	first := legacyFallbackId("2024-05-01|4.50|true|coffee")
+	second := legacyFallbackId("2024-05-01|4.50|true|coffee|1")
+	nextDay := legacyFallbackId("2024-05-02|4.50|true|coffee")
+
+	tests := []struct {
+		name    string
+		account string
+		want    []string
+	}{
+		{
+			name: "legacy bare ids without an account",
+			want: []string{first, second, nextDay},
+		},
+		{
+			name:    "identical same-day purchases scoped to the account",
+			account: "CHK",
+			want:    []string{"B1:CHK/" + first, "B1:CHK/" + second, "B1:CHK/" + nextDay},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, got, err := statement.Parse(statement.FormatOFX, []byte(withoutIds(tt.account)))
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d transactions, want %d", len(got), len(tt.want))
+			}
+			for i, want := range tt.want {
+				if got[i].ExternalId != want {
+					t.Errorf("transaction %d: ExternalId = %q, want %q", i, got[i].ExternalId, want)
+				}
+			}
+		})
+	}
+}
+
+func TestParseBareFITIDWithoutAccount(t *testing.T) {
+	data := "OFXHEADER:100\n<OFX><BANKTRANLIST><STMTTRN><TRNAMT>-1<DTPOSTED>20240101<FITID>ABC<NAME>x</STMTTRN></BANKTRANLIST></OFX>"
+
+	_, got, err := statement.Parse(statement.FormatOFX, []byte(data))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(got) != 1 || got[0].ExternalId != "ABC" || got[0].BankId != "ABC" {
+		t.Fatalf("got %+v, want the bare FITID ABC as external id", got)
+	}
+}
+
+func TestParseLongIdsAreHashed(t *testing.T) {
+	fitId := strings.Repeat("9", 200)
+	data := "OFXHEADER:100\n<OFX><BANKACCTFROM><BANKID>B<ACCTID>A</BANKACCTFROM><BANKTRANLIST><STMTTRN><TRNAMT>-1<DTPOSTED>20240101<FITID>" + fitId + "<NAME>x</STMTTRN></BANKTRANLIST></OFX>"
+
+	_, got, err := statement.Parse(statement.FormatOFX, []byte(data))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if want := legacyFallbackId("B:A/" + fitId); got[0].ExternalId != want {
+		t.Errorf("ExternalId = %q, want %q", got[0].ExternalId, want)
+	}
+}
+
+func TestParseRejectsMalformedFiles(t *testing.T) {
+	transaction := func(fields string) string {
+		return "OFXHEADER:100\n<OFX><BANKTRANLIST><STMTTRN>" + fields + "</STMTTRN></BANKTRANLIST></OFX>"
+	}
+
+	tests := []struct {
+		name   string
+		format string
+		data   string
+		want   string
+	}{
+		{"OFX without transaction list", statement.FormatOFX, "OFXHEADER:100\n<OFX></OFX>", "no transaction list"},
+		{"missing TRNAMT", statement.FormatOFX, transaction("<DTPOSTED>20240101<FITID>1"), "invalid TRNAMT"},
+		{"non-numeric TRNAMT", statement.FormatOFX, transaction("<TRNAMT>12,50,00<DTPOSTED>20240101<FITID>1"), "invalid TRNAMT"},
+		{"short DTPOSTED", statement.FormatOFX, transaction("<TRNAMT>-1<DTPOSTED>202401<FITID>1"), "invalid DTPOSTED"},
+		{"impossible DTPOSTED", statement.FormatOFX, transaction("<TRNAMT>-1<DTPOSTED>20241340<FITID>1"), "invalid DTPOSTED"},
+		{"CAMT without statement", statement.FormatCAMT053, `<Document><BkToCstmrStmt></BkToCstmrStmt></Document>`, "no statement"},
+		{"CAMT with invalid Amt", statement.FormatCAMT053, `<Document><BkToCstmrStmt><Stmt><Ntry><Amt Ccy="EUR">x</Amt><BookgDt><Dt>2024-01-01</Dt></BookgDt></Ntry></Stmt></BkToCstmrStmt></Document>`, "invalid Amt"},
+		{"CAMT with invalid date", statement.FormatCAMT053, `<Document><BkToCstmrStmt><Stmt><Ntry><Amt Ccy="EUR">1</Amt><BookgDt><Dt>01.01.2024</Dt></BookgDt></Ntry></Stmt></BkToCstmrStmt></Document>`, "invalid booking date"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := statement.Parse(tt.format, []byte(tt.data))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("Parse error = %v, want one mentioning %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package takeout
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxFileSize bounds each file unpacked from an uploaded archive, so a
+// small ZIP cannot expand without limit.
+const maxFileSize = 64 << 20
+
+// Read loads the parts of an archive a restore needs: the manifest, the
+// categories and the expenses. It checks that every expense points at a
+// category in the archive.
+func Read(data []byte) (*Archive, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, errors.New("the file is not a ZIP archive")
+	}
+
+	files := make(map[string]*zip.File, len(zr.File))
+	for _, file := range zr.File {
+		files[file.Name] = file
+	}
+
+	archive := &Archive{}
+	if err := readJSON(files, ManifestFile, &archive.Manifest); err != nil {
+		return nil, err
+	}
+	if archive.Manifest.Format != Format {
+		return nil, errors.New("the archive is not an expense tracker export")
+	}
+	if archive.Manifest.Version < 1 || archive.Manifest.Version > Version {
+		return nil, fmt.Errorf("archive version %d is not supported", archive.Manifest.Version)
+	}
+
+	if err := readJSON(files, CategoriesFile, &archive.Categories); err != nil {
+		return nil, err
+	}
+	if err := readJSON(files, ExpensesFile, &archive.Expenses); err != nil {
+		return nil, err
+	}
+
+	if err := check(archive); err != nil {
+		return nil, err
+	}
+
+	return archive, nil
+}
+
+func readJSON(files map[string]*zip.File, name string, value any) error {
+	file, ok := files[name]
+	if !ok {
+		return fmt.Errorf("%s is missing from the archive", name)
+	}
+
+	r, err := file.Open()
+	if err != nil {
+		return fmt.Errorf("%s cannot be opened: %w", name, err)
+	}
+	defer r.Close()
+
+	content, err := io.ReadAll(io.LimitReader(r, maxFileSize+1))
+	if err != nil {
+		return fmt.Errorf("%s cannot be read: %w", name, err)
+	}
+	if len(content) > maxFileSize {
+		return fmt.Errorf("%s is larger than %d MB", name, maxFileSize>>20)
+	}
+
+	if err := json.Unmarshal(content, value); err != nil {
+		return fmt.Errorf("%s is not valid: %w", name, err)
+	}
+
+	return nil
+}
+
+// check applies the limits the database has, so a bad archive is rejected
+// before anything is written.
+func check(archive *Archive) error {
+	categoryIds := make(map[int]bool, len(archive.Categories))
+	for _, category := range archive.Categories {
+		name := strings.TrimSpace(category.Name)
+		if name == "" || utf8.RuneCountInString(name) > 100 {
+			return fmt.Errorf("category %d needs a name of 1 to 100 characters", category.Id)
+		}
+		if categoryIds[category.Id] {
+			return fmt.Errorf("category %d appears twice", category.Id)
+		}
+		categoryIds[category.Id] = true
+	}
+
+	for _, expense := range archive.Expenses {
+		if !categoryIds[expense.CategoryId] {
+			return fmt.Errorf("expense %d points at category %d, which is not in the archive", expense.Id, expense.CategoryId)
+		}
+		if utf8.RuneCountInString(expense.Description) > 255 {
+			return fmt.Errorf("expense %d has a description longer than 255 characters", expense.Id)
+		}
+		if expense.Amount == 0 {
+			return fmt.Errorf("expense %d has no amount", expense.Id)
+		}
+		if expense.CreatedAt.IsZero() {
+			return fmt.Errorf("expense %d has no date", expense.Id)
+		}
+	}
+
+	return nil
+}
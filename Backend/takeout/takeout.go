@@ -0,0 +1,83 @@
+// Package takeout writes and reads the archive a user downloads with all
+// the app holds about them. Every kind of record is a JSON file; categories
+// and expenses come as CSV as well.
+package takeout
+
+import (
+	"time"
+
+	"github.com/ryhnfhrza/Expense-Tracker/model/web"
+)
+
+const (
+	Format  = "expense-tracker-takeout"
+	Version = 1
+)
+
+// Names of the files in the archive.
+const (
+	ManifestFile          = "manifest.json"
+	ProfileFile           = "profile.json"
+	CategoriesFile        = "categories.json"
+	CategoriesCSVFile     = "categories.csv"
+	ExpensesFile          = "expenses.json"
+	ExpensesCSVFile       = "expenses.csv"
+	RecurringExpensesFile = "recurring_expenses.json"
+	CategoryRulesFile     = "category_rules.json"
+	SavedViewsFile        = "saved_views.json"
+	WebhooksFile          = "webhooks.json"
+	AccessTokensFile      = "access_tokens.json"
+	AuditLogFile          = "audit_log.json"
+)
+
+type Manifest struct {
+	Format     string    `json:"format"`
+	Version    int       `json:"version"`
+	ExportedAt time.Time `json:"exported_at"`
+	UserId     int       `json:"user_id"`
+}
+
+type Profile struct {
+	web.UserProfileResponse
+	Identities []Identity `json:"identities"`
+}
+
+// Identity is an account at a single sign-on provider linked to the user.
+type Identity struct {
+	Issuer    string    `json:"issuer"`
+	Subject   string    `json:"subject"`
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Category and Expense include the ones in the trash, with DeletedAt set.
+type Category struct {
+	Id        int        `json:"id"`
+	Name      string     `json:"name"`
+	DeletedAt *time.Time `json:"deleted_at"`
+}
+
+type Expense struct {
+	Id           int        `json:"id"`
+	CategoryId   int        `json:"category_id"`
+	CategoryName string     `json:"category_name"`
+	Description  string     `json:"description"`
+	Amount       float64    `json:"amount"`
+	CreatedAt    time.Time  `json:"created_at"`
+	DeletedAt    *time.Time `json:"deleted_at"`
+}
+
+// Archive is the content of a takeout. Webhook secrets and token hashes
+// are left out: they are credentials, not data about the user.
+type Archive struct {
+	Manifest          Manifest
+	Profile           Profile
+	Categories        []Category
+	Expenses          []Expense
+	RecurringExpenses []web.RecurringExpenseResponse
+	CategoryRules     []web.CategoryRuleResponse
+	SavedViews        []web.SavedViewResponse
+	Webhooks          []web.WebhookResponse
+	AccessTokens      []web.AccessTokenResponse
+	AuditLog          []web.AuditEntryResponse
+}
@@ -0,0 +1,142 @@
+package takeout
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Write stores the archive as a ZIP file in w.
+func Write(w io.Writer, archive *Archive) error {
+	zw := zip.NewWriter(w)
+	modified := archive.Manifest.ExportedAt
+
+	files := []struct {
+		name  string
+		value any
+	}{
+		{ManifestFile, archive.Manifest},
+		{ProfileFile, archive.Profile},
+		{CategoriesFile, nonNil(archive.Categories)},
+		{ExpensesFile, nonNil(archive.Expenses)},
+		{RecurringExpensesFile, nonNil(archive.RecurringExpenses)},
+		{CategoryRulesFile, nonNil(archive.CategoryRules)},
+		{SavedViewsFile, nonNil(archive.SavedViews)},
+		{WebhooksFile, nonNil(archive.Webhooks)},
+		{AccessTokensFile, nonNil(archive.AccessTokens)},
+		{AuditLogFile, nonNil(archive.AuditLog)},
+	}
+	for _, file := range files {
+		if err := writeJSON(zw, file.name, modified, file.value); err != nil {
+			return err
+		}
+	}
+
+	categories := [][]string{{"id", "name", "deleted_at"}}
+	for _, category := range archive.Categories {
+		categories = append(categories, []string{
+			strconv.Itoa(category.Id),
+			csvText(category.Name),
+			csvTime(category.DeletedAt),
+		})
+	}
+	if err := writeCSV(zw, CategoriesCSVFile, modified, categories); err != nil {
+		return err
+	}
+
+	expenses := [][]string{{"id", "category_id", "category_name", "description", "amount", "created_at", "deleted_at"}}
+	for _, expense := range archive.Expenses {
+		expenses = append(expenses, []string{
+			strconv.Itoa(expense.Id),
+			strconv.Itoa(expense.CategoryId),
+			csvText(expense.CategoryName),
+			csvText(expense.Description),
+			strconv.FormatFloat(expense.Amount, 'f', -1, 64),
+			expense.CreatedAt.UTC().Format(time.RFC3339),
+			csvTime(expense.DeletedAt),
+		})
+	}
+	if err := writeCSV(zw, ExpensesCSVFile, modified, expenses); err != nil {
+		return err
+	}
+
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("failed to finish takeout archive: %w", err)
+	}
+
+	return nil
+}
+
+func create(zw *zip.Writer, name string, modified time.Time) (io.Writer, error) {
+	w, err := zw.CreateHeader(&zip.FileHeader{
+		Name:     name,
+		Method:   zip.Deflate,
+		Modified: modified,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to add %s to takeout archive: %w", name, err)
+	}
+
+	return w, nil
+}
+
+func writeJSON(zw *zip.Writer, name string, modified time.Time, value any) error {
+	w, err := create(zw, name, modified)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(value); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+
+	return nil
+}
+
+func writeCSV(zw *zip.Writer, name string, modified time.Time, records [][]string) error {
+	w, err := create(zw, name, modified)
+	if err != nil {
+		return err
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.WriteAll(records); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// csvText keeps spreadsheets from running a description as a formula. The
+// JSON files have the text unchanged.
+func csvText(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+
+	return s
+}
+
+func csvTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+
+	return t.UTC().Format(time.RFC3339)
+}
+
+// nonNil makes empty lists come out as [] instead of null.
+func nonNil[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+
+	return items
+}
@@ -2,6 +2,7 @@ package util
 
 import (
 	"crypto/rand"
+	"encoding/base64"
 	"fmt"
 	"io"
 )
@@ -16,3 +17,12 @@ func Generate6DigitCode() (string, error) {
 	code := n % 1000000
 	return fmt.Sprintf("%06d", code), nil
 }
+
+// RandomToken returns a URL-safe random string carrying n random bytes.
+func RandomToken(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := io.ReadFull(rand.Reader, buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
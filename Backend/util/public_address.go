@@ -0,0 +1,33 @@
+package util
+
+import "net/netip"
+
+// sharedAddressSpace is the carrier-grade NAT range, which is not covered
+// by netip's IsPrivate.
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
+
+// thisNetwork holds 0.0.0.0/8, which Linux routes to the local host.
+var thisNetwork = netip.MustParsePrefix("0.0.0.0/8")
+
+// IsPublicAddress reports whether addr can be reached on the internet, as
+// opposed to loopback, private, link-local, multicast or unspecified
+// addresses. IPv4 addresses mapped into IPv6 are checked as IPv4.
+func IsPublicAddress(addr netip.Addr) bool {
+	addr = addr.Unmap()
+
+	switch {
+	case !addr.IsValid(),
+		addr.IsUnspecified(),
+		addr.IsLoopback(),
+		addr.IsPrivate(),
+		addr.IsLinkLocalUnicast(),
+		addr.IsLinkLocalMulticast(),
+		addr.IsInterfaceLocalMulticast(),
+		addr.IsMulticast(),
+		sharedAddressSpace.Contains(addr),
+		thisNetwork.Contains(addr):
+		return false
+	}
+
+	return true
+}
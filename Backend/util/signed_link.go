@@ -0,0 +1,42 @@
+package util
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"time"
+)
+
+// SignLink returns the signature that lets a link to path work without a
+// token until expires. It is keyed with SIGNING_KEY.
+func SignLink(path string, expires time.Time) (string, error) {
+	signingKey, err := getSigningKey()
+	if err != nil {
+		return "", err
+	}
+
+	return linkSignature(signingKey, path, expires.Unix()), nil
+}
+
+// VerifyLink checks a signature made by SignLink for path and the expiry
+// in Unix seconds.
+func VerifyLink(path string, expires int64, signature string, now time.Time) bool {
+	if now.Unix() >= expires {
+		return false
+	}
+
+	signingKey, err := getSigningKey()
+	if err != nil {
+		return false
+	}
+
+	expected := linkSignature(signingKey, path, expires)
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
+
+func linkSignature(signingKey []byte, path string, expires int64) string {
+	mac := hmac.New(sha256.New, signingKey)
+	mac.Write([]byte(path + "\n" + strconv.FormatInt(expires, 10)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
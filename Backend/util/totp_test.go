@@ -0,0 +1,126 @@
+package util
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfc6238Secret is the SHA-1 seed of the RFC 6238 test vectors,
+// "12345678901234567890", base32 encoded.
+const rfc6238Secret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+// The RFC lists 8-digit codes; a 6-digit code is their last six digits.
+var rfc6238Vectors = []struct {
+	unix int64
+	code string
+}{
+	{59, "94287082"},
+	{1111111109, "07081804"},
+	{1111111111, "14050471"},
+	{1234567890, "89005924"},
+	{2000000000, "69279037"},
+	{20000000000, "65353130"},
+}
+
+func TestTOTPCodeMatchesRFC6238(t *testing.T) {
+	key, err := totpEncoding.DecodeString(rfc6238Secret)
+	if err != nil {
+		t.Fatalf("decode secret: %v", err)
+	}
+
+	for _, vector := range rfc6238Vectors {
+		want := vector.code[len(vector.code)-totpDigits:]
+		if got := totpCode(key, vector.unix/totpPeriod); got != want {
+			t.Errorf("totpCode at %d = %q, want %q", vector.unix, got, want)
+		}
+	}
+}
+
+func TestValidateTOTP(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	step := now.Unix() / totpPeriod
+	key, _ := totpEncoding.DecodeString(rfc6238Secret)
+
+	tests := []struct {
+		name     string
+		secret   string
+		code     string
+		wantStep int64
+		wantOK   bool
+	}{
+		{"current step", rfc6238Secret, "050471", step, true},
+		{"lowercase secret", strings.ToLower(rfc6238Secret), "050471", step, true},
+		{"one step behind", rfc6238Secret, totpCode(key, step-1), step - 1, true},
+		{"one step ahead", rfc6238Secret, totpCode(key, step+1), step + 1, true},
+		{"two steps behind", rfc6238Secret, totpCode(key, step-2), 0, false},
+		{"two steps ahead", rfc6238Secret, totpCode(key, step+2), 0, false},
+		{"wrong code", rfc6238Secret, "000000", 0, false},
+		{"8-digit code", rfc6238Secret, "14050471", 0, false},
+		{"too short", rfc6238Secret, "05047", 0, false},
+		{"invalid secret", "not base32!", "050471", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotStep, gotOK := ValidateTOTP(tt.secret, tt.code, now)
+			if gotOK != tt.wantOK || gotStep != tt.wantStep {
+				t.Errorf("ValidateTOTP(%q) = (%d, %t), want (%d, %t)", tt.code, gotStep, gotOK, tt.wantStep, tt.wantOK)
+			}
+		})
+	}
+}
+
+// TestValidateTOTPReplay follows the LastUsedStep rule callers apply: a code
+// is only accepted when it matched a step after the last one used.
+func TestValidateTOTPReplay(t *testing.T) {
+	key, _ := totpEncoding.DecodeString(rfc6238Secret)
+	start := time.Unix(1111111111, 0)
+	step := start.Unix() / totpPeriod
+
+	var lastUsedStep int64
+	accept := func(code string, now time.Time) bool {
+		matched, ok := ValidateTOTP(rfc6238Secret, code, now)
+		if !ok || matched <= lastUsedStep {
+			return false
+		}
+		lastUsedStep = matched
+		return true
+	}
+
+	current := totpCode(key, step)
+	if !accept(current, start) {
+		t.Fatal("current code refused")
+	}
+	if accept(current, start) {
+		t.Error("the same code was accepted twice")
+	}
+	if accept(current, start.Add(totpPeriod*time.Second)) {
+		t.Error("the used code was accepted again one step later, within the skew")
+	}
+	if accept(totpCode(key, step-1), start) {
+		t.Error("a code older than the used one was accepted")
+	}
+	if !accept(totpCode(key, step+1), start.Add(totpPeriod*time.Second)) {
+		t.Error("the next step's code was refused")
+	}
+}
+
+func TestTOTPProvisioningURI(t *testing.T) {
+	got := TOTPProvisioningURI("Expense Tracker", "jane doe", "ABC")
+	want := "otpauth://totp/Expense%20Tracker:jane%20doe?secret=ABC&issuer=Expense%20Tracker"
+	if got != want {
+		t.Errorf("TOTPProvisioningURI = %q, want %q", got, want)
+	}
+}
+
+func TestGenerateTOTPSecret(t *testing.T) {
+	secret, err := GenerateTOTPSecret()
+	if err != nil {
+		t.Fatalf("GenerateTOTPSecret: %v", err)
+	}
+	key, err := totpEncoding.DecodeString(secret)
+	if err != nil || len(key) != 20 {
+		t.Fatalf("secret %q decodes to %d bytes (err %v), want 20", secret, len(key), err)
+	}
+}
@@ -20,6 +20,22 @@ function toQuery(params = {}) {
   return s ? `?${s}` : "";
 }
 
+// Sends a POST with an Idempotency-Key and retries it once with the same
+// key if the connection drops, so a request that did reach the server is
+// not applied twice.
+async function postIdempotent(url, token, body) {
+  const init = {
+    method: "POST",
+    headers: buildHeaders(token, { "Idempotency-Key": crypto.randomUUID() }),
+    body: JSON.stringify(body),
+  };
+  try {
+    return await fetch(url, init);
+  } catch {
+    return fetch(url, init);
+  }
+}
+
 async function parseJson(res) {
   const text = await res.text();
   let json = null;
@@ -55,6 +71,31 @@ export const API = {
     return parseJson(res);
   },
 
+  async loginTwoFactor(body) {
+    const res = await fetch(`${BASE_URL}/login/2fa`, {
+      method: "POST",
+      headers: buildHeaders(null),
+      body: JSON.stringify(body),
+    });
+    return parseJson(res);
+  },
+
+  async ssoAuthorize() {
+    const res = await fetch(`${BASE_URL}/sso/authorize`, {
+      headers: buildHeaders(null),
+    });
+    return parseJson(res);
+  },
+
+  async ssoCallback(body) {
+    const res = await fetch(`${BASE_URL}/sso/callback`, {
+      method: "POST",
+      headers: buildHeaders(null),
+      body: JSON.stringify(body),
+    });
+    return parseJson(res);
+  },
+
   async register(body) {
     const res = await fetch(`${BASE_URL}/register`, {
       method: "POST",
@@ -107,24 +148,32 @@ export const API = {
   },
 
   async addExpense(body) {
-    const res = await fetch(`${BASE_URL}/expense`, {
-      method: "POST",
-      headers: buildHeaders(this._token),
-      body: JSON.stringify(body),
-    });
+    const res = await postIdempotent(`${BASE_URL}/expense`, this._token, body);
     return parseJson(res);
   },
 
-  async updateExpense(id, body) {
+  async quickAddExpense(body) {
+    const res = await postIdempotent(
+      `${BASE_URL}/expense/quick`,
+      this._token,
+      body
+    );
+    return parseJson(res);
+  },
+
+  // version is the one the expense was read at; without it the update
+  // overwrites whatever is stored (If-Match: *).
+  async updateExpense(id, body, version) {
+    const ifMatch = { "If-Match": version ? `"${version}"` : "*" };
     const res = await fetch(`${BASE_URL}/expense/${id}`, {
       method: "PUT",
-      headers: buildHeaders(this._token),
+      headers: buildHeaders(this._token, ifMatch),
       body: JSON.stringify(body),
     });
     if (res.status === 404) {
       const res2 = await fetch(`${BASE_URL}/expense/${id}`, {
         method: "PUT",
-        headers: buildHeaders(this._token),
+        headers: buildHeaders(this._token, ifMatch),
         body: JSON.stringify(body),
       });
       return parseJson(res2);
@@ -160,6 +209,30 @@ export const API = {
     return parseJson(res);
   },
 
+  async getViews() {
+    const res = await fetch(`${BASE_URL}/views`, {
+      headers: buildHeaders(this._token),
+    });
+    return parseJson(res);
+  },
+
+  async addView(body) {
+    const res = await fetch(`${BASE_URL}/views`, {
+      method: "POST",
+      headers: buildHeaders(this._token),
+      body: JSON.stringify(body),
+    });
+    return parseJson(res);
+  },
+
+  async deleteView(id) {
+    const res = await fetch(`${BASE_URL}/views/${id}`, {
+      method: "DELETE",
+      headers: buildHeaders(this._token),
+    });
+    return parseJson(res);
+  },
+
   async getSummaryDetails(params = {}) {
     const url = `${BASE_URL}/expenses/summary/details${toQuery(params)}`;
     const res = await fetch(url, {
@@ -167,4 +240,19 @@ export const API = {
     });
     return parseJson(res);
   },
+
+  // since is next_since from the previous call; leave it out for a snapshot.
+  async getSyncChanges(since) {
+    const res = await fetch(`${BASE_URL}/sync${toQuery({ since })}`, {
+      headers: buildHeaders(this._token),
+    });
+    return parseJson(res);
+  },
+
+  async pushSyncChanges(mutations) {
+    const res = await postIdempotent(`${BASE_URL}/sync`, this._token, {
+      mutations,
+    });
+    return parseJson(res);
+  },
 };
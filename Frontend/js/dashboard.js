@@ -68,6 +68,7 @@ const modalDate = document.getElementById("modal-date");
 
 let lineChartInstance = null;
 let currentEditId = null;
+let currentEditVersion = null;
 
 // ===== Helpers =====
 function formatCurrency(n) {
@@ -566,6 +567,7 @@ function openModal(expense) {
   modal.classList.remove("hidden");
   modalTitle.textContent = "Update expense";
   currentEditId = expense?.id ?? null;
+  currentEditVersion = expense?.version ?? null;
 
   modalAmount.value = expense?.amount
     ? formatIdAmountString(expense.amount)
@@ -580,6 +582,7 @@ function closeModal() {
   modal.classList.add("hidden");
   modalForm.reset();
   currentEditId = null;
+  currentEditVersion = null;
 }
 modalClose.onclick = () => closeModal();
 
@@ -611,10 +614,17 @@ modalForm?.addEventListener("submit", async (e) => {
   }
 
   const res = await safeApiCall(
-    API.updateExpense(currentEditId, body),
+    API.updateExpense(currentEditId, body, currentEditVersion),
     "Failed to update expense"
   );
 
+  // Edited elsewhere in the meantime: show the current values so the user
+  // can review and save again.
+  if (res?.status === 412 && res.data?.current) {
+    openModal(res.data.current);
+    return;
+  }
+
   if (res?.ok) {
     // update cache sekali saja
     if (window.clickedDate && window.dailyExpenses) {
@@ -50,42 +50,20 @@ function toLocalDateKey(iso) {
 }
 
 // ===== Natural Language Input Parser =====
+// Parsing dilakukan di server (POST /api/expense/quick) supaya kategori
+// dicocokkan dengan kategori milik user, bukan selalu kategori pertama.
 export async function parseExpenseInput(raw) {
-  const text = String(raw || "")
-    .toLowerCase()
-    .trim();
-
-  // Tangkap angka fleksibel: "rp 25k", "25rb", "25.000", "25,000"
-  const amountMatch = text.match(/(?:rp\s*)?(\d+(?:[.,]\d+)?\s?(k|rb)?)/i);
-
-  let amount = undefined;
-  console.log("Raw input:", raw, "Match:", amountMatch, "Amount:", amount);
-  if (amountMatch) {
-    let rawAmount = amountMatch[1].replace(/[^\d]/g, "");
-    let unit = amountMatch[2]?.toLowerCase();
-
-    if (unit === "k" || unit === "rb") {
-      amount = parseInt(rawAmount, 10) * 1000;
-    } else {
-      amount = parseInt(rawAmount, 10);
-    }
-
-    if (isNaN(amount)) amount = undefined;
-  }
-
-  // Fallback kategori default (ambil kategori pertama)
-  const categoriesRes = await API.getCategories();
-  const categories = safeData(categoriesRes);
-  const defaultCatId =
-    Array.isArray(categories) && categories.length > 0
-      ? categories[0].id
-      : null;
+  const res = await API.quickAddExpense({
+    text: String(raw || "").trim(),
+    tz: Intl.DateTimeFormat().resolvedOptions().timeZone,
+  });
+  const parsed = safeData(res)?.parsed || {};
 
   return {
-    description: raw,
-    amount,
-    category_id: defaultCatId,
-    created_at: new Date().toISOString(),
+    description: parsed.description || raw,
+    amount: parsed.amount ?? undefined,
+    category_id: parsed.category_id ?? null,
+    created_at: parsed.created_at || new Date().toISOString(),
   };
 }
 
@@ -106,7 +84,7 @@ quickInput?.addEventListener("keydown", async (e) => {
   if (!parsed.category_id) {
     toast({
       title: "Invalid",
-      message: "Tidak ada kategori default. Buat minimal 1 kategori dulu.",
+      message: "Kategori tidak dikenali. Sebutkan nama kategori (contoh: makan 25k food).",
       status: "error",
     });
     return;
@@ -448,18 +426,47 @@ window.__reloadDashboard = async function () {
 };
 
 // ===== Saved Filters =====
-export function saveCurrentFilter(name, query) {
-  localStorage.setItem("filter_" + name, JSON.stringify(query));
-  toast({
-    title: "Saved",
-    message: `Filter "${name}" disimpan`,
-    status: "success",
+// Filter disimpan di server (/api/views) supaya ikut ke device lain
+export async function saveCurrentFilter(name, query = {}) {
+  const { sort_by, order, ...filter } = query;
+  const stringFilter = {};
+  Object.entries(filter).forEach(([k, v]) => {
+    if (v === undefined || v === null || v === "") return;
+    stringFilter[k] = String(v);
   });
+
+  const res = await API.addView({
+    name,
+    kind: "expense",
+    filter: stringFilter,
+    sort_by,
+    order,
+  });
+  if (res?.ok) {
+    toast({
+      title: "Saved",
+      message: `Filter "${name}" disimpan`,
+      status: "success",
+    });
+  } else {
+    const msg = res?.raw?.data?.message || res?.raw?.data || "Bad request";
+    toast({ title: "Save failed", message: msg, status: "error" });
+  }
 }
 
-export function applySavedFilter(name) {
-  const q = JSON.parse(localStorage.getItem("filter_" + name) || "{}");
-  window.loadExpenses(q);
+export async function applySavedFilter(name) {
+  const res = await API.getViews();
+  const views = safeData(res);
+  const view = Array.isArray(views) ? views.find((v) => v.name === name) : null;
+  if (!view) {
+    toast({
+      title: "Not found",
+      message: `Filter "${name}" tidak ditemukan`,
+      status: "error",
+    });
+    return;
+  }
+  window.loadExpenses({ view: view.id });
 }
 
 // ===== Masked Mode =====
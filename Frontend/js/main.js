@@ -93,13 +93,34 @@ verifyForm.onsubmit = async (e) => {
   }
 };
 
+// 2FA -> trade the challenge token and a code for the real token
+async function completeTwoFactor(res) {
+  while (res?.data?.two_factor_required) {
+    const code = window.prompt(
+      "Enter the code from your authenticator app or a recovery code"
+    );
+    if (!code) return res;
+    const next = await API.loginTwoFactor({
+      challenge_token: res.data.challenge_token,
+      code: code.trim(),
+    });
+    if (next?.data?.token || next.status !== 401) return next;
+    toast({
+      title: "Invalid code",
+      message: next.data?.message || "Try again",
+      status: "warn",
+    });
+  }
+  return res;
+}
+
 // LOGIN -> username+password
 loginForm.onsubmit = async (e) => {
   e.preventDefault();
   const body = Object.fromEntries(new FormData(e.target).entries());
 
   try {
-    const res = await API.login(body);
+    const res = await completeTwoFactor(await API.login(body));
 
     if (res?.data?.token) {
       localStorage.setItem("token", res.data.token);
@@ -131,3 +152,58 @@ loginForm.onsubmit = async (e) => {
     });
   }
 };
+
+// SSO -> send the browser to the provider
+document.getElementById("sso-login").onclick = async () => {
+  try {
+    const res = await API.ssoAuthorize();
+    if (res?.data?.authorization_url) {
+      window.location.href = res.data.authorization_url;
+    } else {
+      toast({
+        title: "SSO unavailable",
+        message: res.data?.message || res.message || "Single sign-on is not set up",
+        status: "warn",
+      });
+    }
+  } catch (err) {
+    console.error(err);
+    toast({
+      title: "Network Error",
+      message: "Backend unreachable.",
+      status: "error",
+    });
+  }
+};
+
+// SSO callback -> the provider sends the browser back here with ?code&state
+const ssoParams = new URLSearchParams(window.location.search);
+if (ssoParams.get("code") && ssoParams.get("state")) {
+  history.replaceState(null, "", window.location.pathname);
+
+  API.ssoCallback({
+    code: ssoParams.get("code"),
+    state: ssoParams.get("state"),
+  })
+    .then(completeTwoFactor)
+    .then((res) => {
+      if (res?.data?.token) {
+        localStorage.setItem("token", res.data.token);
+        window.location.href = "dashboard.html";
+      } else {
+        toast({
+          title: "SSO sign in failed",
+          message: res.data?.message || res.message || "Please try again",
+          status: "error",
+        });
+      }
+    })
+    .catch((err) => {
+      console.error(err);
+      toast({
+        title: "Network Error",
+        message: "Backend unreachable.",
+        status: "error",
+      });
+    });
+}